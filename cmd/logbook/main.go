@@ -1,20 +1,55 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/clobrano/LogBook/pkg/clipboard"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/editor"
+	"github.com/clobrano/LogBook/pkg/encrypt"
+	"github.com/clobrano/LogBook/pkg/export"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/importer"
 	"github.com/clobrano/LogBook/pkg/journal"
+	"github.com/clobrano/LogBook/pkg/mood"
+	"github.com/clobrano/LogBook/pkg/plugin"
 	"github.com/clobrano/LogBook/pkg/review"
+	"github.com/clobrano/LogBook/pkg/search"
+	"github.com/clobrano/LogBook/pkg/stats"
+	"github.com/clobrano/LogBook/pkg/theme"
+	"github.com/clobrano/LogBook/pkg/weather"
+	"github.com/mattn/go-isatty"
+)
+
+// Build information, set with -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=...",
+// see the Makefile. They are empty in development builds.
+var (
+	version   string
+	commit    string
+	buildDate string
 )
 
 func main() {
+	args, noColor := removeNoColorFlag(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	if noColor {
+		theme.Disable()
+	}
+
 	usr, err := user.Current()
 	if err != nil {
 		fmt.Printf("Error getting current user: %v\n", err)
@@ -36,23 +71,251 @@ Usage:
   logbook <command> [arguments]
 
 Available Commands:
+  init    Create the configuration, asking for the journal directory, the AI command and the date format, and today's journal file.
+          Usage: logbook init (accepts the defaults when the standard input is not a terminal)
   config  Create a default configuration file.
+          Usage: logbook config show (show the fields of the configuration, marking with * the ones differing from the defaults)
+                 logbook config list-templates (show the template fields with their default values and a preview)
+                 logbook config set ai_command <command|auto> (auto uses the first of gemini, claude, ollama, llm and sgpt found)
   help    Display help information for LogBook.
   log     Add an entry to today's journal.
-          Usage: logbook log <your entry text>
+          Usage: logbook log [--prepend-date] [--weather] [--relate-to YYYY-MM-DD] [--time HH:MM] [--tag TAG] [--mood MOOD] <your entry text>
+                 logbook log --from-file <path> (log the content of a text or Markdown file, up to 64KB)
+                 echo "Fixed bug #123" | logbook log (without text, log each line of the standard input, or ask for one)
+                 logbook log --follow (keep running and log each line typed, until Ctrl-D)
+                 logbook log --yesterday "Forgot to log the deploy" (log to yesterday's journal file, with the current time)
+          Options:
+            --prepend-date        Write the date before the entry time, formatted with entry_date_prefix (e.g. "Mon ")
+            --weather             Prepend the current weather from wttr.in, e.g. "🌤️ 22°C" (see weather_location)
+            --relate-to <date>    Link the entry to the daily note of the given YYYY-MM-DD date, and that note back to today
+            --time <HH:MM>        Write the entry with the given time instead of now, keeping today's journal file
+            --yesterday           Write the entry to yesterday's journal file, creating it if missing
+            --date <YYYY-MM-DD>   Write the entry to the journal file of the given date, creating it if missing
+            --tag <tag>           Append "#tag" to the entry, lower case, and to the front matter tags if the file has any; can be repeated (e.g. --tag work --tag golang)
+            --follow              Keep reading entries from the standard input, finalizing the daily file on exit
+            --dry-run             Print the entries as they would be written, with their time and tags, without writing them
+            --journal <name>      Log to the given [[journals]] entry of the configuration (defaults to the first one)
+            --mood <mood>         Append "mood:<mood>" to the entry, or log it alone without text (excited, happy, neutral, tired, stressed, sad or angry)
+            --from-file <path>    Use the file content as the entry, without YAML frontmatter and with a "# Title" as first line
+            --encrypt-summary     Encrypt the summary of the day, keeping the LOG readable (passphrase from $LOGBOOK_PASSPHRASE or prompted)
   review  Perform a review of journal entries for a specific period.
           Usage:
             logbook review week [week number] [year] (defaults to current week/year)
-            logbook review month [month name] [year] (defaults to current month/year)
+            logbook review week --since-last (each week after the last weekly review, up to the current one)
+            logbook review month [month name or number] [year] (defaults to current month/year)
+            logbook review quarter [1-4] [year] (defaults to current quarter/year)
             logbook review year [year] (defaults to current year)
+            logbook review custom <YYYY-MM-DD> <YYYY-MM-DD> (from the first to the second date included)
+          Options:
+            --linked-navigation   Link the previous and next weekly reviews below the title
+            --ai-language <lang>  Write the AI summary in the given language
+            --cross-reference     List the topics mentioned across multiple days in the weekly review
+            --min-mentions <n>    Minimum number of days for --cross-reference (default 2)
+            --extract-learnings   Add the skills and tools learned, extracted by the AI, to the yearly review
+            --entry-graph         Add a chart of the entries per month to the yearly review
+            --best-of             Add the longest, the most referenced and the first entries to the yearly review
+            --top-tags [N]        Add a table of the N most used #hashtags to the yearly review (default 10)
+            --sort-by <order>     Order the daily summaries of the weekly review by date (default), wordcount-desc or wordcount-asc
+            --habits              Add the completion of the configured habits to the monthly review
+            --retro               Write the monthly review summary as a Start/Stop/Continue retrospective (requires AI)
+            --include-reviews     Add the summaries of the existing weekly reviews of the month to the monthly review
+            --mood-timeline       Add the moods of the week to the weekly review (requires the mood section)
+            --productivity        Add the productivity score of the week to the weekly review
+            --insight             Add the key insight of the week, generated by the AI, below the weekly review title
+            --ai-profile <name>   Use the given [ai_profiles.<name>] of the configuration instead of the default AI command
+            --decrypt             Include the encrypted daily summaries (passphrase from $LOGBOOK_PASSPHRASE or prompted)
+            --no-footer           Do not append the total of entries, words and active days to the weekly review
+            --output-format <fmt> Also write the weekly review as html or json, next to the Markdown one (default md)
+            --full-log            Add the LOG entries of each day below its summary in the weekly, monthly and yearly reviews
+            --related             List the days linked with log --relate-to below each daily summary of the weekly review
+            --journal <name>      Review the given [[journals]] entry of the configuration (defaults to the first one)
+  stats   Show statistics about the journal.
+          Usage: logbook stats [year] (journal days, entries, streaks, most active day and words, defaults to current year)
+                 logbook stats --by-project (entries of the current year grouped by [project:name] label)
+                 logbook stats --entry-interval (average minutes between the entries of each day of the current year)
+                 logbook stats --words [day|week|month|year] (words of the LOG chapters of the current period: total, daily average and peak day, defaults to year)
+          Options:
+            --top <N>             With --words, also list the N days with the most words
+  mood    Show the moods recorded with log --mood.
+          Usage: logbook mood report [month name or number] [year] (histogram of the moods of the month, defaults to current month/year)
+  edit    Open the journal file of a day in $EDITOR (or $VISUAL, or nano).
+          Usage: logbook edit [YYYY-MM-DD] (defaults to today, the file must exist; encrypted files are edited decrypted)
+  summary Regenerate the summary of a day, e.g. after logging new entries (typed when AI is disabled).
+          Usage: logbook summary [--if-missing] [YYYY-MM-DD] (defaults to today, --if-missing keeps an existing summary)
+  open    Open the journal file of a day with the default application (xdg-open, open on macOS, start on Windows).
+          Usage: logbook open [YYYY-MM-DD] (defaults to today, the file must exist and not be encrypted)
+  delete  Delete the journal file of a day, after showing its first lines and asking for confirmation.
+          Usage: logbook delete [--force|-f] <YYYY-MM-DD> (--force deletes without asking)
+  import  Copy Markdown notes, e.g. from Obsidian or Notion, into the journal as daily files.
+          Usage: logbook import [--overwrite|--skip] <file|directory|glob> (the date is the "date:" front matter field, or the file modification time)
+  search  Search the summaries and the entries of the days, oldest first.
+          Usage: logbook search [--exact|--regex] [--summary] [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--tag TAG]... <query>
+                 (case-insensitive unless --exact; --summary searches the summaries only, --tag the days using all the tags)
+  tags    List the #tags of the entries, or the entries using a tag.
+          Usage: logbook tags list [--from YYYY-MM-DD] [--to YYYY-MM-DD] (tags with their number of uses)
+                 logbook tags search <tag> [--from YYYY-MM-DD] [--to YYYY-MM-DD]
+  export  Export the journal to other formats.
+          Usage: logbook export json [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--output FILE] (defaults to all days, to stdout)
+                 logbook export html [--output-dir DIR] (a page per day and an index.html, defaults to ./logbook-html)
+  code    Print the code blocks logged in a day.
+          Usage: logbook code [--date YYYY-MM-DD] [--language go] [--copy N] (defaults to today, --copy copies the Nth block to the clipboard)
+  rename-entry  Change the time of an entry, moving it to its chronological position.
+          Usage: logbook rename-entry --date YYYY-MM-DD --from HH:MM --to HH:MM
+  doctor  Check the journal for problems.
+          Usage: logbook doctor --orphaned-reviews [--delete] (reviews of periods without journal files)
+                 logbook doctor --repair (converts the line endings to \n and removes the trailing whitespace of the daily files)
+  version Print the version, commit and build date of LogBook.
+
+Global Options:
+  --no-color  Print without colours, as when the NO_COLOR environment variable is set.
 
 Examples:
+  logbook init
   logbook config
+  logbook config list-templates
+  logbook config set ai_command auto
   logbook log "Started working on the LogBook help command."
+  logbook log --tag work --tag golang "Fixed the parser"
   logbook review week 38 2025
+  logbook review week --cross-reference --min-mentions 3
   logbook review month September 2025
-  logbook review year 2025`)
+  logbook review month 9 2025
+  logbook review year 2025
+  logbook review custom 2025-01-06 2025-01-17
+  logbook stats --by-project
+  logbook code --language go --copy 1
+  logbook doctor --orphaned-reviews`)
+
+			if plugins := plugin.Discover(); len(plugins) > 0 {
+				fmt.Println("\nPlugins (logbook-<command> executables on $PATH):")
+				for _, p := range plugins {
+					fmt.Printf("  %-7s %s\n", p.Name, p.Path)
+				}
+			}
+		case "init":
+			if len(os.Args) > 2 {
+				fmt.Println("Usage: logbook init")
+				os.Exit(1)
+			}
+			wizard := &config.Wizard{
+				Reader:      bufio.NewReader(os.Stdin),
+				Out:         os.Stdout,
+				Interactive: isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()),
+			}
+			_, err = os.Stat(configFilePath)
+			if err == nil {
+				overwrite, err := wizard.Confirm(fmt.Sprintf("Configuration file already exists at: %s, overwrite it?", configFilePath))
+				if err != nil {
+					fmt.Printf("Error reading the answer: %v\n", err)
+					os.Exit(1)
+				}
+				if !overwrite {
+					fmt.Printf("Configuration file left unchanged: %s\n", configFilePath)
+					os.Exit(0)
+				}
+			} else if !os.IsNotExist(err) {
+				fmt.Printf("Error checking config file: %v\n", err)
+				os.Exit(1)
+			}
+
+			newCfg, err := wizard.Run()
+			if err != nil {
+				fmt.Printf("Error creating the configuration: %v\n", err)
+				os.Exit(1)
+			}
+			if err := os.MkdirAll(configDir, 0755); err != nil {
+				fmt.Printf("Error creating config directory %s: %v\n", configDir, err)
+				os.Exit(1)
+			}
+			if err := config.SaveConfig(configFilePath, newCfg); err != nil {
+				fmt.Printf("Error saving configuration: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(theme.Success("Configuration file created at: %s", configFilePath))
+
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			if err := os.MkdirAll(cfg.JournalDir, 0755); err != nil {
+				fmt.Printf("Error creating journal directory %s: %v\n", cfg.JournalDir, err)
+				os.Exit(1)
+			}
+			_, message, err := journal.CreateDailyJournalFile(cfg, time.Now(), cfg.AISummarizer, wizard.Reader)
+			if err != nil {
+				fmt.Printf("Error creating/getting daily journal file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(message)
+			fmt.Println(theme.Muted("Start logging with: logbook log \"My first entry\""))
+			os.Exit(0)
 		case "config":
+			if len(os.Args) > 2 && os.Args[2] == "list-templates" {
+				cfg, err = loadConfig(configFilePath, "")
+				if err != nil {
+					fmt.Printf("Error loading configuration: %v\n", err)
+					os.Exit(1)
+				}
+				printTemplateFields(cfg)
+				os.Exit(0)
+			}
+			if len(os.Args) > 2 && os.Args[2] == "show" {
+				_, err = os.Stat(configFilePath)
+				switch {
+				case err == nil:
+					cfg, err = config.LoadConfig(configFilePath)
+					if err != nil {
+						fmt.Printf("Error loading configuration: %v\n", err)
+						os.Exit(1)
+					}
+					fmt.Printf("Configuration file: %s\n", configFilePath)
+				case os.IsNotExist(err):
+					cfg = config.DefaultConfig()
+					fmt.Printf("Configuration file: %s (not found, showing the defaults)\n", configFilePath)
+				default:
+					fmt.Printf("Error checking config file: %v\n", err)
+					os.Exit(1)
+				}
+				if err := theme.Apply(cfg.ColorTheme); err != nil {
+					fmt.Printf("Error loading configuration: %v\n", err)
+					os.Exit(1)
+				}
+				printConfig(cfg)
+				os.Exit(0)
+			}
+			if len(os.Args) > 2 && os.Args[2] == "set" {
+				if len(os.Args) != 5 || os.Args[3] != "ai_command" {
+					fmt.Println("Usage: logbook config set ai_command <command|auto>")
+					os.Exit(1)
+				}
+				cfg, err = config.LoadConfig(configFilePath)
+				if err != nil {
+					fmt.Printf("Error loading configuration: %v\n", err)
+					os.Exit(1)
+				}
+				if os.Args[4] == "auto" {
+					cfg.AICommand, err = config.AutoDetectAICommand()
+					if err != nil {
+						fmt.Printf("Error detecting the AI command: %v\n", err)
+						os.Exit(1)
+					}
+					cfg.AutoDetectedAI = true
+				} else {
+					cfg.AICommand = os.Args[4]
+					cfg.AutoDetectedAI = false
+				}
+				if err := config.SaveConfig(configFilePath, cfg); err != nil {
+					fmt.Printf("Error saving configuration: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(theme.Success("ai_command set to: %s", cfg.AICommand))
+				if !cfg.AIEnabled {
+					fmt.Println(theme.Warning("AI is disabled, set ai_enabled = true to use it"))
+				}
+				os.Exit(0)
+			}
+
 			usr, err := user.Current()
 			if err != nil {
 				fmt.Printf("Error getting current user: %v\n", err)
@@ -89,151 +352,1463 @@ Examples:
 			fmt.Printf("Default configuration file created at: %s\n", configFilePath)
 			os.Exit(0)
 		case "log":
-			cfg, err = config.LoadConfig(configFilePath)
+			logFlags := flag.NewFlagSet("log", flag.ExitOnError)
+			journalName := logFlags.String("journal", "", "Name of the [[journals]] entry to log to (defaults to the first one)")
+			prependDate := logFlags.Bool("prepend-date", false, "Write the date before the entry time, formatted with entry_date_prefix")
+			withWeather := logFlags.Bool("weather", false, "Prepend the current weather to the entry")
+			relateTo := logFlags.String("relate-to", "", "Link the entry to the daily note of the given YYYY-MM-DD date, and back")
+			fromFile := logFlags.String("from-file", "", "Use the content of a text or Markdown file as the entry")
+			encryptSummary := logFlags.Bool("encrypt-summary", false, "Encrypt the summary of the day, keeping the LOG readable")
+			atTime := logFlags.String("time", "", "Time of the entry, as HH:MM (defaults to now)")
+			var tags stringListFlag
+			logFlags.Var(&tags, "tag", "Append a #tag to the entry, can be repeated")
+			follow := logFlags.Bool("follow", false, "Keep reading entries from the standard input, one per line, until Ctrl-D")
+			yesterday := logFlags.Bool("yesterday", false, "Log to the journal file of yesterday, with the current time")
+			onDate := logFlags.String("date", "", "Log to the journal file of the given YYYY-MM-DD date, with the current time")
+			dryRun := logFlags.Bool("dry-run", false, "Print the entry as it would be written, without writing it")
+			moodName := logFlags.String("mood", "", "Record how you feel: excited, happy, neutral, tired, stressed, sad or angry")
+			args := parseFlags(logFlags, os.Args[2:])
+			cfg, err = loadConfig(configFilePath, *journalName)
 			if err != nil {
 				fmt.Printf("Error loading configuration: %v\n", err)
 				os.Exit(1)
 			}
-			if len(os.Args) < 3 {
-				fmt.Println("Usage: logbook log <entry>")
+			if len(args) > 0 && *fromFile != "" {
+				fmt.Println("Usage: logbook log [--prepend-date] [--weather] [--relate-to YYYY-MM-DD] [--time HH:MM] [--tag TAG] <entry>")
+				fmt.Println("       logbook log [--prepend-date] [--weather] [--relate-to YYYY-MM-DD] [--time HH:MM] [--tag TAG] --from-file <path>")
+				fmt.Println("       logbook log [--prepend-date] [--weather] [--relate-to YYYY-MM-DD] [--time HH:MM] [--tag TAG] < entries.txt")
+				os.Exit(1)
+			}
+			for _, tag := range tags {
+				if _, err := journal.NormalizeTag(tag); err != nil {
+					fmt.Printf("Invalid --tag: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			var entryMood mood.Mood
+			if *moodName != "" {
+				entryMood, err = mood.ParseMood(*moodName)
+				if err != nil {
+					fmt.Printf("Invalid --mood: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if *follow {
+				if len(args) > 0 || *fromFile != "" || *atTime != "" || *relateTo != "" || *moodName != "" || *dryRun || *yesterday || *onDate != "" {
+					fmt.Println("Usage: logbook log --follow [--prepend-date] [--tag TAG]")
+					os.Exit(1)
+				}
+				if isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+					fmt.Println(theme.Muted("Type one entry per line, Ctrl-D to stop."))
+				}
+				err = journal.FollowLog(cfg, os.Stdin, os.Stdout, func() time.Time { return time.Now().In(cfg.Location()) }, journal.AppendOptions{
+					Prepend:     cfg.LogEntryOrder == config.LogEntryOrderPrepend,
+					PrependDate: *prependDate,
+					Tags:        tags,
+				})
+				if err != nil {
+					fmt.Printf("Error following the log: %v\n", err)
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+			if *relateTo != "" {
+				if _, err := time.Parse("2006-01-02", *relateTo); err != nil {
+					fmt.Printf("Invalid --relate-to date %q, expected YYYY-MM-DD\n", *relateTo)
+					os.Exit(1)
+				}
+			}
+			now := time.Now().In(cfg.Location())
+			entryTime := now
+			if *atTime != "" {
+				entryTime, err = journal.AtClock(now, *atTime)
+				if err != nil {
+					fmt.Printf("Invalid --time: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			// The day of the journal file, the entries keep the current time
+			fileTime := now
+			switch {
+			case *yesterday && *onDate != "":
+				fmt.Println("Use either --yesterday or --date, not both")
 				os.Exit(1)
+			case *yesterday:
+				fileTime = now.AddDate(0, 0, -1)
+			case *onDate != "":
+				fileTime, err = journal.ParseDay(cfg, *onDate)
+				if err != nil {
+					fmt.Printf("Invalid --date: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			entries := []string{strings.Join(args, " ")}
+			switch {
+			case *fromFile != "":
+				entry, err := journal.ReadEntryFromFile(*fromFile)
+				if err != nil {
+					fmt.Printf("Error reading entry: %v\n", err)
+					os.Exit(1)
+				}
+				entries = []string{entry}
+			case len(args) == 0 && entryMood != "":
+				entries = nil // Only the mood is recorded
+			case len(args) == 0:
+				// Without text the entries are typed, or piped one per line
+				interactive := isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+				entries, err = journal.ReadEntries(os.Stdin, interactive, os.Stdout)
+				if err != nil {
+					fmt.Printf("Error reading entry: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if entryMood != "" {
+				for i := range entries {
+					entries[i] += " " + mood.Marker(entryMood)
+				}
+			}
+			if *withWeather || (config.SectionEnabled(cfg, config.SectionWeather) && cfg.WeatherAutoPrefix) {
+				info, err := weather.Fetch(cfg.WeatherLocation)
+				if err != nil {
+					fmt.Println(theme.Warning("Logging without weather: %v", err))
+				} else {
+					for i := range entries {
+						entries[i] = info.String() + " " + entries[i]
+					}
+				}
+			}
+
+			appendOptions := journal.AppendOptions{
+				Prepend:     cfg.LogEntryOrder == config.LogEntryOrderPrepend,
+				PrependDate: *prependDate,
+				Tags:        tags,
+			}
+			if *dryRun {
+				// Nothing is written, not even the daily file
+				if len(entries) == 0 && entryMood != "" {
+					entries = []string{mood.Marker(entryMood)}
+				}
+				for _, entry := range entries {
+					line, err := journal.FormatLogEntry(cfg, entry, entryTime, appendOptions)
+					if err != nil {
+						fmt.Printf("Error formatting entry: %v\n", err)
+						os.Exit(1)
+					}
+					fmt.Println(line)
+				}
+				os.Exit(0)
 			}
-			entry := strings.Join(os.Args[2:], " ")
 
-			now := time.Now()
-			journalFilePath, message, err := journal.CreateDailyJournalFile(cfg, now, cfg.AISummarizer, os.Stdin)
+			journalFilePath, message, err := journal.CreateDailyJournalFile(cfg, fileTime, cfg.AISummarizer, os.Stdin)
 			if err != nil {
 				fmt.Printf("Error creating/getting daily journal file: %v\n", err)
 				os.Exit(1)
 			}
 			fmt.Println(message)
+			hookEnv := map[string]string{
+				config.HookEnvFile: journalFilePath,
+				config.HookEnvDate: journal.EffectiveDate(fileTime, cfg.DayBoundaryHour).Format("2006-01-02"),
+			}
+			runHook("pre_log", cfg.Hooks.PreLog, hookEnv)
 
-			err = journal.AppendToLog(cfg, journalFilePath, entry, now)
-			if err != nil {
-				fmt.Printf("Error appending to log: %v\n", err)
+			for _, entry := range entries {
+				err = journal.AppendToLogWithOptions(cfg, journalFilePath, entry, entryTime, appendOptions)
+				if err != nil {
+					fmt.Printf("Error appending to log: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println("Entry added to log.")
+			}
+			if err := journal.AddFrontMatterTags(journalFilePath, tags); err != nil {
+				fmt.Printf("Error updating the front matter tags: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Println("Entry added to log.")
+			if len(entries) == 0 && entryMood != "" {
+				if err := mood.RecordMood(cfg, journalFilePath, entryMood, entryTime); err != nil {
+					fmt.Printf("Error recording mood: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println("Mood added to log.")
+			}
+
+			if *relateTo != "" {
+				if err := journal.AddRelation(cfg, journalFilePath, *relateTo); err != nil {
+					fmt.Printf("Error relating entry to %s: %v\n", *relateTo, err)
+					os.Exit(1)
+				}
+			}
 
 			// Finalize the daily file: embed one-line notes
-			err = journal.FinalizeDailyFile(cfg, journalFilePath, now)
+			err = journal.FinalizeDailyFile(cfg, journalFilePath, journal.EffectiveDate(fileTime, cfg.DayBoundaryHour))
 			if err != nil {
 				fmt.Printf("Error finalizing daily file: %v\n", err)
 				os.Exit(1)
 			}
+
+			if *encryptSummary || cfg.EncryptSummary {
+				passphrase, err := readPassphrase(os.Stdin)
+				if err != nil {
+					fmt.Printf("Error reading passphrase: %v\n", err)
+					os.Exit(1)
+				}
+				if err := journal.EncryptSummary(journalFilePath, passphrase); err != nil {
+					fmt.Printf("Error encrypting summary: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			runHook("post_log", cfg.Hooks.PostLog, hookEnv)
 		case "review":
-			cfg, err = config.LoadConfig(configFilePath)
+			if len(os.Args) < 3 {
+				fmt.Println("Usage: logbook review <week|month|quarter|year|custom> [args]")
+				os.Exit(1)
+			}
+			subCommand := os.Args[2]
+
+			reviewFlags := flag.NewFlagSet("review "+subCommand, flag.ExitOnError)
+			journalName := reviewFlags.String("journal", "", "Name of the [[journals]] entry to review (defaults to the first one)")
+			sinceLast := reviewFlags.Bool("since-last", false, "Review each week after the last reviewed one, up to the current week")
+			linkedNavigation := reviewFlags.Bool("linked-navigation", false, "Link the previous and next weekly reviews below the title")
+			aiLanguage := reviewFlags.String("ai-language", "", "Language of the AI generated summary (defaults to default_ai_language)")
+			crossReference := reviewFlags.Bool("cross-reference", false, "List the topics mentioned across multiple days in the weekly review")
+			minMentions := reviewFlags.Int("min-mentions", 2, "Minimum number of days a topic must be mentioned in to be cross referenced")
+			extractLearnings := reviewFlags.Bool("extract-learnings", false, "Add the skills and tools learned, extracted by the AI, to the yearly review")
+			entryGraph := reviewFlags.Bool("entry-graph", false, "Add a chart of the entries per month to the yearly review")
+			bestOf := reviewFlags.Bool("best-of", false, "Add the longest, the most referenced and the first entries to the yearly review")
+			topTags := &optionalIntFlag{defaultValue: 10}
+			reviewFlags.Var(topTags, "top-tags", "Add a table of the N most used #hashtags to the yearly review (default 10)")
+			sortBy := reviewFlags.String("sort-by", review.SortByDate, "Order of the daily summaries of the weekly review: date, wordcount-desc or wordcount-asc")
+			habits := reviewFlags.Bool("habits", false, "Add the completion of the configured habits to the monthly review")
+			retroFormat := reviewFlags.Bool("retro", false, "Write the monthly review summary as a Start/Stop/Continue retrospective")
+			includeReviews := reviewFlags.Bool("include-reviews", false, "Add the summaries of the existing weekly reviews of the month to the monthly review")
+			moodTimeline := reviewFlags.Bool("mood-timeline", false, "Add the moods of the week to the weekly review (requires the mood section)")
+			productivity := reviewFlags.Bool("productivity", false, "Add the productivity score of the week to the weekly review")
+			insight := reviewFlags.Bool("insight", false, "Add the key insight of the week, generated by the AI, below the weekly review title")
+			aiProfile := reviewFlags.String("ai-profile", "", "Name of the AI profile to use (defaults to default_ai_profile)")
+			decrypt := reviewFlags.Bool("decrypt", false, "Include the encrypted daily summaries")
+			noFooter := reviewFlags.Bool("no-footer", false, "Do not append the total of entries, words and active days to the weekly review")
+			outputFormat := reviewFlags.String("output-format", review.OutputFormatMarkdown, "Format of the weekly review: md, html or json")
+			fullLog := reviewFlags.Bool("full-log", false, "Add the LOG of each day below its summary in the weekly, monthly and yearly reviews")
+			related := reviewFlags.Bool("related", false, "List the days linked with log --relate-to below each daily summary of the weekly review")
+			args := parseFlags(reviewFlags, os.Args[3:])
+			if *sinceLast && subCommand != "week" {
+				fmt.Println("--since-last is only supported by review week")
+				os.Exit(1)
+			}
+			if *includeReviews && subCommand != "month" {
+				fmt.Println("--include-reviews is only supported by review month")
+				os.Exit(1)
+			}
+			cfg, err = loadConfig(configFilePath, *journalName)
 			if err != nil {
 				fmt.Printf("Error loading configuration: %v\n", err)
 				os.Exit(1)
 			}
-			if len(os.Args) < 3 {
-				fmt.Println("Usage: logbook review <week|month|year> [args]")
-				os.Exit(1)
+
+			var passphrase string
+			if *decrypt || cfg.EncryptSummary {
+				passphrase, err = readPassphrase(os.Stdin)
+				if err != nil {
+					fmt.Printf("Error reading passphrase: %v\n", err)
+					os.Exit(1)
+				}
+				cfg.SummaryPassphrase = passphrase
 			}
-			subCommand := os.Args[2]
+
+			opts := review.DefaultReviewOptions()
+			opts.LinkedNavigation = *linkedNavigation
+			opts.AILanguage = *aiLanguage
+			opts.SortDailySummariesBy = *sortBy
+			opts.IncludeEntryGraph = *entryGraph
+			opts.ExtractLearnings = *extractLearnings
+			opts.BestOf = *bestOf
+			opts.IncludeTopTags = topTags.set
+			opts.TopTagsCount = topTags.value
+			opts.CrossReference = *crossReference
+			opts.MinMentions = *minMentions
+			opts.RetroFormat = *retroFormat
+			opts.IncludeHabits = *habits
+			opts.IncludeMoodTimeline = *moodTimeline
+			opts.ShowProductivityScore = *productivity
+			opts.IncludeInsight = *insight
+			opts.AIProfile = *aiProfile
+			opts.PassPhrase = passphrase
+			opts.OutputFormat = *outputFormat
+			opts.IncludeFullLog = *fullLog
+			opts.IncludeWeeklyReviews = *includeReviews
+			opts.IncludeRelated = *related
+			if *noFooter {
+				opts.IncludeFooter = false
+			}
+
+			// The review hooks get the directory of the reviews
+			hookEnv := map[string]string{
+				config.HookEnvFile: cfg.ReviewDirectory(),
+				config.HookEnvDate: time.Now().In(cfg.Location()).Format("2006-01-02"),
+			}
+			runHook("pre_review", cfg.Hooks.PreReview, hookEnv)
+
 			switch subCommand {
 			case "week":
-				now := time.Now()
+				now := time.Now().In(cfg.Location())
 				currentYear, currentWeek := now.ISOWeek()
 
+				if *sinceLast {
+					if len(args) > 0 {
+						fmt.Println("Usage: logbook review week --since-last")
+						os.Exit(1)
+					}
+					lastWeek, lastYear, err := review.FindLastReviewedWeek(cfg)
+					if err != nil {
+						fmt.Printf("Error finding the last weekly review: %v\n", err)
+						os.Exit(1)
+					}
+					weeks := review.WeeksAfter(lastWeek, lastYear, now)
+					if len(weeks) == 0 {
+						fmt.Printf("Week %d of %d is already reviewed.\n", lastWeek, lastYear)
+					}
+					for _, w := range weeks {
+						result, err := review.ReviewWeek(cfg, w.Week, w.Year, cfg.AISummarizer, os.Stdin, opts)
+						if err != nil {
+							fmt.Printf("Error generating weekly review: %v\n", err)
+							os.Exit(1)
+						}
+						fmt.Println(result)
+					}
+					break
+				}
+
 				week := currentWeek
 				year := currentYear
 
-				if len(os.Args) >= 4 {
-					parsedWeek, err := strconv.Atoi(os.Args[3])
+				if len(args) >= 1 {
+					parsedWeek, err := strconv.Atoi(args[0])
 					if err != nil {
-						fmt.Println("Invalid week number:", os.Args[3])
+						fmt.Println("Invalid week number:", args[0])
 						os.Exit(1)
 					}
 					week = parsedWeek
 				}
-				if len(os.Args) >= 5 {
-					parsedYear, err := strconv.Atoi(os.Args[4])
+				if len(args) >= 2 {
+					parsedYear, err := strconv.Atoi(args[1])
 					if err != nil {
-						fmt.Println("Invalid year:", os.Args[4])
+						fmt.Println("Invalid year:", args[1])
 						os.Exit(1)
 					}
 					year = parsedYear
 				}
 
 				// If only 'logbook review week' is called, use current week and year
-				if len(os.Args) == 3 {
+				if len(args) == 0 {
 					fmt.Printf("No week number or year provided. Defaulting to current week (%d) and year (%d).\n", week, year)
 				}
 
-				result, err := review.ReviewWeek(cfg, week, year, cfg.AISummarizer, os.Stdin)
+				result, err := review.ReviewWeek(cfg, week, year, cfg.AISummarizer, os.Stdin, opts)
 				if err != nil {
 					fmt.Printf("Error generating weekly review: %v\n", err)
 					os.Exit(1)
 				}
 				fmt.Println(result)
 			case "month":
-				now := time.Now()
+				now := time.Now().In(cfg.Location())
 				currentMonth := now.Month().String()
 				currentYear := now.Year()
 
 				month := currentMonth
 				year := currentYear
 
-				if len(os.Args) >= 4 {
-					month = os.Args[3]
+				if len(args) >= 1 {
+					month = args[0]
 				}
-				if len(os.Args) >= 5 {
-					parsedYear, err := strconv.Atoi(os.Args[4])
+				if len(args) >= 2 {
+					parsedYear, err := strconv.Atoi(args[1])
 					if err != nil {
-						fmt.Println("Invalid year:", os.Args[4])
+						fmt.Println("Invalid year:", args[1])
 						os.Exit(1)
 					}
 					year = parsedYear
 				}
 
 				// If only 'logbook review month' is called, use current month and year
-				if len(os.Args) == 3 {
+				if len(args) == 0 {
 					fmt.Printf("No month or year provided. Defaulting to current month (%s) and year (%d).\n", month, year)
 				}
 
-				result, err := review.ReviewMonth(cfg, month, year, cfg.AISummarizer, os.Stdin)
+				result, err := review.ReviewMonth(cfg, month, year, cfg.AISummarizer, os.Stdin, opts)
 				if err != nil {
 					fmt.Printf("Error generating monthly review: %v\n", err)
 					os.Exit(1)
 				}
 				fmt.Println(result)
+			case "quarter":
+				now := time.Now().In(cfg.Location())
+				quarter := (int(now.Month())-1)/3 + 1
+				year := now.Year()
+
+				if len(args) >= 1 {
+					parsedQuarter, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(args[0]), "Q"))
+					if err != nil {
+						fmt.Println("Invalid quarter:", args[0])
+						os.Exit(1)
+					}
+					quarter = parsedQuarter
+				}
+				if len(args) >= 2 {
+					parsedYear, err := strconv.Atoi(args[1])
+					if err != nil {
+						fmt.Println("Invalid year:", args[1])
+						os.Exit(1)
+					}
+					year = parsedYear
+				}
+
+				// If only 'logbook review quarter' is called, use current quarter and year
+				if len(args) == 0 {
+					fmt.Printf("No quarter or year provided. Defaulting to current quarter (Q%d) and year (%d).\n", quarter, year)
+				}
+
+				result, err := review.ReviewQuarter(cfg, quarter, year, cfg.AISummarizer, os.Stdin, opts)
+				if err != nil {
+					fmt.Printf("Error generating quarterly review: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(result)
 			case "year":
-				now := time.Now()
+				now := time.Now().In(cfg.Location())
 				currentYear := now.Year()
 
 				year := currentYear
 
-				if len(os.Args) >= 4 {
-					parsedYear, err := strconv.Atoi(os.Args[3])
+				if len(args) >= 1 {
+					parsedYear, err := strconv.Atoi(args[0])
 					if err != nil {
-						fmt.Println("Invalid year:", os.Args[3])
+						fmt.Println("Invalid year:", args[0])
 						os.Exit(1)
 					}
 					year = parsedYear
 				}
 
 				// If only 'logbook review year' is called, use current year
-				if len(os.Args) == 3 {
+				if len(args) == 0 {
 					fmt.Printf("No year provided. Defaulting to current year (%d).\n", year)
 				}
 
-				result, err := review.ReviewYear(cfg, year, cfg.AISummarizer, os.Stdin)
+				result, err := review.ReviewYear(cfg, year, cfg.AISummarizer, os.Stdin, opts)
 				if err != nil {
 					fmt.Printf("Error generating yearly review: %v\n", err)
 					os.Exit(1)
 				}
 				fmt.Println(result)
+			case "custom":
+				if len(args) != 2 {
+					fmt.Println("Usage: logbook review custom <YYYY-MM-DD> <YYYY-MM-DD>")
+					os.Exit(1)
+				}
+				startDate, err := time.Parse("2006-01-02", args[0])
+				if err != nil {
+					fmt.Printf("Invalid start date %q, expected YYYY-MM-DD\n", args[0])
+					os.Exit(1)
+				}
+				endDate, err := time.Parse("2006-01-02", args[1])
+				if err != nil {
+					fmt.Printf("Invalid end date %q, expected YYYY-MM-DD\n", args[1])
+					os.Exit(1)
+				}
+
+				result, err := review.ReviewCustomRange(cfg, startDate, endDate, cfg.AISummarizer, os.Stdin, opts)
+				if err != nil {
+					fmt.Printf("Error generating custom review: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(result)
 			default:
 				fmt.Println("Unknown review subcommand. Use 'logbook review help' for more information.")
 				os.Exit(1)
 			}
-		default:
-			fmt.Println("Unknown command. Use 'logbook help' for more information.")
-			os.Exit(1)
-		}
-	} else {
-		fmt.Println("Welcome to LogBook! Use 'logbook help' for more information.")
+			runHook("post_review", cfg.Hooks.PostReview, hookEnv)
+		case "stats":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			statsFlags := flag.NewFlagSet("stats", flag.ExitOnError)
+			byProject := statsFlags.Bool("by-project", false, "Group the entries of the current year by project label")
+			entryInterval := statsFlags.Bool("entry-interval", false, "Show the average time between the entries of each day of the current year")
+			words := statsFlags.Bool("words", false, "Show the words written in the current day, week, month or year")
+			top := statsFlags.Int("top", 0, "With --words, list the N days with the most words")
+			args := parseFlags(statsFlags, os.Args[2:])
+
+			now := time.Now().In(cfg.Location())
+			if *words || *top > 0 {
+				if *byProject || *entryInterval || len(args) > 1 || *top < 0 {
+					fmt.Println("Usage: logbook stats --words [day|week|month|year] [--top N]")
+					os.Exit(1)
+				}
+				period := stats.PeriodYear
+				if len(args) == 1 {
+					period = args[0]
+				}
+				start, end, err := stats.PeriodRange(period, journal.EffectiveDate(now, cfg.DayBoundaryHour))
+				if err != nil {
+					fmt.Printf("Invalid period: %v\n", err)
+					os.Exit(1)
+				}
+				wordStat, err := stats.WordStats(cfg, start, end)
+				if err != nil {
+					fmt.Printf("Error computing word statistics: %v\n", err)
+					os.Exit(1)
+				}
+				peak := "-"
+				if len(wordStat.Days) > 0 {
+					peak = fmt.Sprintf("%s (%d words)", wordStat.Peak.Date.Format("2006-01-02"), wordStat.Peak.Words)
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintf(w, "WORDS\t%s to %s\n", start.Format("2006-01-02"), end.Format("2006-01-02"))
+				fmt.Fprintf(w, "Total\t%d\n", wordStat.Total)
+				fmt.Fprintf(w, "Daily average\t%.1f\n", wordStat.DailyAverage)
+				fmt.Fprintf(w, "Peak day\t%s\n", peak)
+				if *top > 0 && len(wordStat.Days) > 0 {
+					fmt.Fprintln(w)
+					fmt.Fprintln(w, "DATE\tWORDS")
+					for _, day := range wordStat.TopDays(*top) {
+						fmt.Fprintf(w, "%s\t%d\n", day.Date.Format("2006-01-02"), day.Words)
+					}
+				}
+				w.Flush()
+				os.Exit(0)
+			}
+			if !*byProject && !*entryInterval {
+				year := now.Year()
+				if len(args) >= 1 {
+					year, err = strconv.Atoi(args[0])
+					if err != nil {
+						fmt.Println("Invalid year:", args[0])
+						os.Exit(1)
+					}
+				}
+				activity, err := stats.ComputeStats(cfg, year)
+				if err != nil {
+					fmt.Printf("Error computing statistics: %v\n", err)
+					os.Exit(1)
+				}
+				mostActive := "-"
+				if activity.TotalEntries > 0 {
+					mostActive = activity.MostActiveWeekday.String()
+				}
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintf(w, "STATISTICS\t%d\n", activity.Year)
+				fmt.Fprintf(w, "Journal days\t%d\n", activity.JournalDays)
+				fmt.Fprintf(w, "Entries\t%d\n", activity.TotalEntries)
+				fmt.Fprintf(w, "Average entries per day\t%.1f\n", activity.AverageEntries)
+				fmt.Fprintf(w, "Longest streak\t%d days\n", activity.LongestStreak)
+				fmt.Fprintf(w, "Current streak\t%d days\n", activity.CurrentStreak)
+				fmt.Fprintf(w, "Most active day\t%s\n", mostActive)
+				fmt.Fprintf(w, "Words\t%d\n", activity.WordCount)
+				w.Flush()
+				os.Exit(0)
+			}
+
+			startDate := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+
+			if *entryInterval {
+				averages, err := stats.AverageEntryInterval(cfg, startDate, now)
+				if err != nil {
+					fmt.Printf("Error computing entry intervals: %v\n", err)
+					os.Exit(1)
+				}
+				if len(averages) == 0 {
+					fmt.Println("No entries found.")
+					os.Exit(0)
+				}
+				overall, err := stats.OverallAverageInterval(cfg, startDate, now)
+				if err != nil {
+					fmt.Printf("Error computing entry intervals: %v\n", err)
+					os.Exit(1)
+				}
+
+				dates := make([]string, 0, len(averages))
+				for date := range averages {
+					dates = append(dates, date)
+				}
+				sort.Strings(dates)
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "DATE\tAVERAGE INTERVAL (MIN)")
+				for _, date := range dates {
+					fmt.Fprintf(w, "%s\t%.0f\n", date, averages[date].Minutes())
+				}
+				fmt.Fprintf(w, "Overall\t%.0f\n", overall.Minutes())
+				w.Flush()
+				os.Exit(0)
+			}
+
+			projects, err := stats.ProjectStats(cfg, startDate, now)
+			if err != nil {
+				fmt.Printf("Error computing project statistics: %v\n", err)
+				os.Exit(1)
+			}
+			if len(projects) == 0 {
+				fmt.Println("No project entries found.")
+				os.Exit(0)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROJECT\tENTRIES\tWORDS\tDAYS\tFIRST ENTRY\tLAST ENTRY")
+			for _, name := range stats.SortProjectsByEntryCount(projects) {
+				p := projects[name]
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", name, p.EntryCount, p.WordCount, p.ActiveDays,
+					p.FirstEntry.Format("2006-01-02 15:04"), p.LastEntry.Format("2006-01-02 15:04"))
+			}
+			w.Flush()
+		case "mood":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			if len(os.Args) < 3 || os.Args[2] != "report" || len(os.Args) > 5 {
+				fmt.Println("Usage: logbook mood report [month name or number] [year]")
+				os.Exit(1)
+			}
+
+			now := time.Now().In(cfg.Location())
+			month, year := now.Month(), now.Year()
+			if len(os.Args) > 3 {
+				month, err = review.ParseMonth(os.Args[3])
+				if err != nil {
+					fmt.Printf("Invalid month: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if len(os.Args) > 4 {
+				year, err = strconv.Atoi(os.Args[4])
+				if err != nil {
+					fmt.Println("Invalid year:", os.Args[4])
+					os.Exit(1)
+				}
+			}
+
+			startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+			moods, err := mood.ExtractMoods(cfg, startDate, startDate.AddDate(0, 1, -1))
+			if err != nil {
+				fmt.Printf("Error reading moods: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Moods of %s %d\n\n", month, year)
+			if len(moods) == 0 {
+				fmt.Println(theme.Muted("No moods recorded, use logbook log --mood <mood>."))
+				os.Exit(0)
+			}
+			fmt.Print(mood.FormatHistogram(mood.Histogram(moods)))
+		case "edit":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			date := journal.EffectiveDate(time.Now().In(cfg.Location()), cfg.DayBoundaryHour)
+			if len(os.Args) > 3 {
+				fmt.Println("Usage: logbook edit [YYYY-MM-DD]")
+				os.Exit(1)
+			}
+			if len(os.Args) == 3 {
+				date, err = time.Parse("2006-01-02", os.Args[2])
+				if err != nil {
+					fmt.Printf("Invalid date %q, expected YYYY-MM-DD\n", os.Args[2])
+					os.Exit(1)
+				}
+			}
+			filePath, err := journal.DailyFilePath(cfg, date)
+			if err != nil {
+				fmt.Printf("Error getting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			if _, err := os.Stat(filePath); err != nil {
+				fmt.Printf("No journal file for %s: %s\n", date.Format("2006-01-02"), filePath)
+				os.Exit(1)
+			}
+			if err := editor.Open(filePath); err != nil {
+				fmt.Printf("Error opening the editor: %v\n", err)
+				os.Exit(1)
+			}
+		case "open":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			date := journal.EffectiveDate(time.Now().In(cfg.Location()), cfg.DayBoundaryHour)
+			if len(os.Args) > 3 {
+				fmt.Println("Usage: logbook open [YYYY-MM-DD]")
+				os.Exit(1)
+			}
+			if len(os.Args) == 3 {
+				date, err = time.Parse("2006-01-02", os.Args[2])
+				if err != nil {
+					fmt.Printf("Invalid date %q, expected YYYY-MM-DD\n", os.Args[2])
+					os.Exit(1)
+				}
+			}
+			if err := journal.OpenFile(cfg, date); err != nil {
+				fmt.Printf("Error opening the journal file: %v\n", err)
+				os.Exit(1)
+			}
+		case "summary":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			summaryFlags := flag.NewFlagSet("summary", flag.ExitOnError)
+			ifMissing := summaryFlags.Bool("if-missing", false, "Only write the summary if the day has none")
+			args := parseFlags(summaryFlags, os.Args[2:])
+			if len(args) > 1 {
+				fmt.Println("Usage: logbook summary [--if-missing] [YYYY-MM-DD]")
+				os.Exit(1)
+			}
+			date := journal.EffectiveDate(time.Now().In(cfg.Location()), cfg.DayBoundaryHour)
+			if len(args) == 1 {
+				date, err = time.Parse("2006-01-02", args[0])
+				if err != nil {
+					fmt.Printf("Invalid date %q, expected YYYY-MM-DD\n", args[0])
+					os.Exit(1)
+				}
+			}
+			filePath, err := journal.DailyFilePath(cfg, date)
+			if err != nil {
+				fmt.Printf("Error getting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			if _, err := os.Stat(filePath); err != nil {
+				fmt.Printf("No journal file for %s: %s does not exist\n", date.Format("2006-01-02"), filePath)
+				os.Exit(1)
+			}
+			if err := journal.RegenerateSummary(context.Background(), cfg, filePath, cfg.AIPrompt, cfg.AISummarizer, os.Stdin, !*ifMissing); err != nil {
+				fmt.Printf("Error writing the summary: %v\n", err)
+				os.Exit(1)
+			}
+			summary, err := journal.ExtractSummary(filePath)
+			if err != nil {
+				fmt.Printf("Error reading the summary: %v\n", err)
+				os.Exit(1)
+			}
+			if summary == "" {
+				fmt.Println(theme.Warning("%s has no summary", filePath))
+				os.Exit(0)
+			}
+			fmt.Println(theme.Success("Summary of %s: %s", date.Format("2006-01-02"), summary))
+		case "delete":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			deleteFlags := flag.NewFlagSet("delete", flag.ExitOnError)
+			force := deleteFlags.Bool("force", false, "Delete without asking for confirmation")
+			deleteFlags.BoolVar(force, "f", false, "Shorthand for --force")
+			args := parseFlags(deleteFlags, os.Args[2:])
+			if len(args) != 1 {
+				fmt.Println("Usage: logbook delete [--force] <YYYY-MM-DD>")
+				os.Exit(1)
+			}
+			date, err := time.Parse("2006-01-02", args[0])
+			if err != nil {
+				fmt.Printf("Invalid date %q, expected YYYY-MM-DD\n", args[0])
+				os.Exit(1)
+			}
+			filePath, err := journal.DailyFilePath(cfg, date)
+			if err != nil {
+				fmt.Printf("Error getting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			if _, err := os.Stat(filePath); err != nil {
+				fmt.Printf("No journal file for %s: %s\n", date.Format("2006-01-02"), filePath)
+				os.Exit(1)
+			}
+			if !*force {
+				confirmed, err := journal.ConfirmDelete(filePath, os.Stdin, os.Stderr)
+				if err != nil {
+					fmt.Printf("Error asking for confirmation: %v\n", err)
+					os.Exit(1)
+				}
+				if !confirmed {
+					fmt.Println(theme.Muted("Nothing deleted."))
+					os.Exit(0)
+				}
+			}
+			if err := journal.DeleteDailyFile(cfg, date); err != nil {
+				fmt.Printf("Error deleting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(theme.Success("Deleted %s", filePath))
+		case "import":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			importFlags := flag.NewFlagSet("import", flag.ExitOnError)
+			overwrite := importFlags.Bool("overwrite", false, "Replace the journal files of the same date")
+			skip := importFlags.Bool("skip", false, "Keep the journal files of the same date and skip the notes")
+			args := parseFlags(importFlags, os.Args[2:])
+			if len(args) == 0 || (*overwrite && *skip) {
+				fmt.Println("Usage: logbook import [--overwrite|--skip] <file|directory|glob>...")
+				os.Exit(1)
+			}
+			strategy := importer.ConflictFail
+			if *overwrite {
+				strategy = importer.ConflictOverwrite
+			} else if *skip {
+				strategy = importer.ConflictSkip
+			}
+
+			var files []string
+			for _, arg := range args {
+				matches, err := importer.MarkdownFiles(arg)
+				if err != nil {
+					fmt.Printf("Error listing the notes: %v\n", err)
+					os.Exit(1)
+				}
+				files = append(files, matches...)
+			}
+			imported, failed := 0, 0
+			for i, err := range importer.ImportFiles(cfg, files, strategy) {
+				file := files[i]
+				switch {
+				case errors.Is(err, importer.ErrSkipped):
+					fmt.Println(theme.Muted("%s: %v", file, err))
+				case errors.Is(err, importer.ErrFileExists):
+					fmt.Println(theme.Warning("%v (use --overwrite or --skip)", err))
+					failed++
+				case err != nil:
+					fmt.Println(theme.Warning("%v", err))
+					failed++
+				default:
+					fmt.Printf("Imported %s\n", file)
+					imported++
+				}
+			}
+			fmt.Println(theme.Success("Imported %d of %d notes.", imported, len(files)))
+			if failed > 0 {
+				os.Exit(1)
+			}
+		case "search":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			searchFlags := flag.NewFlagSet("search", flag.ExitOnError)
+			exact := searchFlags.Bool("exact", false, "Case-sensitive search")
+			regex := searchFlags.Bool("regex", false, "The query is a regular expression")
+			summaryOnly := searchFlags.Bool("summary", false, "Only search the summaries")
+			fromFlag := searchFlags.String("from", "", "First day, as YYYY-MM-DD")
+			toFlag := searchFlags.String("to", "", "Last day, as YYYY-MM-DD")
+			var tags stringListFlag
+			searchFlags.Var(&tags, "tag", "Only search the days using the #tag, can be repeated")
+			args := parseFlags(searchFlags, os.Args[2:])
+			if len(args) == 0 {
+				fmt.Println("Usage: logbook search [--exact|--regex] [--summary] [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--tag TAG]... <query>")
+				os.Exit(1)
+			}
+
+			opts := search.SearchOptions{Query: strings.Join(args, " "), Exact: *exact, Regex: *regex, SummaryOnly: *summaryOnly, Tags: tags}
+			if *fromFlag != "" {
+				if opts.From, err = time.Parse("2006-01-02", *fromFlag); err != nil {
+					fmt.Printf("Invalid --from %q, expected YYYY-MM-DD\n", *fromFlag)
+					os.Exit(1)
+				}
+			}
+			if *toFlag != "" {
+				if opts.To, err = time.Parse("2006-01-02", *toFlag); err != nil {
+					fmt.Printf("Invalid --to %q, expected YYYY-MM-DD\n", *toFlag)
+					os.Exit(1)
+				}
+			}
+			results, err := search.Search(cfg, opts)
+			if err != nil {
+				fmt.Printf("Error searching entries: %v\n", err)
+				os.Exit(1)
+			}
+			if len(results) == 0 {
+				fmt.Println("No entries found.")
+				os.Exit(0)
+			}
+			for _, result := range results {
+				fmt.Printf("%s %s\n", theme.Muted("%s:%d", result.Date.Format("2006-01-02"), result.LineNumber), result.Line)
+			}
+		case "tags":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			if len(os.Args) < 3 || (os.Args[2] != "list" && os.Args[2] != "search") {
+				fmt.Println("Usage: logbook tags <list|search <tag>> [--from YYYY-MM-DD] [--to YYYY-MM-DD]")
+				os.Exit(1)
+			}
+			tagsFlags := flag.NewFlagSet("tags "+os.Args[2], flag.ExitOnError)
+			fromFlag := tagsFlags.String("from", "", "First day, as YYYY-MM-DD")
+			toFlag := tagsFlags.String("to", "", "Last day, as YYYY-MM-DD")
+			args := parseFlags(tagsFlags, os.Args[3:])
+
+			var from, to time.Time
+			if *fromFlag != "" {
+				if from, err = time.Parse("2006-01-02", *fromFlag); err != nil {
+					fmt.Printf("Invalid --from %q, expected YYYY-MM-DD\n", *fromFlag)
+					os.Exit(1)
+				}
+			}
+			if *toFlag != "" {
+				if to, err = time.Parse("2006-01-02", *toFlag); err != nil {
+					fmt.Printf("Invalid --to %q, expected YYYY-MM-DD\n", *toFlag)
+					os.Exit(1)
+				}
+			}
+			entries, err := journal.ListTagEntries(cfg, from, to)
+			if err != nil {
+				fmt.Printf("Error listing tags: %v\n", err)
+				os.Exit(1)
+			}
+
+			if os.Args[2] == "list" {
+				counts := make(map[string]int)
+				var tags []string
+				for _, entry := range entries {
+					if counts[entry.Tag] == 0 {
+						tags = append(tags, entry.Tag)
+					}
+					counts[entry.Tag]++
+				}
+				if len(tags) == 0 {
+					fmt.Println("No tags found.")
+					os.Exit(0)
+				}
+				sort.SliceStable(tags, func(i, j int) bool {
+					if counts[tags[i]] != counts[tags[j]] {
+						return counts[tags[i]] > counts[tags[j]]
+					}
+					return tags[i] < tags[j]
+				})
+				for _, tag := range tags {
+					fmt.Printf("#%s %s\n", tag, theme.Muted("(%d)", counts[tag]))
+				}
+				os.Exit(0)
+			}
+
+			if len(args) == 0 {
+				fmt.Println("Usage: logbook tags search <tag> [--from YYYY-MM-DD] [--to YYYY-MM-DD]")
+				os.Exit(1)
+			}
+			tag := strings.ToLower(strings.TrimPrefix(args[0], "#"))
+			found := false
+			for _, entry := range entries {
+				if entry.Tag == tag {
+					fmt.Printf("%s %s\n", theme.Muted("%s", entry.Date), entry.Line)
+					found = true
+				}
+			}
+			if !found {
+				fmt.Println("No entries found.")
+			}
+		case "export":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			if len(os.Args) >= 3 && os.Args[2] == "html" {
+				htmlFlags := flag.NewFlagSet("export html", flag.ExitOnError)
+				outputDir := htmlFlags.String("output-dir", "logbook-html", "Directory of the HTML pages")
+				htmlFlags.Parse(os.Args[3:])
+
+				if err := export.ToHTML(cfg, *outputDir); err != nil {
+					fmt.Printf("Error exporting journal: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(theme.Success("Journal exported to %s", filepath.Join(*outputDir, "index.html")))
+				os.Exit(0)
+			}
+			if len(os.Args) < 3 || os.Args[2] != "json" {
+				fmt.Println("Usage: logbook export <json|html> [args]")
+				os.Exit(1)
+			}
+			exportFlags := flag.NewFlagSet("export json", flag.ExitOnError)
+			fromFlag := exportFlags.String("from", "", "First day, as YYYY-MM-DD")
+			toFlag := exportFlags.String("to", "", "Last day, as YYYY-MM-DD")
+			output := exportFlags.String("output", "", "File to write (defaults to the standard output)")
+			exportFlags.Parse(os.Args[3:])
+
+			var from, to time.Time
+			if *fromFlag != "" {
+				if from, err = time.Parse("2006-01-02", *fromFlag); err != nil {
+					fmt.Printf("Invalid --from %q, expected YYYY-MM-DD\n", *fromFlag)
+					os.Exit(1)
+				}
+			}
+			if *toFlag != "" {
+				if to, err = time.Parse("2006-01-02", *toFlag); err != nil {
+					fmt.Printf("Invalid --to %q, expected YYYY-MM-DD\n", *toFlag)
+					os.Exit(1)
+				}
+			}
+
+			if *output == "" {
+				if err := export.ToJSON(cfg, from, to, os.Stdout); err != nil {
+					fmt.Printf("Error exporting journal: %v\n", err)
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+			file, err := os.Create(*output)
+			if err != nil {
+				fmt.Printf("Error creating %s: %v\n", *output, err)
+				os.Exit(1)
+			}
+			err = export.ToJSON(cfg, from, to, file)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				fmt.Printf("Error exporting journal: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(theme.Success("Journal exported to %s", *output))
+		case "code":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			codeFlags := flag.NewFlagSet("code", flag.ExitOnError)
+			dateFlag := codeFlags.String("date", "", "Day of the code blocks, as YYYY-MM-DD (defaults to today)")
+			language := codeFlags.String("language", "", "Only the code blocks of the given language")
+			copyIndex := codeFlags.Int("copy", 0, "Copy the Nth code block to the clipboard instead of printing them")
+			codeFlags.Parse(os.Args[2:])
+
+			date := journal.EffectiveDate(time.Now().In(cfg.Location()), cfg.DayBoundaryHour)
+			if *dateFlag != "" {
+				date, err = time.Parse("2006-01-02", *dateFlag)
+				if err != nil {
+					fmt.Printf("Invalid --date %q, expected YYYY-MM-DD\n", *dateFlag)
+					os.Exit(1)
+				}
+			}
+			filePath, err := journal.DailyFilePath(cfg, date)
+			if err != nil {
+				fmt.Printf("Error getting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			blocks, err := journal.ExtractCodeBlocks(filePath)
+			if err != nil {
+				fmt.Printf("Error extracting code blocks: %v\n", err)
+				os.Exit(1)
+			}
+			if *language != "" {
+				blocks = journal.FilterCodeBlocksByLanguage(blocks, *language)
+			}
+			if len(blocks) == 0 {
+				fmt.Println("No code blocks found.")
+				os.Exit(0)
+			}
+
+			if *copyIndex != 0 {
+				if *copyIndex < 1 || *copyIndex > len(blocks) {
+					fmt.Printf("Invalid --copy %d, there are %d code blocks\n", *copyIndex, len(blocks))
+					os.Exit(1)
+				}
+				if err := clipboard.Copy(blocks[*copyIndex-1].Content); err != nil {
+					fmt.Printf("Error copying code block: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(theme.Success("Code block %d copied to the clipboard.", *copyIndex))
+				os.Exit(0)
+			}
+
+			for i, block := range blocks {
+				fmt.Println(theme.Muted("[%d] %s (line %d)", i+1, block.Language, block.LineNumber))
+				fmt.Println(block.Content)
+				fmt.Println()
+			}
+		case "rename-entry":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			renameFlags := flag.NewFlagSet("rename-entry", flag.ExitOnError)
+			dateFlag := renameFlags.String("date", "", "Day of the entry, as YYYY-MM-DD (defaults to today)")
+			fromFlag := renameFlags.String("from", "", "Current time of the entry, as HH:MM")
+			toFlag := renameFlags.String("to", "", "New time of the entry, as HH:MM")
+			renameFlags.Parse(os.Args[2:])
+
+			if *fromFlag == "" || *toFlag == "" {
+				fmt.Println("Usage: logbook rename-entry --date YYYY-MM-DD --from HH:MM --to HH:MM")
+				os.Exit(1)
+			}
+			date := journal.EffectiveDate(time.Now().In(cfg.Location()), cfg.DayBoundaryHour)
+			if *dateFlag != "" {
+				date, err = time.Parse("2006-01-02", *dateFlag)
+				if err != nil {
+					fmt.Printf("Invalid --date %q, expected YYYY-MM-DD\n", *dateFlag)
+					os.Exit(1)
+				}
+			}
+			from, err := time.Parse("15:04", *fromFlag)
+			if err != nil {
+				fmt.Printf("Invalid --from %q, expected HH:MM\n", *fromFlag)
+				os.Exit(1)
+			}
+			to, err := time.Parse("15:04", *toFlag)
+			if err != nil {
+				fmt.Printf("Invalid --to %q, expected HH:MM\n", *toFlag)
+				os.Exit(1)
+			}
+			filePath, err := journal.DailyFilePath(cfg, date)
+			if err != nil {
+				fmt.Printf("Error getting the journal file: %v\n", err)
+				os.Exit(1)
+			}
+			if err := journal.RenameEntry(cfg, filePath, from, to); err != nil {
+				fmt.Printf("Error renaming entry: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(theme.Success("Entry moved from %s to %s in %s", *fromFlag, *toFlag, filePath))
+		case "doctor":
+			cfg, err = loadConfig(configFilePath, "")
+			if err != nil {
+				fmt.Printf("Error loading configuration: %v\n", err)
+				os.Exit(1)
+			}
+			doctorFlags := flag.NewFlagSet("doctor", flag.ExitOnError)
+			orphanedReviews := doctorFlags.Bool("orphaned-reviews", false, "List the reviews of periods without journal files")
+			deleteOrphans := doctorFlags.Bool("delete", false, "Delete the orphaned reviews instead of listing them")
+			repair := doctorFlags.Bool("repair", false, "Convert the line endings to \\n and remove the trailing whitespace of the daily files")
+			doctorFlags.Parse(os.Args[2:])
+
+			if !*orphanedReviews && !*repair {
+				fmt.Println("Usage: logbook doctor [--orphaned-reviews [--delete]] [--repair]")
+				os.Exit(1)
+			}
+
+			if *repair {
+				files, err := journal.ListJournalFiles(cfg, time.Time{}, time.Time{})
+				if err != nil {
+					fmt.Printf("Error listing the journal files: %v\n", err)
+					os.Exit(1)
+				}
+				repairedFiles := 0
+				for _, filePath := range files {
+					repaired, err := journal.RepairFile(filePath)
+					if err != nil {
+						fmt.Printf("Error repairing %s: %v\n", filePath, err)
+						os.Exit(1)
+					}
+					if repaired {
+						fmt.Println(theme.Warning("Repaired %s", filePath))
+						repairedFiles++
+					}
+				}
+				fmt.Println(theme.Success("Repaired %d of %d journal files.", repairedFiles, len(files)))
+				if !*orphanedReviews {
+					os.Exit(0)
+				}
+			}
+
+			orphaned, err := review.DeleteOrphanedReviews(cfg, !*deleteOrphans)
+			if err != nil {
+				fmt.Printf("Error looking for orphaned reviews: %v\n", err)
+				os.Exit(1)
+			}
+			if len(orphaned) == 0 {
+				fmt.Println(theme.Success("No orphaned reviews found."))
+				os.Exit(0)
+			}
+			for _, path := range orphaned {
+				if *deleteOrphans {
+					fmt.Println(theme.Warning("Deleted %s", path))
+				} else {
+					fmt.Println(path)
+				}
+			}
+		case "version":
+			fmt.Println(versionString())
+		default:
+			// Commands not built in may be provided by a logbook-<command> executable on $PATH
+			env := []string{"LOGBOOK_CONFIG_PATH=" + configFilePath}
+			if pluginCfg, err := config.LoadConfig(configFilePath); err == nil {
+				env = append(env, "LOGBOOK_JOURNAL_DIR="+pluginCfg.JournalDir)
+			}
+			err := plugin.Execute(os.Args[1], os.Args[2:], env)
+			if errors.Is(err, plugin.ErrNotFound) {
+				fmt.Println("Unknown command. Use 'logbook help' for more information.")
+				os.Exit(1)
+			}
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			if err != nil {
+				fmt.Printf("Error running plugin: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	} else {
+		fmt.Println("Welcome to LogBook! Use 'logbook help' for more information.")
+	}
+}
+
+// versionString returns the version line of the version command, with "(dev)" for the build information
+// not set at build time.
+func versionString() string {
+	orDev := func(value string) string {
+		if value == "" {
+			return "(dev)"
+		}
+		return value
+	}
+	return fmt.Sprintf("LogBook version %s (commit %s, built %s)", orDev(version), orDev(commit), orDev(buildDate))
+}
+
+// loadConfig loads and validates the configuration file of the given [[journals]] entry, see config.GetJournalConfig,
+// applies its color theme and offers to recover journal files left partially written by an interrupted run.
+func loadConfig(configFilePath string, journalName string) (*config.Config, error) {
+	cfg, err := config.LoadConfig(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err = config.GetJournalConfig(cfg, journalName)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ValidateAll(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := theme.Apply(cfg.ColorTheme); err != nil {
+		return nil, err
+	}
+	fileutil.PreserveCRLF = cfg.PreserveCRLF
+	if cfg.Encrypt {
+		key, err := encrypt.LoadKey(cfg.EncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		fileutil.EncryptionKey = key
+	}
+	weather.Timeout = cfg.WeatherTimeout
+	interactive := isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+	if err := handlePartialWrites(cfg, os.Stdin, interactive); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readPassphrase returns the passphrase of the encrypted summaries, from $LOGBOOK_PASSPHRASE or asking for it.
+func readPassphrase(reader io.Reader) (string, error) {
+	if passphrase := os.Getenv("LOGBOOK_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	fmt.Print("Passphrase: ")
+	scanner := bufio.NewScanner(reader)
+	if !scanner.Scan() {
+		return "", fmt.Errorf("failed to read passphrase: %w", scanner.Err())
+	}
+	passphrase := strings.TrimSpace(scanner.Text())
+	if passphrase == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
+
+// handlePartialWrites asks, for each partially written journal file, whether to recover or discard it.
+// Without an interactive reader, e.g. when the entry is piped to logbook log, the files are only listed.
+func handlePartialWrites(cfg *config.Config, reader io.Reader, interactive bool) error {
+	partialWrites, err := journal.FindPartialWrites(cfg.JournalDir)
+	if err != nil {
+		return err
+	}
+	if len(partialWrites) == 0 {
+		return nil
+	}
+
+	fmt.Println(theme.Warning("Found %d journal file(s) left partially written by an interrupted run.", len(partialWrites)))
+	if !interactive {
+		for _, tmpPath := range partialWrites {
+			fmt.Println(theme.Warning("%s: run logbook from a terminal to recover or discard it", tmpPath))
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(reader)
+	for _, tmpPath := range partialWrites {
+		for {
+			fmt.Printf("%s: [r]ecover or [d]iscard? ", tmpPath)
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return fmt.Errorf("failed to read answer for %s: %w", tmpPath, err)
+				}
+				return fmt.Errorf("no answer typed for %s", tmpPath)
+			}
+			answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if answer == "r" || answer == "recover" {
+				err := journal.RecoverPartialWrite(tmpPath)
+				if errors.Is(err, journal.ErrWriteCompleted) {
+					fmt.Println(theme.Muted("%s was completed in the meantime", strings.TrimSuffix(tmpPath, fileutil.TmpSuffix)))
+					break
+				}
+				if err != nil {
+					return err
+				}
+				fmt.Println(theme.Success("Recovered %s", strings.TrimSuffix(tmpPath, fileutil.TmpSuffix)))
+				break
+			}
+			if answer == "d" || answer == "discard" {
+				err := journal.DiscardPartialWrite(tmpPath)
+				if errors.Is(err, journal.ErrWriteCompleted) {
+					fmt.Println(theme.Muted("%s was completed in the meantime", strings.TrimSuffix(tmpPath, fileutil.TmpSuffix)))
+					break
+				}
+				if err != nil {
+					return err
+				}
+				fmt.Println(theme.Success("Discarded %s", tmpPath))
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// printTemplateFields prints a table of the template fields, highlighting the ones changed from the default,
+// with a preview rendered on config.PreviewDate.
+func printTemplateFields(cfg *config.Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tTOML KEY\tVALUE\tDEFAULT\tPREVIEW")
+	for _, field := range config.ListTemplateFields(cfg) {
+		preview, err := config.PreviewTemplate(field.CurrentValue)
+		if err != nil {
+			preview = fmt.Sprintf("error: %v", err)
+		}
+		colorize := theme.Muted
+		if !field.IsDefault {
+			colorize = theme.Warning
+		}
+		fmt.Fprintln(w, colorize("%s\t%s\t%q\t%q\t%q", field.Name, field.TOMLKey, field.CurrentValue, field.DefaultValue, preview))
+	}
+	w.Flush()
+}
+
+// printConfig prints config.FormatConfig, highlighting the fields that differ from the defaults.
+func printConfig(cfg *config.Config) {
+	for _, line := range strings.Split(strings.TrimSuffix(config.FormatConfig(cfg), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "*"):
+			fmt.Println(theme.Warning("%s", line))
+		case strings.HasPrefix(line, "      "):
+			fmt.Println(theme.Muted("%s", line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+// removeNoColorFlag removes the --no-color flags from args, which are accepted by every command,
+// and reports whether there were any. Arguments after "--" are left as they are.
+func removeNoColorFlag(args []string) ([]string, bool) {
+	var kept []string
+	found := false
+	for i, arg := range args {
+		if arg == "--" {
+			kept = append(kept, args[i:]...)
+			break
+		}
+		if arg == "--no-color" {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept, found
+}
+
+// parseFlags parses flags placed before, between or after the positional arguments
+// and returns the positional arguments in order.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	args = joinOptionalIntValues(fs, args)
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// joinOptionalIntValues rewrites "--name N" as "--name=N" for the optionalIntFlag flags of fs: the flag package
+// parses them as boolean flags, and would leave N as a positional argument.
+func joinOptionalIntValues(fs *flag.FlagSet, args []string) []string {
+	joined := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(joined, args[i:]...)
+		}
+		name := strings.TrimLeft(arg, "-")
+		if f := fs.Lookup(name); f != nil && name != arg && i+1 < len(args) {
+			if _, ok := f.Value.(*optionalIntFlag); ok {
+				if _, err := strconv.Atoi(args[i+1]); err == nil {
+					arg += "=" + args[i+1]
+					i++
+				}
+			}
+		}
+		joined = append(joined, arg)
+	}
+	return joined
+}
+
+// runHook runs a hook of the configuration, exiting if it fails.
+func runHook(name, hookCmd string, env map[string]string) {
+	if err := config.RunHook(hookCmd, env); err != nil {
+		fmt.Printf("Error running the %s hook: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// stringListFlag is a string flag that can be repeated, collecting all its values.
+type stringListFlag []string
+
+func (f *stringListFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringListFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// optionalIntFlag is an integer flag whose value can be omitted: "--name" sets the default value,
+// "--name=N" and "--name N" set N.
+type optionalIntFlag struct {
+	set          bool
+	value        int
+	defaultValue int
+}
+
+func (f *optionalIntFlag) String() string { return strconv.Itoa(f.value) }
+
+func (f *optionalIntFlag) IsBoolFlag() bool { return true }
+
+func (f *optionalIntFlag) Set(s string) error {
+	f.set = true
+	if s == "true" {
+		f.value = f.defaultValue
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("expected a positive number, got %q", s)
 	}
-}
\ No newline at end of file
+	f.value = n
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainVersionCommand(t *testing.T) {
+	// Test case 1: Development build, without ldflags
+	assert.Equal(t, "LogBook version (dev) (commit (dev), built (dev))", versionString())
+
+	// Test case 2: Build information set with ldflags
+	defer func(v, c, d string) { version, commit, buildDate = v, c, d }(version, commit, buildDate)
+	version, commit, buildDate = "1.2.3", "abc1234", "2025-01-01"
+	output := versionString()
+	assert.Contains(t, output, "LogBook version")
+	assert.Equal(t, "LogBook version 1.2.3 (commit abc1234, built 2025-01-01)", output)
+}
+
+func TestRemoveNoColorFlag(t *testing.T) {
+	// Test case 1: The flag is removed wherever it is
+	args, found := removeNoColorFlag([]string{"--no-color", "log", "Fixed the parser", "--no-color"})
+	assert.True(t, found)
+	assert.Equal(t, []string{"log", "Fixed the parser"}, args)
+
+	// Test case 2: Without the flag the arguments are unchanged
+	args, found = removeNoColorFlag([]string{"stats", "--by-project"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"stats", "--by-project"}, args)
+
+	// Test case 3: Arguments after "--" are kept
+	args, found = removeNoColorFlag([]string{"log", "--", "--no-color"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"log", "--", "--no-color"}, args)
+}
+
+func TestParseFlagsOptionalInt(t *testing.T) {
+	newFlags := func() (*flag.FlagSet, *optionalIntFlag, *bool) {
+		fs := flag.NewFlagSet("review year", flag.ContinueOnError)
+		topTags := &optionalIntFlag{defaultValue: 10}
+		fs.Var(topTags, "top-tags", "")
+		return fs, topTags, fs.Bool("best-of", false, "")
+	}
+
+	// Test case 1: The value after the flag is its value, not a positional argument
+	fs, topTags, _ := newFlags()
+	assert.Equal(t, []string{"2025"}, parseFlags(fs, []string{"--top-tags", "5", "2025"}))
+	assert.True(t, topTags.set)
+	assert.Equal(t, 5, topTags.value)
+
+	// Test case 2: Without a value the default is used
+	fs, topTags, bestOf := newFlags()
+	assert.Equal(t, []string{"2025"}, parseFlags(fs, []string{"2025", "--top-tags", "--best-of"}))
+	assert.True(t, topTags.set)
+	assert.Equal(t, 10, topTags.value)
+	assert.True(t, *bestOf)
+
+	// Test case 3: The value after "="
+	fs, topTags, _ = newFlags()
+	assert.Equal(t, []string{"2025"}, parseFlags(fs, []string{"-top-tags=3", "2025"}))
+	assert.Equal(t, 3, topTags.value)
+
+	// Test case 4: Not set
+	fs, topTags, _ = newFlags()
+	assert.Equal(t, []string{"2025"}, parseFlags(fs, []string{"2025"}))
+	assert.False(t, topTags.set)
+}
+
+func TestHandlePartialWrites(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = t.TempDir()
+	filePath := filepath.Join(cfg.JournalDir, "2025-09-18.md")
+	os.WriteFile(filePath, []byte("# Sep 18 2025\n"), 0644)
+	os.WriteFile(filePath+".tmp", []byte("# Sep 18 2025\n\n# LOG\n"), 0644)
+
+	// Test case 1: Without a terminal the partial writes are left alone, the input is not read
+	input := strings.NewReader("r\n")
+	assert.NoError(t, handlePartialWrites(cfg, input, false))
+	assert.FileExists(t, filePath+".tmp")
+	assert.Equal(t, 2, input.Len())
+
+	// Test case 2: The input ends before the answer
+	assert.EqualError(t, handlePartialWrites(cfg, strings.NewReader(""), true), "no answer typed for "+filePath+".tmp")
+
+	// Test case 3: Recovering the partial write
+	assert.NoError(t, handlePartialWrites(cfg, strings.NewReader("x\nr\n"), true))
+	assert.NoFileExists(t, filePath+".tmp")
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, "# Sep 18 2025\n\n# LOG\n", string(content))
+}
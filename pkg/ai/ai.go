@@ -1,13 +1,18 @@
 package ai
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
+// AISummarizer generates a text from a prompt and a content, e.g. the summary of a daily journal file.
+// Implementations should stop and return the error of ctx when it is cancelled or its deadline expires.
 type AISummarizer interface {
-	GenerateSummary(text string, prompt string) (string, error)
+	GenerateSummary(ctx context.Context, text string, prompt string) (string, error)
 }
 
 // ExternalAISummarizer is a concrete implementation of AISummarizer that calls an external AI command.
@@ -15,7 +20,7 @@ type ExternalAISummarizer struct {
 	CommandTemplate string
 }
 
-func (e *ExternalAISummarizer) GenerateSummary(text string, prompt string) (string, error) {
+func (e *ExternalAISummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
 	if e.CommandTemplate == "" {
 		return "", fmt.Errorf("AI command template is not configured")
 	}
@@ -33,8 +38,12 @@ func (e *ExternalAISummarizer) GenerateSummary(text string, prompt string) (stri
 
 	// Parse the command string into command and args
 	// Use shell to execute the command to handle complex arguments properly
-	cmd := exec.Command("sh", "-c", cmdString)
+	cmd := exec.CommandContext(ctx, "sh", "-c", cmdString)
+	cmd.WaitDelay = time.Second // Do not wait for the children of the killed shell, still writing the output
 	output, err := cmd.Output()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to execute AI command '%s': %w", cmdString, err)
 	}
@@ -44,11 +53,11 @@ func (e *ExternalAISummarizer) GenerateSummary(text string, prompt string) (stri
 
 // PlaceholderAISummarizer is a concrete implementation of AISummarizer that returns a predefined summary.
 type PlaceholderAISummarizer struct {
-	Err            error
+	Err             error
 	CommandTemplate string
 }
 
-func (p *PlaceholderAISummarizer) GenerateSummary(text string, prompt string) (string, error) {
+func (p *PlaceholderAISummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
 	if p.Err != nil {
 		return "", p.Err
 	}
@@ -57,22 +66,109 @@ func (p *PlaceholderAISummarizer) GenerateSummary(text string, prompt string) (s
 	return "This is a placeholder summary generated by the AI agent.", nil
 }
 
-// NewAISummarizer creates a new AISummarizer based on the provided command template.
-func NewAISummarizer(commandTemplate string) AISummarizer {
-	if commandTemplate != "" {
-		return &ExternalAISummarizer{CommandTemplate: commandTemplate}
+// NewAISummarizer creates a new AISummarizer for the backend of the given settings.
+// The command backend falls back to a PlaceholderAISummarizer when no command template is provided.
+func NewAISummarizer(settings Settings) AISummarizer {
+	switch settings.Backend {
+	case BackendOpenAI:
+		return &OpenAISummarizer{APIKey: settings.APIKey, Model: settings.Model}
+	case BackendAnthropic:
+		return &AnthropicSummarizer{APIKey: settings.APIKey, Model: settings.Model}
+	case BackendOllama:
+		return NewOllamaAISummarizer(settings.BaseURL, settings.Model)
+	}
+	if settings.CommandTemplate != "" {
+		return &ExternalAISummarizer{CommandTemplate: settings.CommandTemplate}
 	}
 	// Fallback to PlaceholderAISummarizer if no command template is provided
 	return &PlaceholderAISummarizer{}
 }
 
+// MaxTitleLength is the maximum number of characters of a title returned by GenerateTitle.
+const MaxTitleLength = 60
+
+// GenerateTitle asks the summarizer for a short descriptive title of the given log content.
+// Only the first line of the answer is used, stripped of Markdown header marks and quotes, and cut to MaxTitleLength characters.
+func GenerateTitle(ctx context.Context, logContent, prompt string, summarizer AISummarizer) (string, error) {
+	if summarizer == nil {
+		return "", fmt.Errorf("AI summarizer is not configured")
+	}
+
+	output, err := summarizer.GenerateSummary(ctx, logContent, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate title with AI: %w", err)
+	}
+
+	title, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "#"))
+	title = strings.Trim(title, "\"'` ")
+	if title == "" {
+		return "", fmt.Errorf("AI returned an empty title")
+	}
+
+	if runes := []rune(title); len(runes) > MaxTitleLength {
+		title = strings.TrimSpace(string(runes[:MaxTitleLength]))
+	}
+	return title, nil
+}
+
+// FirstSentence returns the first sentence of text, that is up to the first ".", "!" or "?" followed by a space
+// or a line break, or up to the end of the first line. Surrounding spaces are removed.
+func FirstSentence(text string) string {
+	text = strings.TrimSpace(text)
+	for i, r := range text {
+		if r == '\n' {
+			return strings.TrimSpace(text[:i])
+		}
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		if next := i + 1; next == len(text) || text[next] == ' ' || text[next] == '\t' || text[next] == '\n' || text[next] == '\r' {
+			return text[:next]
+		}
+	}
+	return text
+}
+
 // MockAISummarizer is a mock implementation of the AISummarizer interface for testing.
 type MockAISummarizer struct {
 	Summary string
 	Err     error
+	Delay   time.Duration // Time taken by each GenerateSummary call
 }
 
-func (m *MockAISummarizer) GenerateSummary(text string, prompt string) (string, error) {
+func (m *MockAISummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	select {
+	case <-time.After(m.Delay):
+	case <-ctx.Done():
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	return m.Summary, m.Err
 }
 
+// SummaryCall records the arguments of a GenerateSummary call.
+type SummaryCall struct {
+	Text   string
+	Prompt string
+}
+
+// RecordingMockSummarizer is a mock implementation of the AISummarizer interface that records every call, for testing.
+type RecordingMockSummarizer struct {
+	Summary string
+	Err     error
+	Calls   []SummaryCall
+
+	mu sync.Mutex
+}
+
+func (r *RecordingMockSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Calls = append(r.Calls, SummaryCall{Text: text, Prompt: prompt})
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return r.Summary, r.Err
+}
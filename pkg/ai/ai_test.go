@@ -1,8 +1,11 @@
 package ai
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -12,7 +15,7 @@ func TestAISummarizerInterface(t *testing.T) {
 	mockAI := &MockAISummarizer{Summary: "Test summary", Err: nil}
 	var summarizer AISummarizer = mockAI
 
-	summary, err := summarizer.GenerateSummary("some text", "some prompt")
+	summary, err := summarizer.GenerateSummary(context.Background(), "some text", "some prompt")
 	assert.NoError(t, err)
 	assert.Equal(t, "Test summary", summary)
 
@@ -20,7 +23,7 @@ func TestAISummarizerInterface(t *testing.T) {
 	mockAI = &MockAISummarizer{Summary: "", Err: errors.New("AI error")}
 	summarizer = mockAI
 
-	summary, err = summarizer.GenerateSummary("some text", "some prompt")
+	summary, err = summarizer.GenerateSummary(context.Background(), "some text", "some prompt")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "AI error")
 	assert.Empty(t, summary)
@@ -29,7 +32,7 @@ func TestAISummarizerInterface(t *testing.T) {
 	placeholderAI := &PlaceholderAISummarizer{}
 	summarizer = placeholderAI
 
-	summary, err = summarizer.GenerateSummary("some text", "some prompt")
+	summary, err = summarizer.GenerateSummary(context.Background(), "some text", "some prompt")
 	assert.NoError(t, err)
 	assert.Equal(t, "This is a placeholder summary generated by the AI agent.", summary)
 
@@ -37,8 +40,60 @@ func TestAISummarizerInterface(t *testing.T) {
 	placeholderAIWithError := &PlaceholderAISummarizer{Err: errors.New("placeholder AI error")}
 	summarizer = placeholderAIWithError
 
-	summary, err = summarizer.GenerateSummary("some text", "some prompt")
+	summary, err = summarizer.GenerateSummary(context.Background(), "some text", "some prompt")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "placeholder AI error")
 	assert.Empty(t, summary)
+
+	// Test case 5: ExternalAISummarizer stops the command when the context expires
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = (&ExternalAISummarizer{CommandTemplate: "sleep 5"}).GenerateSummary(ctx, "some text", "some prompt")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGenerateTitle(t *testing.T) {
+	// Test case 1: The first line of the answer is used, without header marks and quotes
+	mockAI := &RecordingMockSummarizer{Summary: "## \"Fixed the review parser\"\nSome explanation"}
+	title, err := GenerateTitle(context.Background(), "09:00 Fixed the parser", "Write a title", mockAI)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed the review parser", title)
+	assert.Equal(t, "09:00 Fixed the parser", mockAI.Calls[0].Text)
+	assert.Equal(t, "Write a title", mockAI.Calls[0].Prompt)
+
+	// Test case 2: Long titles are cut to MaxTitleLength characters
+	mockAI = &RecordingMockSummarizer{Summary: strings.Repeat("è", 100)}
+	title, err = GenerateTitle(context.Background(), "log", "prompt", mockAI)
+	assert.NoError(t, err)
+	assert.Equal(t, MaxTitleLength, len([]rune(title)))
+
+	// Test case 3: Empty answer
+	_, err = GenerateTitle(context.Background(), "log", "prompt", &MockAISummarizer{Summary: "  \n"})
+	assert.ErrorContains(t, err, "AI returned an empty title")
+
+	// Test case 4: AI error
+	_, err = GenerateTitle(context.Background(), "log", "prompt", &MockAISummarizer{Err: errors.New("AI error")})
+	assert.ErrorContains(t, err, "AI error")
+
+	// Test case 5: No summarizer
+	_, err = GenerateTitle(context.Background(), "log", "prompt", nil)
+	assert.ErrorContains(t, err, "AI summarizer is not configured")
+}
+
+func TestFirstSentence(t *testing.T) {
+	// Test case 1: Only the first of several sentences is kept
+	assert.Equal(t, "We shipped the release.", FirstSentence("  We shipped the release. It took two sprints! Next is v2?"))
+	assert.Equal(t, "Did we ship?", FirstSentence("Did we ship? Yes."))
+
+	// Test case 2: Dots within a sentence do not end it
+	assert.Equal(t, "Upgraded to Go 1.23 and fixed main.go.", FirstSentence("Upgraded to Go 1.23 and fixed main.go.\nMore text"))
+
+	// Test case 3: A line break ends the sentence
+	assert.Equal(t, "No punctuation here", FirstSentence("No punctuation here\nSecond line."))
+
+	// Test case 4: Single sentence without final punctuation, empty text
+	assert.Equal(t, "Just this", FirstSentence("Just this"))
+	assert.Equal(t, "", FirstSentence("  "))
 }
@@ -0,0 +1,224 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Supported AI backends, see Settings.Backend.
+const (
+	BackendCommand   = "command"   // Run an external command, see ExternalAISummarizer
+	BackendOpenAI    = "openai"    // Call the OpenAI chat completions API
+	BackendAnthropic = "anthropic" // Call the Anthropic messages API
+	BackendOllama    = "ollama"    // Call the generate API of a local Ollama server
+)
+
+// Default addresses and models of the HTTP backends.
+const (
+	DefaultOpenAIBaseURL    = "https://api.openai.com/v1"
+	DefaultOpenAIModel      = "gpt-4o-mini"
+	DefaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	DefaultAnthropicModel   = "claude-3-5-haiku-latest"
+	DefaultOllamaBaseURL    = "http://localhost:11434"
+	DefaultOllamaModel      = "llama3.2"
+	anthropicVersion        = "2023-06-01"
+	anthropicMaxTokens      = 1024
+)
+
+// HTTPTimeout is the maximum duration of a request to an HTTP backend.
+var HTTPTimeout = 60 * time.Second
+
+// Settings selects and configures the AISummarizer built by NewAISummarizer.
+type Settings struct {
+	Backend         string // One of BackendCommand, BackendOpenAI, BackendAnthropic or BackendOllama, empty for BackendCommand
+	CommandTemplate string // Command of BackendCommand
+	APIKey          string // API key of the HTTP backends
+	Model           string // Model of the HTTP backends, empty for the backend default
+	BaseURL         string // Address of the Ollama server, empty for DefaultOllamaBaseURL
+}
+
+// chatMessage is a message of the OpenAI and Anthropic request bodies.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OpenAISummarizer is a concrete implementation of AISummarizer that calls the OpenAI chat completions API.
+// The prompt is sent as the system message and the text as the user message.
+type OpenAISummarizer struct {
+	APIKey  string
+	Model   string
+	BaseURL string // Empty for DefaultOpenAIBaseURL, replaced in tests
+}
+
+type openAIRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (o *OpenAISummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	if o.APIKey == "" {
+		return "", fmt.Errorf("OpenAI API key is not configured")
+	}
+	body := openAIRequest{
+		Model: valueOr(o.Model, DefaultOpenAIModel),
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: text},
+		},
+	}
+	headers := map[string]string{"Authorization": "Bearer " + o.APIKey}
+
+	var response openAIResponse
+	if err := postJSON(ctx, valueOr(o.BaseURL, DefaultOpenAIBaseURL)+"/chat/completions", headers, body, &response); err != nil {
+		return "", fmt.Errorf("failed to call OpenAI: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("failed to call OpenAI: the response has no choices")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// AnthropicSummarizer is a concrete implementation of AISummarizer that calls the Anthropic messages API.
+// The prompt is sent as the system prompt and the text as the user message.
+type AnthropicSummarizer struct {
+	APIKey  string
+	Model   string
+	BaseURL string // Empty for DefaultAnthropicBaseURL, replaced in tests
+}
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (a *AnthropicSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	if a.APIKey == "" {
+		return "", fmt.Errorf("Anthropic API key is not configured")
+	}
+	body := anthropicRequest{
+		Model:     valueOr(a.Model, DefaultAnthropicModel),
+		MaxTokens: anthropicMaxTokens,
+		System:    prompt,
+		Messages:  []chatMessage{{Role: "user", Content: text}},
+	}
+	headers := map[string]string{"x-api-key": a.APIKey, "anthropic-version": anthropicVersion}
+
+	var response anthropicResponse
+	if err := postJSON(ctx, valueOr(a.BaseURL, DefaultAnthropicBaseURL)+"/messages", headers, body, &response); err != nil {
+		return "", fmt.Errorf("failed to call Anthropic: %w", err)
+	}
+	var parts []string
+	for _, content := range response.Content {
+		if content.Type == "text" {
+			parts = append(parts, content.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("failed to call Anthropic: the response has no text")
+	}
+	return strings.TrimSpace(strings.Join(parts, "")), nil
+}
+
+// OllamaSummarizer is a concrete implementation of AISummarizer that calls the /api/generate endpoint of Ollama.
+// The prompt and the text are sent together as the prompt, without streaming.
+type OllamaSummarizer struct {
+	BaseURL string // Empty for DefaultOllamaBaseURL
+	Model   string
+}
+
+// NewOllamaAISummarizer returns an AISummarizer using the given model of the Ollama server at baseURL,
+// or at DefaultOllamaBaseURL if baseURL is empty.
+func NewOllamaAISummarizer(baseURL, model string) AISummarizer {
+	return &OllamaSummarizer{BaseURL: valueOr(baseURL, DefaultOllamaBaseURL), Model: model}
+}
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+}
+
+func (o *OllamaSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	body := ollamaRequest{
+		Model:  valueOr(o.Model, DefaultOllamaModel),
+		Prompt: prompt + "\n\n" + text,
+		Stream: false,
+	}
+
+	var response ollamaResponse
+	if err := postJSON(ctx, strings.TrimSuffix(valueOr(o.BaseURL, DefaultOllamaBaseURL), "/")+"/api/generate", nil, body, &response); err != nil {
+		return "", fmt.Errorf("failed to call Ollama: %w", err)
+	}
+	summary := strings.TrimSpace(response.Response)
+	if summary == "" {
+		return "", fmt.Errorf("failed to call Ollama: the response is empty")
+	}
+	return summary, nil
+}
+
+// postJSON sends body as JSON to url and decodes the JSON answer into response.
+// The request is aborted when ctx is done.
+func postJSON(ctx context.Context, url string, headers map[string]string, body, response any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{Timeout: HTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// valueOr returns value, or fallback if value is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,144 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAISummarizerBackends(t *testing.T) {
+	// Test case 1: the command backend is the default
+	assert.Equal(t, &ExternalAISummarizer{CommandTemplate: "echo '{TEXT}'"}, NewAISummarizer(Settings{CommandTemplate: "echo '{TEXT}'"}))
+	assert.Equal(t, &ExternalAISummarizer{CommandTemplate: "echo '{TEXT}'"}, NewAISummarizer(Settings{Backend: BackendCommand, CommandTemplate: "echo '{TEXT}'"}))
+	assert.Equal(t, &PlaceholderAISummarizer{}, NewAISummarizer(Settings{}))
+
+	// Test case 2: HTTP backends
+	assert.Equal(t, &OpenAISummarizer{APIKey: "key", Model: "gpt-4o"}, NewAISummarizer(Settings{Backend: BackendOpenAI, APIKey: "key", Model: "gpt-4o"}))
+	assert.Equal(t, &AnthropicSummarizer{APIKey: "key"}, NewAISummarizer(Settings{Backend: BackendAnthropic, APIKey: "key"}))
+	assert.Equal(t, &OllamaSummarizer{BaseURL: DefaultOllamaBaseURL, Model: "mistral"}, NewAISummarizer(Settings{Backend: BackendOllama, Model: "mistral"}))
+	assert.Equal(t, &OllamaSummarizer{BaseURL: "http://gpu-box:11434"}, NewAISummarizer(Settings{Backend: BackendOllama, BaseURL: "http://gpu-box:11434"}))
+}
+
+func TestOpenAISummarizer(t *testing.T) {
+	var received openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Model == "broken" {
+			http.Error(w, `{"error": "invalid model"}`, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": " A productive day. \n"}}]}`))
+	}))
+	defer server.Close()
+
+	// Test case 1: the prompt is the system message and the text the user message
+	summarizer := &OpenAISummarizer{APIKey: "secret", BaseURL: server.URL}
+	summary, err := summarizer.GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.NoError(t, err)
+	assert.Equal(t, "A productive day.", summary)
+	assert.Equal(t, openAIRequest{
+		Model:    DefaultOpenAIModel,
+		Messages: []chatMessage{{Role: "system", Content: "Summarize"}, {Role: "user", Content: "Fixed the parser"}},
+	}, received)
+
+	// Test case 2: error status
+	summarizer.Model = "broken"
+	_, err = summarizer.GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "failed to call OpenAI: 400 Bad Request")
+	assert.ErrorContains(t, err, "invalid model")
+
+	// Test case 3: missing API key
+	_, err = (&OpenAISummarizer{BaseURL: server.URL}).GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "OpenAI API key is not configured")
+}
+
+func TestAnthropicSummarizer(t *testing.T) {
+	var received anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/messages", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("x-api-key"))
+		assert.Equal(t, anthropicVersion, r.Header.Get("anthropic-version"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Model == "empty" {
+			w.Write([]byte(`{"content": []}`))
+			return
+		}
+		w.Write([]byte(`{"content": [{"type": "text", "text": "A productive day."}]}`))
+	}))
+	defer server.Close()
+
+	// Test case 1: the prompt is the system prompt and the text the user message
+	summarizer := &AnthropicSummarizer{APIKey: "secret", Model: "claude-sonnet", BaseURL: server.URL}
+	summary, err := summarizer.GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.NoError(t, err)
+	assert.Equal(t, "A productive day.", summary)
+	assert.Equal(t, anthropicRequest{
+		Model:     "claude-sonnet",
+		MaxTokens: anthropicMaxTokens,
+		System:    "Summarize",
+		Messages:  []chatMessage{{Role: "user", Content: "Fixed the parser"}},
+	}, received)
+
+	// Test case 2: response without text
+	summarizer.Model = "empty"
+	_, err = summarizer.GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "failed to call Anthropic: the response has no text")
+
+	// Test case 3: missing API key
+	_, err = (&AnthropicSummarizer{BaseURL: server.URL}).GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "Anthropic API key is not configured")
+}
+
+func TestOllamaSummarizer(t *testing.T) {
+	var received ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/generate", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		switch received.Model {
+		case "missing":
+			http.Error(w, `{"error": "model 'missing' not found"}`, http.StatusNotFound)
+		case "empty":
+			w.Write([]byte(`{"model": "empty", "response": "", "done": true}`))
+		default:
+			w.Write([]byte(`{"model": "mistral", "response": " A productive day.\n", "done": true}`))
+		}
+	}))
+	defer server.Close()
+
+	// Test case 1: the prompt and the text are sent as the prompt, without streaming
+	summarizer := NewOllamaAISummarizer(server.URL+"/", "mistral")
+	summary, err := summarizer.GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.NoError(t, err)
+	assert.Equal(t, "A productive day.", summary)
+	assert.Equal(t, ollamaRequest{Model: "mistral", Prompt: "Summarize\n\nFixed the parser", Stream: false}, received)
+
+	// Test case 2: the default model is used if none is configured
+	_, err = NewOllamaAISummarizer(server.URL, "").GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultOllamaModel, received.Model)
+
+	// Test case 3: errors and empty responses
+	_, err = NewOllamaAISummarizer(server.URL, "missing").GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "failed to call Ollama: 404 Not Found: {\"error\": \"model 'missing' not found\"}")
+	_, err = NewOllamaAISummarizer(server.URL, "empty").GenerateSummary(context.Background(), "Fixed the parser", "Summarize")
+	assert.ErrorContains(t, err, "failed to call Ollama: the response is empty")
+
+	// Test case 4: the request is not sent with a cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	received = ollamaRequest{}
+	_, err = summarizer.GenerateSummary(ctx, "Fixed the parser", "Summarize")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, received.Model)
+}
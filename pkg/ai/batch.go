@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// SummariseRequest is a text to summarise with BatchSummarise.
+type SummariseRequest struct {
+	ID     string // Identifies the request in its SummariseResult
+	Text   string
+	Prompt string
+}
+
+// SummariseResult is the outcome of a SummariseRequest.
+type SummariseResult struct {
+	ID      string
+	Summary string
+	Err     error
+}
+
+// BatchSummarise summarises the requests with a pool of concurrency workers, runtime.NumCPU() if concurrency <= 0.
+// Results are returned in the order of the requests.
+// The returned error is only about the whole batch; the error of each request is in its SummariseResult,
+// the error of ctx for the requests not summarised before ctx is done.
+func BatchSummarise(ctx context.Context, summarizer AISummarizer, requests []SummariseRequest, concurrency int) ([]SummariseResult, error) {
+	if summarizer == nil {
+		return nil, fmt.Errorf("AI summarizer is not configured")
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	results := make([]SummariseResult, len(requests))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				summary, err := summarizer.GenerateSummary(ctx, requests[i].Text, requests[i].Prompt)
+				results[i] = SummariseResult{ID: requests[i].ID, Summary: summary, Err: err}
+			}
+		}()
+	}
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingSummarizer fails the texts containing "fail" and summarises the others as their upper case.
+type failingSummarizer struct {
+	MockAISummarizer
+}
+
+func (f *failingSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	time.Sleep(f.Delay)
+	if strings.Contains(text, "fail") {
+		return "", errors.New("AI error")
+	}
+	return strings.ToUpper(text), nil
+}
+
+func TestBatchSummarise(t *testing.T) {
+	var requests []SummariseRequest
+	for i := 0; i < 10; i++ {
+		requests = append(requests, SummariseRequest{ID: fmt.Sprintf("req-%d", i), Text: fmt.Sprintf("text %d", i), Prompt: "summarise"})
+	}
+
+	// Test case 1: All the results, in the order of the requests, faster than one request at a time
+	delay := 100 * time.Millisecond
+	start := time.Now()
+	results, err := BatchSummarise(context.Background(), &MockAISummarizer{Summary: "summary", Delay: delay}, requests, 4)
+	elapsed := time.Since(start)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 10) {
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("req-%d", i), result.ID)
+			assert.Equal(t, "summary", result.Summary)
+			assert.NoError(t, result.Err)
+		}
+	}
+	assert.Less(t, elapsed, 10*delay)
+
+	// Test case 2: A failure only affects its own result
+	requests[3].Text = "fail 3"
+	results, err = BatchSummarise(context.Background(), &failingSummarizer{MockAISummarizer{Delay: delay}}, requests, 4)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 10) {
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("req-%d", i), result.ID)
+			if i == 3 {
+				assert.EqualError(t, result.Err, "AI error")
+				assert.Empty(t, result.Summary)
+				continue
+			}
+			assert.NoError(t, result.Err)
+			assert.Equal(t, fmt.Sprintf("TEXT %d", i), result.Summary)
+		}
+	}
+
+	// Test case 3: A non positive concurrency uses all the CPUs
+	results, err = BatchSummarise(context.Background(), &MockAISummarizer{Summary: "summary"}, requests, 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 10)
+
+	// Test case 4: No summarizer
+	_, err = BatchSummarise(context.Background(), nil, requests, 4)
+	assert.EqualError(t, err, "AI summarizer is not configured")
+}
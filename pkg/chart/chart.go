@@ -0,0 +1,70 @@
+package chart
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// barWidth is the width of a bar, also the maximum label length shown below it.
+const barWidth = 3
+
+// BarChart renders values as an ASCII bar chart of the given height (in rows), one column per value.
+// Bars are scaled relative to the highest value, which is shown on the y-axis next to the top row.
+// Any non zero value is drawn with at least one row, zero values are drawn as an empty bar.
+func BarChart(labels []string, values []int, height int) (string, error) {
+	if len(labels) != len(values) {
+		return "", fmt.Errorf("got %d labels for %d values", len(labels), len(values))
+	}
+	if height < 1 {
+		return "", fmt.Errorf("chart height must be at least 1, got %d", height)
+	}
+
+	maxValue := 0
+	for _, value := range values {
+		if value < 0 {
+			return "", fmt.Errorf("chart values cannot be negative, got %d", value)
+		}
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+
+	// Height of each bar in rows, rounded up so that small values stay visible
+	barHeights := make([]int, len(values))
+	if maxValue > 0 {
+		for i, value := range values {
+			barHeights[i] = (value*height + maxValue - 1) / maxValue
+		}
+	}
+
+	axisWidth := len(strconv.Itoa(maxValue))
+	var sb strings.Builder
+	for row := height; row >= 1; row-- {
+		axisLabel := ""
+		if row == height {
+			axisLabel = strconv.Itoa(maxValue)
+		}
+		line := fmt.Sprintf("%*s |", axisWidth, axisLabel)
+		for _, barHeight := range barHeights {
+			if barHeight >= row {
+				line += " " + strings.Repeat("#", barWidth)
+			} else {
+				line += " " + strings.Repeat(" ", barWidth)
+			}
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("%*s +%s\n", axisWidth, "0", strings.Repeat("-", (barWidth+1)*len(values))))
+	line := strings.Repeat(" ", axisWidth+2)
+	for _, label := range labels {
+		if len(label) > barWidth {
+			label = label[:barWidth]
+		}
+		line += fmt.Sprintf(" %-*s", barWidth, label)
+	}
+	sb.WriteString(strings.TrimRight(line, " ") + "\n")
+
+	return sb.String(), nil
+}
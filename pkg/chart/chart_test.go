@@ -0,0 +1,46 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarChart(t *testing.T) {
+	// Test case 1: Bars are scaled relative to the highest value
+	result, err := BarChart([]string{"Jan", "Feb", "Mar"}, []int{4, 0, 2}, 4)
+	assert.NoError(t, err)
+	expected := "" +
+		"4 | ###\n" +
+		"  | ###\n" +
+		"  | ###     ###\n" +
+		"  | ###     ###\n" +
+		"0 +------------\n" +
+		"    Jan Feb Mar\n"
+	assert.Equal(t, expected, result)
+
+	// Test case 2: Small values are visible, labels are cut to the bar width
+	result, err = BarChart([]string{"January", "February"}, []int{100, 1}, 2)
+	assert.NoError(t, err)
+	expected = "" +
+		"100 | ###\n" +
+		"    | ### ###\n" +
+		"  0 +--------\n" +
+		"      Jan Feb\n"
+	assert.Equal(t, expected, result)
+
+	// Test case 3: All values are zero
+	result, err = BarChart([]string{"A", "B"}, []int{0, 0}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "0 |\n0 +--------\n    A   B\n", result)
+	assert.Len(t, strings.Split(strings.TrimSuffix(result, "\n"), "\n"), 3)
+
+	// Test case 4: Invalid input
+	_, err = BarChart([]string{"A"}, []int{1, 2}, 3)
+	assert.ErrorContains(t, err, "got 1 labels for 2 values")
+	_, err = BarChart([]string{"A"}, []int{1}, 0)
+	assert.ErrorContains(t, err, "chart height must be at least 1")
+	_, err = BarChart([]string{"A"}, []int{-1}, 3)
+	assert.ErrorContains(t, err, "chart values cannot be negative")
+}
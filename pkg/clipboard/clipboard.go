@@ -0,0 +1,34 @@
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// newCommand is replaced in tests.
+var newCommand = exec.Command
+
+// commandFor returns the command copying its standard input to the clipboard on the given operating system.
+func commandFor(goos string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "pbcopy", nil
+	case "windows":
+		return "clip.exe", nil
+	default:
+		return "xclip", []string{"-selection", "clipboard"}
+	}
+}
+
+// Copy copies text to the system clipboard, using pbcopy on macOS, clip.exe on Windows and xclip elsewhere.
+func Copy(text string) error {
+	name, args := commandFor(runtime.GOOS)
+	cmd := newCommand(name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard with %s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package clipboard
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandFor(t *testing.T) {
+	name, args := commandFor("darwin")
+	assert.Equal(t, "pbcopy", name)
+	assert.Empty(t, args)
+
+	name, args = commandFor("windows")
+	assert.Equal(t, "clip.exe", name)
+	assert.Empty(t, args)
+
+	name, args = commandFor("linux")
+	assert.Equal(t, "xclip", name)
+	assert.Equal(t, []string{"-selection", "clipboard"}, args)
+}
+
+func TestCopy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the clipboard command is a shell script in this test")
+	}
+
+	origNewCommand := newCommand
+	defer func() { newCommand = origNewCommand }()
+
+	// Test case 1: The text is written to the standard input of the clipboard command
+	output := filepath.Join(t.TempDir(), "clipboard")
+	newCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "cat > "+output)
+	}
+	err := Copy("func main() {}\n")
+	assert.NoError(t, err)
+	content, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, "func main() {}\n", string(content))
+
+	// Test case 2: The clipboard command fails
+	newCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo 'Error: cannot open display' >&2; exit 1")
+	}
+	err = Copy("text")
+	assert.ErrorContains(t, err, "failed to copy to the clipboard")
+	assert.ErrorContains(t, err, "cannot open display")
+}
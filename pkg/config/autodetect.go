@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ErrNoAICommandFound is returned by AutoDetectAICommand when none of the known AI tools works.
+var ErrNoAICommandFound = errors.New("no working AI command found")
+
+// knownAICommands are the AI command line tools probed by AutoDetectAICommand, in order,
+// with the AICommand template used for each of them. The placeholders are single quoted: the command is run
+// by a shell, and only single quotes keep it from expanding the text.
+var knownAICommands = []struct {
+	binary   string
+	template string
+}{
+	{"gemini", "gemini --prompt '{PROMPT} {TEXT}'"},
+	{"claude", "claude --print '{PROMPT} {TEXT}'"},
+	{"ollama", "ollama run llama2 '{PROMPT} {TEXT}'"},
+	{"llm", "llm '{PROMPT} {TEXT}'"},
+	{"sgpt", "sgpt '{PROMPT} {TEXT}'"},
+}
+
+// lookPath and runVersion are replaced in tests.
+var (
+	lookPath   = exec.LookPath
+	runVersion = func(path string) error {
+		return exec.Command(path, "--version").Run()
+	}
+)
+
+// AutoDetectAICommand returns the AICommand template of the first known AI tool found on $PATH
+// that runs successfully with --version. The tools are tried in order: gemini, claude, ollama, llm and sgpt.
+func AutoDetectAICommand() (string, error) {
+	for _, known := range knownAICommands {
+		path, err := lookPath(known.binary)
+		if err != nil {
+			continue
+		}
+		if err := runVersion(path); err != nil {
+			continue
+		}
+		return known.template, nil
+	}
+	return "", ErrNoAICommandFound
+}
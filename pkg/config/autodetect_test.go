@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoDetectAICommand(t *testing.T) {
+	origLookPath, origRunVersion := lookPath, runVersion
+	defer func() { lookPath, runVersion = origLookPath, origRunVersion }()
+
+	// mock installs the given binaries, the broken ones fail to run --version
+	mock := func(installed []string, broken []string) *[]string {
+		var probed []string
+		lookPath = func(file string) (string, error) {
+			probed = append(probed, file)
+			for _, name := range installed {
+				if name == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+		runVersion = func(path string) error {
+			for _, name := range broken {
+				if path == "/usr/bin/"+name {
+					return errors.New("exit status 1")
+				}
+			}
+			return nil
+		}
+		return &probed
+	}
+
+	// Test case 1: The first tool found is used
+	probed := mock([]string{"claude", "gemini", "sgpt"}, nil)
+	command, err := AutoDetectAICommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "gemini --prompt '{PROMPT} {TEXT}'", command)
+	assert.Equal(t, []string{"gemini"}, *probed)
+
+	// Test case 2: Tools are probed in order
+	probed = mock([]string{"sgpt", "llm"}, nil)
+	command, err = AutoDetectAICommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "llm '{PROMPT} {TEXT}'", command)
+	assert.Equal(t, []string{"gemini", "claude", "ollama", "llm"}, *probed)
+
+	// Test case 3: A tool failing to run --version is skipped
+	mock([]string{"gemini", "claude"}, []string{"gemini"})
+	command, err = AutoDetectAICommand()
+	assert.NoError(t, err)
+	assert.Equal(t, "claude --print '{PROMPT} {TEXT}'", command)
+
+	// Test case 4: No working tool
+	probed = mock([]string{"ollama"}, []string{"ollama"})
+	_, err = AutoDetectAICommand()
+	assert.ErrorIs(t, err, ErrNoAICommandFound)
+	assert.Equal(t, []string{"gemini", "claude", "ollama", "llm", "sgpt"}, *probed)
+}
+
+func TestKnownAICommandsQuoting(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "injected")
+	text := "Ran $(touch " + marker + ") and `touch " + marker + "`, it's \"done\""
+
+	// Test case 1: The text reaches the tool as it is, the shell does not run what is in it
+	for _, known := range knownAICommands {
+		summarizer := &ai.ExternalAISummarizer{CommandTemplate: "echo" + strings.TrimPrefix(known.template, known.binary)}
+		output, err := summarizer.GenerateSummary(context.Background(), text, "Summarize")
+		assert.NoError(t, err, known.binary)
+		assert.True(t, strings.HasSuffix(output, "Summarize "+text), known.binary)
+		_, err = os.Stat(marker)
+		assert.True(t, os.IsNotExist(err), known.binary)
+	}
+}
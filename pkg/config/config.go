@@ -2,37 +2,156 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"os/user"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/template"
+)
+
+// Supported values of Config.LogEntryOrder.
+const (
+	LogEntryOrderAppend  = "append"
+	LogEntryOrderPrepend = "prepend"
 )
 
 // Config represents the application's configuration.
 type Config struct {
-	JournalDir       string `toml:"journal_dir"`
-	DailyFileName    string `toml:"daily_file_name"`
-	DailyTemplate    string `toml:"daily_template"`
-	LogEntryTemplate string `toml:"log_entry_template"`
-	AIEnabled        bool   `toml:"ai_enabled"`
-	AICommand        string `toml:"ai_command"`
-	AIPrompt         string `toml:"ai_prompt"`
-	OneLineTemplate  string `toml:"one_line_template"`
-	AISummarizer     ai.AISummarizer `toml:"-"` // Not serialized to TOML
+	JournalDir                  string                `toml:"journal_dir"`
+	ReviewDir                   string                `toml:"review_dir"` // Directory of the review files, empty for JournalDir
+	DailyFileName               string                `toml:"daily_file_name"`
+	DailyTemplate               string                `toml:"daily_template"`
+	DailyTemplateFile           string                `toml:"daily_template_file"` // Optional path to a Markdown file used instead of DailyTemplate
+	LogEntryTemplate            string                `toml:"log_entry_template"`
+	LogEntryOrder               string                `toml:"log_entry_order"`     // Either "append" (oldest first) or "prepend" (newest first)
+	TrimEntries                 bool                  `toml:"trim_entries"`        // Remove trailing spaces and tabs from new log entries
+	EntryDatePrefix             string                `toml:"entry_date_prefix"`   // Go date layout written before the entry time by "log --prepend-date", e.g. "Mon "
+	EntryPrefix                 string                `toml:"entry_prefix"`        // Template written before the text of every log entry, e.g. "[alice]"
+	LogEntrySeparator           string                `toml:"log_entry_separator"` // Line written between log entries, e.g. "---"
+	DayBoundaryHour             int                   `toml:"day_boundary_hour"`   // Hour the day starts at, e.g. 4 to log between 00:00 and 03:59 in the previous day file
+	Timezone                    string                `toml:"timezone"`            // Timezone of the journal dates and times, e.g. "America/New_York", empty for the system one
+	WeatherAutoPrefix           bool                  `toml:"weather_auto_prefix"` // Prepend the current weather to every log entry, as "log --weather"
+	WeatherLocation             string                `toml:"weather_location"`    // City of the weather, empty to detect it from the IP address
+	WeatherTimeout              time.Duration         `toml:"weather_timeout"`     // Maximum duration of the weather lookup, e.g. "3s"
+	AIEnabled                   bool                  `toml:"ai_enabled"`
+	AICommand                   string                `toml:"ai_command"`
+	AIBackend                   string                `toml:"ai_backend"`       // One of "command" (AICommand), "openai", "anthropic" or "ollama", empty for "command"
+	AIAPIKey                    string                `toml:"ai_api_key"`       // API key of the "openai" and "anthropic" backends
+	AIModel                     string                `toml:"ai_model"`         // Model of the "openai", "anthropic" and "ollama" backends, empty for the backend default
+	AIOllamaURL                 string                `toml:"ai_ollama_url"`    // Address of the Ollama server, empty for "http://localhost:11434"
+	AutoDetectedAI              bool                  `toml:"auto_detected_ai"` // AICommand was set by AutoDetectAICommand
+	AIPrompt                    string                `toml:"ai_prompt"`
+	DefaultAIProfile            string                `toml:"default_ai_profile"` // Name of the AIProfiles entry used instead of AICommand, e.g. "gemini"
+	AITitleEnabled              bool                  `toml:"ai_title_enabled"`   // Prefix the daily file title with an AI generated description of the day
+	AITitlePrompt               string                `toml:"ai_title_prompt"`
+	LearningExtractionPrompt    string                `toml:"learning_extraction_prompt"`
+	WeeklyInsightPrompt         string                `toml:"weekly_insight_prompt"` // Asks the AI for the key insight of the weekly review
+	ReviewWeekPrompt            string                `toml:"review_week_prompt"`    // Prompt of the weekly review summary, e.g. to write it in another language
+	ReviewMonthPrompt           string                `toml:"review_month_prompt"`
+	ReviewYearPrompt            string                `toml:"review_year_prompt"`
+	AutoReSummarizeAfterEntries int                   `toml:"auto_resummarize_after_entries"` // Regenerate the daily summary every N log entries, 0 to disable
+	AIMaxContextTokens          int                   `toml:"ai_max_context_tokens"`          // Maximum size of the text sent to the AI, approximated in words
+	DefaultAILanguage           string                `toml:"default_ai_language"`            // Language of AI generated review summaries, e.g. "Spanish"
+	OneLineTemplate             string                `toml:"one_line_template"`
+	ColorTheme                  string                `toml:"color_theme"`            // One of "default", "solarized", "dracula" or "none"
+	ChartHeight                 int                   `toml:"chart_height"`           // Number of rows of the ASCII charts in reviews
+	WorkingDaysPerWeek          int                   `toml:"working_days_per_week"`  // Either 5 or 7, the days with entries expected by the productivity score
+	DefaultWordGoal             int                   `toml:"default_word_goal"`      // Number of words per week expected by the productivity score, 0 to ignore words
+	IssueLinkPattern            string                `toml:"issue_link_pattern"`     // Regular expression of issue references in reviews, with an "id" named group, e.g. "(?P<id>JIRA-\\d+)"
+	IssueLinkTemplate           string                `toml:"issue_link_template"`    // URL of a referenced issue, e.g. "https://jira.example.com/browse/{{.ID}}"
+	PreserveCRLF                bool                  `toml:"preserve_crlf"`          // Write journal files with Windows line endings (Windows only)
+	EncryptSummary              bool                  `toml:"encrypt_summary"`        // Store the generated summaries encrypted with SummaryPassphrase
+	Encrypt                     bool                  `toml:"encrypt"`                // Encrypt the daily journal files at rest with the key of EncryptionKeyFile
+	EncryptionKeyFile           string                `toml:"encryption_key_file"`    // File with the 32 bytes key of Encrypt, as hexadecimal, e.g. "~/.config/logbook/journal.key"
+	Habits                      []string              `toml:"habits"`                 // Habits tracked by the monthly review, e.g. ["exercise", "reading"]
+	Sections                    map[string]bool       `toml:"sections"`               // Optional sections, e.g. [sections] mood = true, see SectionEnabled
+	AIProfiles                  map[string]AIProfile  `toml:"ai_profiles"`            // Named AI commands, e.g. [ai_profiles.gemini]
+	ReviewCustomSections        []ReviewCustomSection `toml:"review_custom_sections"` // Sections added to every review, e.g. [[review_custom_sections]]
+	Journals                    []Journal             `toml:"journals"`               // Named journals selected with --journal, e.g. [[journals]], see GetJournalConfig
+	Hooks                       Hooks                 `toml:"hooks"`                  // Shell commands run before and after log and review, e.g. [hooks] post_log = "..."
+	AISummarizer                ai.AISummarizer       `toml:"-"`                      // Not serialized to TOML
+	SummaryPassphrase           string                `toml:"-"`                      // Passphrase of EncryptSummary, never stored
+}
+
+// Supported values of ReviewCustomSection.Position.
+const (
+	ReviewSectionBeforeSummary       = "before-summary"
+	ReviewSectionAfterSummary        = "after-summary"
+	ReviewSectionAfterDailySummaries = "after-daily-summaries"
+)
+
+// ReviewCustomSection is a user defined section added to the weekly, monthly and yearly reviews.
+// Template is rendered with the review data, e.g. {{.Period}}, and can be empty to only add the header.
+type ReviewCustomSection struct {
+	Title    string `toml:"title"`
+	Position string `toml:"position"` // One of "before-summary", "after-summary" or "after-daily-summaries"
+	Template string `toml:"template"`
+	Enabled  bool   `toml:"enabled"`
+}
+
+// AIDriverCommand is the only supported AIProfile.Driver: the profile runs an external command.
+const AIDriverCommand = "command"
+
+// AIProfile is a named AI configuration, so that several AI tools can be configured and selected by name.
+// Command is a template as AICommand; besides {PROMPT} and {TEXT} it can reference {MODEL}, {BASE_URL} and {API_KEY}.
+type AIProfile struct {
+	Command string `toml:"command"`
+	Prompt  string `toml:"prompt"` // Overrides AIPrompt when this is the DefaultAIProfile
+	Model   string `toml:"model"`
+	BaseURL string `toml:"base_url"`
+	APIKey  string `toml:"api_key"`
+	Driver  string `toml:"driver"` // Empty or "command"
+
+	Summarizer ai.AISummarizer `toml:"-"` // Built by LoadConfig, can be replaced in tests
+}
+
+// commandTemplate returns the profile command with the profile settings filled in.
+func (p AIProfile) commandTemplate() string {
+	return strings.NewReplacer("{MODEL}", p.Model, "{BASE_URL}", p.BaseURL, "{API_KEY}", p.APIKey).Replace(p.Command)
 }
 
 // DefaultConfig returns a new Config with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		JournalDir:       filepath.Join(os.Getenv("HOME"), ".logbook", "journal"),
-		DailyFileName:    "{{.Date | formatDate \"2006-01-02\"}}.md",
-		DailyTemplate:    "# {{.Date | formatDate \"Jan 02 2006 Monday\"}}\n<!-- add today summary below this line. If missing, the AI will generate one for you according to configuration file -->\n\n# One-line note\n\n# LOG\n\n",
-		LogEntryTemplate: "{{.Time | formatTime \"15:04\"}} {{.Entry}}",
-		AIEnabled:        false,
-		AICommand:        "", // Example: "gemini --prompt '{PROMPT} {TEXT}'" or "claude --text '{TEXT}' --instructions '{PROMPT}'"
-		AIPrompt:         "Write a summary of the note at the given file. Use 1st person and a simple language. Use 200 characters or less",
-		OneLineTemplate:  "{{.Date | formatDate \"2006-01-02\"}}: {{.Summary}}",
+		JournalDir:                  filepath.Join(os.Getenv("HOME"), ".logbook", "journal"),
+		DailyFileName:               "{{.Date | formatDate \"2006-01-02\"}}.md",
+		DailyTemplate:               "# {{.Date | formatDate \"Jan 02 2006 Monday\"}}\n<!-- add today summary below this line. If missing, the AI will generate one for you according to configuration file -->\n\n# One-line note\n\n# LOG\n\n",
+		LogEntryTemplate:            "{{.Time | formatTime \"15:04\"}} {{.Entry}}",
+		LogEntryOrder:               LogEntryOrderAppend,
+		TrimEntries:                 false,
+		EntryDatePrefix:             "",
+		EntryPrefix:                 "",
+		LogEntrySeparator:           "",
+		DayBoundaryHour:             0,
+		WeatherAutoPrefix:           false,
+		WeatherLocation:             "",
+		WeatherTimeout:              3 * time.Second,
+		AIEnabled:                   false,
+		AICommand:                   "", // Example: "gemini --prompt '{PROMPT} {TEXT}'" or "claude --text '{TEXT}' --instructions '{PROMPT}'"
+		AIPrompt:                    "Write a summary of the note at the given file. Use 1st person and a simple language. Use 200 characters or less",
+		AITitleEnabled:              false,
+		AITitlePrompt:               "Write a short title for the work described in the following log. Use 60 characters or less and reply with the title only",
+		LearningExtractionPrompt:    "Extract the distinct technologies, methodologies and tools mentioned in the following journal entries. Reply with a bullet list only, one item per line",
+		WeeklyInsightPrompt:         "In one sentence, what was the single most important thing that happened this week?",
+		ReviewWeekPrompt:            "Write a summary of the weekly review using the same Language. Use 1st person and a simple language. Use 200 characters or less.",
+		ReviewMonthPrompt:           "Write a summary of the monthly review. Use 1st person and a simple language. Use 200 characters or less.",
+		ReviewYearPrompt:            "Write a summary of the yearly review. Use 1st person and a simple language. Use 200 characters or less.",
+		AutoReSummarizeAfterEntries: 0,
+		AIMaxContextTokens:          8000,
+		OneLineTemplate:             "{{.Date | formatDate \"2006-01-02\"}}: {{.Summary}}",
+		ColorTheme:                  "default",
+		ChartHeight:                 10,
+		WorkingDaysPerWeek:          5,
+		DefaultWordGoal:             500,
+		PreserveCRLF:                false,
+		EncryptSummary:              false,
 	}
 }
 
@@ -43,14 +162,54 @@ func LoadConfig(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode config file %s: %w", path, err)
 	}
+	if err := migrateLegacySections(path, cfg); err != nil {
+		return nil, err
+	}
+	cfg.JournalDir = ExpandPath(cfg.JournalDir)
+	cfg.ReviewDir = ExpandPath(cfg.ReviewDir)
+	cfg.EncryptionKeyFile = ExpandPath(cfg.EncryptionKeyFile)
+	for i := range cfg.Journals {
+		cfg.Journals[i].JournalDir = ExpandPath(cfg.Journals[i].JournalDir)
+		cfg.Journals[i].ReviewDir = ExpandPath(cfg.Journals[i].ReviewDir)
+	}
+
+	for name, profile := range cfg.AIProfiles {
+		profile.Summarizer = ai.NewAISummarizer(ai.Settings{CommandTemplate: profile.commandTemplate()})
+		cfg.AIProfiles[name] = profile
+	}
 
 	if cfg.AIEnabled {
-		cfg.AISummarizer = ai.NewAISummarizer(cfg.AICommand)
+		if profile, ok := cfg.AIProfiles[cfg.DefaultAIProfile]; ok {
+			cfg.AISummarizer = profile.Summarizer
+			if profile.Prompt != "" {
+				cfg.AIPrompt = profile.Prompt
+			}
+		} else {
+			cfg.AISummarizer = ai.NewAISummarizer(ai.Settings{
+				Backend:         cfg.AIBackend,
+				CommandTemplate: cfg.AICommand,
+				APIKey:          cfg.AIAPIKey,
+				Model:           cfg.AIModel,
+				BaseURL:         cfg.AIOllamaURL,
+			})
+		}
 	}
 
 	return cfg, nil
 }
 
+// ExpandPath expands the $VAR and ${VAR} environment variables of a path, unset ones expanding to an empty string,
+// then a leading "~" to the home directory of the current user.
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		if usr, err := user.Current(); err == nil {
+			path = usr.HomeDir + path[1:]
+		}
+	}
+	return path
+}
+
 // SaveConfig saves configuration to a TOML file.
 func SaveConfig(path string, cfg *Config) error {
 	f, err := os.Create(path)
@@ -71,20 +230,274 @@ func (cfg *Config) Validate() error {
 	if cfg.JournalDir == "" {
 		return fmt.Errorf("JournalDir cannot be empty")
 	}
+	// An unset environment variable of ExpandPath leaves an empty component, e.g. "/home/me/$UNSET/journal"
+	if strings.Contains(filepath.ToSlash(cfg.JournalDir)[1:], "//") {
+		return fmt.Errorf("JournalDir has an empty path component, is an environment variable unset? %s", cfg.JournalDir)
+	}
+	if cfg.ReviewDir != "" && !filepath.IsAbs(cfg.ReviewDir) {
+		return fmt.Errorf("ReviewDir must be an absolute path: %s", cfg.ReviewDir)
+	}
 	if cfg.DailyFileName == "" {
 		return fmt.Errorf("DailyFileName cannot be empty")
 	}
+	if _, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: time.Now()}); err != nil {
+		return fmt.Errorf("DailyFileName is not a valid template: %w", err)
+	}
 	if cfg.DailyTemplate == "" {
 		return fmt.Errorf("DailyTemplate cannot be empty")
 	}
+	if _, err := template.Render(cfg.DailyTemplate, template.TemplateData{Date: time.Now()}); err != nil {
+		return fmt.Errorf("DailyTemplate is not a valid template: %w", err)
+	}
+	if cfg.DailyTemplateFile != "" {
+		content, err := os.ReadFile(cfg.DailyTemplateFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("DailyTemplateFile does not exist: %s", cfg.DailyTemplateFile)
+			}
+			return fmt.Errorf("failed to read DailyTemplateFile %s: %w", cfg.DailyTemplateFile, err)
+		}
+		if _, err := template.Render(string(content), template.TemplateData{Date: time.Now()}); err != nil {
+			return fmt.Errorf("DailyTemplateFile %s is not a valid template: %w", cfg.DailyTemplateFile, err)
+		}
+	}
 	if cfg.LogEntryTemplate == "" {
 		return fmt.Errorf("LogEntryTemplate cannot be empty")
 	}
+	if _, err := template.Render(cfg.LogEntryTemplate, template.TemplateData{Date: time.Now(), Time: time.Now()}); err != nil {
+		return fmt.Errorf("LogEntryTemplate is not a valid template: %w", err)
+	}
+	if cfg.LogEntryOrder != "" && cfg.LogEntryOrder != LogEntryOrderAppend && cfg.LogEntryOrder != LogEntryOrderPrepend {
+		return fmt.Errorf("LogEntryOrder must be either %q or %q, got %q", LogEntryOrderAppend, LogEntryOrderPrepend, cfg.LogEntryOrder)
+	}
+	if cfg.EntryPrefix != "" {
+		if _, err := template.Render(cfg.EntryPrefix, template.TemplateData{Date: time.Now(), Time: time.Now()}); err != nil {
+			return fmt.Errorf("EntryPrefix is not a valid template: %w", err)
+		}
+	}
+	if cfg.DayBoundaryHour < 0 || cfg.DayBoundaryHour > 23 {
+		return fmt.Errorf("DayBoundaryHour must be between 0 and 23, got %d", cfg.DayBoundaryHour)
+	}
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return fmt.Errorf("Timezone %q is not a valid timezone: %w", cfg.Timezone, err)
+		}
+	}
+	if cfg.WeatherTimeout < 0 {
+		return fmt.Errorf("WeatherTimeout cannot be negative")
+	}
+	if cfg.ChartHeight < 0 {
+		return fmt.Errorf("ChartHeight cannot be negative")
+	}
 	if cfg.AIEnabled && cfg.AIPrompt == "" {
 		return fmt.Errorf("AIPrompt cannot be empty if AI is enabled")
 	}
-	if cfg.AIEnabled && cfg.AICommand == "" {
-		return fmt.Errorf("AICommand cannot be empty if AI is enabled")
+	if cfg.AIEnabled && (cfg.ReviewWeekPrompt == "" || cfg.ReviewMonthPrompt == "" || cfg.ReviewYearPrompt == "") {
+		return fmt.Errorf("ReviewWeekPrompt, ReviewMonthPrompt and ReviewYearPrompt cannot be empty if AI is enabled")
+	}
+	if cfg.WorkingDaysPerWeek != 5 && cfg.WorkingDaysPerWeek != 7 {
+		return fmt.Errorf("WorkingDaysPerWeek must be either 5 or 7, got %d", cfg.WorkingDaysPerWeek)
+	}
+	if cfg.DefaultWordGoal < 0 {
+		return fmt.Errorf("DefaultWordGoal cannot be negative")
+	}
+	if cfg.AITitleEnabled && cfg.AITitlePrompt == "" {
+		return fmt.Errorf("AITitlePrompt cannot be empty if AI title is enabled")
+	}
+	if cfg.AutoReSummarizeAfterEntries < 0 {
+		return fmt.Errorf("AutoReSummarizeAfterEntries cannot be negative")
+	}
+	if cfg.AIMaxContextTokens < 0 {
+		return fmt.Errorf("AIMaxContextTokens cannot be negative")
+	}
+	switch cfg.AIBackend {
+	case "", ai.BackendCommand:
+		if cfg.AIEnabled && cfg.AICommand == "" && cfg.DefaultAIProfile == "" {
+			return fmt.Errorf("AICommand cannot be empty if AI is enabled")
+		}
+	case ai.BackendOpenAI, ai.BackendAnthropic:
+		if cfg.AIEnabled && cfg.AIAPIKey == "" && cfg.DefaultAIProfile == "" {
+			return fmt.Errorf("AIAPIKey cannot be empty if AI is enabled with the %q backend", cfg.AIBackend)
+		}
+	case ai.BackendOllama:
+		if cfg.AIOllamaURL != "" {
+			if u, err := url.Parse(cfg.AIOllamaURL); err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("AIOllamaURL must be an address like \"http://localhost:11434\", got %q", cfg.AIOllamaURL)
+			}
+		}
+	default:
+		return fmt.Errorf("AIBackend must be one of %q, %q, %q or %q, got %q", ai.BackendCommand, ai.BackendOpenAI, ai.BackendAnthropic, ai.BackendOllama, cfg.AIBackend)
+	}
+	if cfg.DefaultAIProfile != "" {
+		if _, ok := cfg.AIProfiles[cfg.DefaultAIProfile]; !ok {
+			return fmt.Errorf("DefaultAIProfile %q is not defined in AIProfiles", cfg.DefaultAIProfile)
+		}
+	}
+	if err := validateSections(cfg.Sections); err != nil {
+		return err
+	}
+	for _, section := range cfg.ReviewCustomSections {
+		if section.Title == "" {
+			return fmt.Errorf("ReviewCustomSections: title cannot be empty")
+		}
+		switch section.Position {
+		case ReviewSectionBeforeSummary, ReviewSectionAfterSummary, ReviewSectionAfterDailySummaries:
+		default:
+			return fmt.Errorf("ReviewCustomSections %q: position must be one of %q, %q or %q, got %q", section.Title,
+				ReviewSectionBeforeSummary, ReviewSectionAfterSummary, ReviewSectionAfterDailySummaries, section.Position)
+		}
+	}
+	if cfg.Encrypt {
+		if cfg.EncryptionKeyFile == "" {
+			return fmt.Errorf("EncryptionKeyFile cannot be empty if Encrypt is enabled")
+		}
+		if _, err := os.Stat(cfg.EncryptionKeyFile); err != nil {
+			return fmt.Errorf("EncryptionKeyFile does not exist: %s", cfg.EncryptionKeyFile)
+		}
+	}
+	if err := validateJournals(cfg.Journals); err != nil {
+		return err
+	}
+	for name, profile := range cfg.AIProfiles {
+		if profile.Command == "" {
+			return fmt.Errorf("AI profile %q: command cannot be empty", name)
+		}
+		if profile.Driver != "" && profile.Driver != AIDriverCommand {
+			return fmt.Errorf("AI profile %q: unsupported driver %q", name, profile.Driver)
+		}
+	}
+	return nil
+}
+
+// ReviewDirectory returns the directory of the review files: ReviewDir, or JournalDir if ReviewDir is empty.
+func (cfg *Config) ReviewDirectory() string {
+	if cfg.ReviewDir == "" {
+		return cfg.JournalDir
+	}
+	return cfg.ReviewDir
+}
+
+// Location returns the location of Timezone, or time.Local if Timezone is empty or invalid.
+func (cfg *Config) Location() *time.Location {
+	if cfg.Timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// AISummarizerFor returns the summarizer of the named AI profile, or cfg.AISummarizer if the name is empty.
+func (cfg *Config) AISummarizerFor(profileName string) (ai.AISummarizer, error) {
+	if profileName == "" {
+		return cfg.AISummarizer, nil
+	}
+	profile, ok := cfg.AIProfiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("unknown AI profile: %s", profileName)
+	}
+	if profile.Summarizer == nil {
+		return ai.NewAISummarizer(ai.Settings{CommandTemplate: profile.commandTemplate()}), nil
+	}
+	return profile.Summarizer, nil
+}
+
+// ValidateAll runs Validate and additionally checks that every template only references TemplateData fields
+// and only uses safe functions. The templates are checked first, as their errors are more precise than
+// the rendering errors of Validate.
+func (cfg *Config) ValidateAll() error {
+	templates := []struct {
+		name  string
+		value string
+	}{
+		{"DailyFileName", cfg.DailyFileName},
+		{"DailyTemplate", cfg.DailyTemplate},
+		{"LogEntryTemplate", cfg.LogEntryTemplate},
+		{"EntryPrefix", cfg.EntryPrefix},
+		{"OneLineTemplate", cfg.OneLineTemplate},
+	}
+	allowed := template.FieldNames()
+	for _, tmpl := range templates {
+		if err := template.ValidateNoCycle(tmpl.value, allowed); err != nil {
+			return fmt.Errorf("%s is not a valid template: %w", tmpl.name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.DailyTemplateFile != "" {
+		content, err := os.ReadFile(cfg.DailyTemplateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read DailyTemplateFile %s: %w", cfg.DailyTemplateFile, err)
+		}
+		if err := template.ValidateNoCycle(string(content), allowed); err != nil {
+			return fmt.Errorf("DailyTemplateFile is not a valid template: %w", err)
+		}
+	}
+
+	if cfg.IssueLinkPattern != "" {
+		pattern, err := regexp.Compile(cfg.IssueLinkPattern)
+		if err != nil {
+			return fmt.Errorf("IssueLinkPattern is not a valid regular expression: %w", err)
+		}
+		if pattern.SubexpIndex("id") == -1 {
+			return fmt.Errorf("IssueLinkPattern must have a named group \"id\", e.g. (?P<id>JIRA-\\d+)")
+		}
+		if cfg.IssueLinkTemplate == "" {
+			return fmt.Errorf("IssueLinkTemplate cannot be empty if IssueLinkPattern is set")
+		}
+		if err := template.ValidateNoCycle(cfg.IssueLinkTemplate, []string{"ID"}); err != nil {
+			return fmt.Errorf("IssueLinkTemplate is not a valid template: %w", err)
+		}
 	}
 	return nil
 }
+
+// TemplateFieldInfo describes a template field of the configuration.
+type TemplateFieldInfo struct {
+	Name         string
+	TOMLKey      string
+	CurrentValue string
+	DefaultValue string
+	IsDefault    bool
+}
+
+// templateFieldNames are the Config fields holding a template.
+var templateFieldNames = []string{"DailyFileName", "DailyTemplate", "LogEntryTemplate", "OneLineTemplate"}
+
+// ListTemplateFields returns the template fields of cfg with their current and default values.
+func ListTemplateFields(cfg *Config) []TemplateFieldInfo {
+	current := reflect.ValueOf(cfg).Elem()
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+
+	fields := make([]TemplateFieldInfo, 0, len(templateFieldNames))
+	for _, name := range templateFieldNames {
+		structField, _ := current.Type().FieldByName(name)
+		info := TemplateFieldInfo{
+			Name:         name,
+			TOMLKey:      structField.Tag.Get("toml"),
+			CurrentValue: current.FieldByName(name).String(),
+			DefaultValue: defaults.FieldByName(name).String(),
+		}
+		info.IsDefault = info.CurrentValue == info.DefaultValue
+		fields = append(fields, info)
+	}
+	return fields
+}
+
+// PreviewDate is the sample date and time used by PreviewTemplate.
+var PreviewDate = time.Date(2025, time.September, 18, 14, 30, 0, 0, time.UTC)
+
+// PreviewTemplate renders a template with sample data, to show what it produces.
+func PreviewTemplate(templateStr string) (string, error) {
+	return template.Render(templateStr, template.TemplateData{
+		Date:    PreviewDate,
+		Time:    PreviewDate,
+		Summary: "Sample summary of the day.",
+		Entry:   "Sample log entry.",
+	})
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/clobrano/LogBook/pkg/ai"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,13 +74,57 @@ func TestSaveConfig(t *testing.T) {
 	assert.NoError(t, err)
 
 	expectedContent := `journal_dir = "/path/to/journal"
+review_dir = ""
 daily_file_name = "{{.Date | formatDate \"2006-01-02\"}}.md"
 daily_template = "# {{.Date | formatDate \"Jan 02 2006 Monday\"}}\n<!-- add today summary below this line. If missing, the AI will generate one for you according to configuration file -->\n\n# One-line note\n\n# LOG\n\n"
+daily_template_file = ""
 log_entry_template = "{{.Time | formatTime \"15:04\"}} {{.Entry}}"
+log_entry_order = "append"
+trim_entries = false
+entry_date_prefix = ""
+entry_prefix = ""
+log_entry_separator = ""
+day_boundary_hour = 0
+timezone = ""
+weather_auto_prefix = false
+weather_location = ""
+weather_timeout = "3s"
 ai_enabled = true
 ai_command = ""
+ai_backend = ""
+ai_api_key = ""
+ai_model = ""
+ai_ollama_url = ""
+auto_detected_ai = false
 ai_prompt = "Write a summary of the note at the given file. Use 1st person and a simple language. Use 200 characters or less"
+default_ai_profile = ""
+ai_title_enabled = false
+ai_title_prompt = "Write a short title for the work described in the following log. Use 60 characters or less and reply with the title only"
+learning_extraction_prompt = "Extract the distinct technologies, methodologies and tools mentioned in the following journal entries. Reply with a bullet list only, one item per line"
+weekly_insight_prompt = "In one sentence, what was the single most important thing that happened this week?"
+review_week_prompt = "Write a summary of the weekly review using the same Language. Use 1st person and a simple language. Use 200 characters or less."
+review_month_prompt = "Write a summary of the monthly review. Use 1st person and a simple language. Use 200 characters or less."
+review_year_prompt = "Write a summary of the yearly review. Use 1st person and a simple language. Use 200 characters or less."
+auto_resummarize_after_entries = 0
+ai_max_context_tokens = 8000
+default_ai_language = ""
 one_line_template = "{{.Date | formatDate \"2006-01-02\"}}: {{.Summary}}"
+color_theme = "default"
+chart_height = 10
+working_days_per_week = 5
+default_word_goal = 500
+issue_link_pattern = ""
+issue_link_template = ""
+preserve_crlf = false
+encrypt_summary = false
+encrypt = false
+encryption_key_file = ""
+
+[hooks]
+  pre_log = ""
+  post_log = ""
+  pre_review = ""
+  post_review = ""
 `
 	assert.Equal(t, expectedContent, string(content))
 
@@ -98,6 +145,25 @@ func TestConfigValidate(t *testing.T) {
 	assert.ErrorContains(t, cfg.Validate(), "JournalDir cannot be empty")
 	cfg = DefaultConfig() // Reset
 
+	// Test relative ReviewDir
+	cfg.ReviewDir = "reviews"
+	assert.ErrorContains(t, cfg.Validate(), "ReviewDir must be an absolute path: reviews")
+	cfg.ReviewDir = "/tmp/reviews"
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "/tmp/reviews", cfg.ReviewDirectory())
+	cfg = DefaultConfig() // Reset
+	assert.Equal(t, cfg.JournalDir, cfg.ReviewDirectory())
+
+	// Test Encrypt without a key file
+	cfg.Encrypt = true
+	assert.ErrorContains(t, cfg.Validate(), "EncryptionKeyFile cannot be empty if Encrypt is enabled")
+	cfg.EncryptionKeyFile = "/nonexistent/journal.key"
+	assert.ErrorContains(t, cfg.Validate(), "EncryptionKeyFile does not exist: /nonexistent/journal.key")
+	cfg.EncryptionKeyFile = filepath.Join(t.TempDir(), "journal.key")
+	os.WriteFile(cfg.EncryptionKeyFile, []byte("00"), 0600)
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
 	// Test empty DailyFileName
 	cfg.DailyFileName = ""
 	assert.ErrorContains(t, cfg.Validate(), "DailyFileName cannot be empty")
@@ -108,9 +174,333 @@ func TestConfigValidate(t *testing.T) {
 	assert.ErrorContains(t, cfg.Validate(), "DailyTemplate cannot be empty")
 	cfg = DefaultConfig() // Reset
 
+	// Test malformed DailyFileName, DailyTemplate and LogEntryTemplate
+	cfg.DailyFileName = "{{.Date | formatDate \"2006-01-02\"}.md"
+	assert.ErrorContains(t, cfg.Validate(), "DailyFileName is not a valid template: ")
+	cfg = DefaultConfig() // Reset
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006\"}}\n\n{{if .Summary}}{{.Summary}}\n\n# LOG\n"
+	assert.ErrorContains(t, cfg.Validate(), "DailyTemplate is not a valid template: ")
+	cfg = DefaultConfig() // Reset
+	cfg.LogEntryTemplate = "{{.Time | formatDate}} {{.Entry}}"
+	assert.ErrorContains(t, cfg.Validate(), "LogEntryTemplate is not a valid template: ")
+	cfg = DefaultConfig() // Reset
+
+	// Test missing DailyTemplateFile
+	cfg.DailyTemplateFile = filepath.Join(t.TempDir(), "missing.md")
+	assert.ErrorContains(t, cfg.Validate(), "DailyTemplateFile does not exist")
+	cfg = DefaultConfig() // Reset
+
+	// Test DailyTemplateFile with an invalid template
+	invalidTemplateFile := filepath.Join(t.TempDir(), "invalid.md")
+	os.WriteFile(invalidTemplateFile, []byte("# {{.Date | formatDate \"2006\""), 0644)
+	cfg.DailyTemplateFile = invalidTemplateFile
+	assert.ErrorContains(t, cfg.Validate(), "is not a valid template")
+	cfg = DefaultConfig() // Reset
+
+	// Test EntryPrefix with an invalid template
+	cfg.EntryPrefix = "[{{.Date | formatDate \"Mon\"]"
+	assert.ErrorContains(t, cfg.Validate(), "EntryPrefix is not a valid template")
+	cfg.EntryPrefix = "[{{.Date | formatDate \"Mon\"}}]"
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test valid DailyTemplateFile
+	validTemplateFile := filepath.Join(t.TempDir(), "daily.md")
+	os.WriteFile(validTemplateFile, []byte("# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"), 0644)
+	cfg.DailyTemplateFile = validTemplateFile
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test unknown LogEntryOrder
+	cfg.LogEntryOrder = "random"
+	assert.ErrorContains(t, cfg.Validate(), "LogEntryOrder must be either")
+	cfg.LogEntryOrder = LogEntryOrderPrepend
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test DayBoundaryHour out of range
+	cfg.DayBoundaryHour = 24
+	assert.ErrorContains(t, cfg.Validate(), "DayBoundaryHour must be between 0 and 23, got 24")
+	cfg.DayBoundaryHour = 4
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test negative WeatherTimeout
+	cfg.WeatherTimeout = -time.Second
+	assert.ErrorContains(t, cfg.Validate(), "WeatherTimeout cannot be negative")
+	cfg = DefaultConfig() // Reset
+
+	// Test negative ChartHeight
+	cfg.ChartHeight = -1
+	assert.ErrorContains(t, cfg.Validate(), "ChartHeight cannot be negative")
+	cfg = DefaultConfig() // Reset
+
+	// Test WorkingDaysPerWeek other than 5 or 7 and negative DefaultWordGoal
+	cfg.WorkingDaysPerWeek = 6
+	assert.ErrorContains(t, cfg.Validate(), "WorkingDaysPerWeek must be either 5 or 7, got 6")
+	cfg.WorkingDaysPerWeek = 7
+	assert.NoError(t, cfg.Validate())
+	cfg.DefaultWordGoal = -1
+	assert.ErrorContains(t, cfg.Validate(), "DefaultWordGoal cannot be negative")
+	cfg = DefaultConfig() // Reset
+
+	// Test AI title enabled with empty AITitlePrompt
+	cfg.AITitleEnabled = true
+	cfg.AITitlePrompt = ""
+	assert.ErrorContains(t, cfg.Validate(), "AITitlePrompt cannot be empty if AI title is enabled")
+	cfg = DefaultConfig() // Reset
+
 	// Test AI enabled with empty AIPrompt
 	cfg.AIEnabled = true
 	cfg.AIPrompt = ""
 	assert.ErrorContains(t, cfg.Validate(), "AIPrompt cannot be empty if AI is enabled")
 	cfg = DefaultConfig() // Reset
+
+	// Test AI enabled with an empty review prompt
+	cfg.AIEnabled = true
+	cfg.ReviewMonthPrompt = ""
+	assert.ErrorContains(t, cfg.Validate(), "ReviewWeekPrompt, ReviewMonthPrompt and ReviewYearPrompt cannot be empty if AI is enabled")
+	cfg.AIEnabled = false
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test review custom sections without title or with an unknown position
+	cfg.ReviewCustomSections = []ReviewCustomSection{{Position: ReviewSectionAfterSummary}}
+	assert.ErrorContains(t, cfg.Validate(), "ReviewCustomSections: title cannot be empty")
+	cfg.ReviewCustomSections = []ReviewCustomSection{{Title: "Action Items", Position: "top"}}
+	assert.ErrorContains(t, cfg.Validate(), "ReviewCustomSections \"Action Items\": position must be one of")
+	cfg.ReviewCustomSections[0].Position = ReviewSectionAfterDailySummaries
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test undefined DefaultAIProfile
+	cfg.DefaultAIProfile = "gemini"
+	assert.ErrorContains(t, cfg.Validate(), "DefaultAIProfile \"gemini\" is not defined")
+	cfg = DefaultConfig() // Reset
+
+	// Test AI profile without command and with an unsupported driver
+	cfg.AIProfiles = map[string]AIProfile{"gemini": {}}
+	assert.ErrorContains(t, cfg.Validate(), "AI profile \"gemini\": command cannot be empty")
+	cfg.AIProfiles = map[string]AIProfile{"gemini": {Command: "gemini", Driver: "http"}}
+	assert.ErrorContains(t, cfg.Validate(), "AI profile \"gemini\": unsupported driver \"http\"")
+	cfg = DefaultConfig() // Reset
+
+	// Test invalid and valid timezones
+	cfg.Timezone = "Mars/Olympus_Mons"
+	assert.ErrorContains(t, cfg.Validate(), "Timezone \"Mars/Olympus_Mons\" is not a valid timezone")
+	assert.Equal(t, time.Local, cfg.Location())
+	cfg.Timezone = "America/New_York"
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "America/New_York", cfg.Location().String())
+	cfg = DefaultConfig() // Reset
+
+	// Test AI backends
+	cfg.AIBackend = "gemini"
+	assert.ErrorContains(t, cfg.Validate(), "AIBackend must be one of \"command\", \"openai\", \"anthropic\" or \"ollama\", got \"gemini\"")
+	cfg.AIEnabled = true
+	cfg.AIBackend = ai.BackendOpenAI
+	assert.ErrorContains(t, cfg.Validate(), "AIAPIKey cannot be empty if AI is enabled with the \"openai\" backend")
+	cfg.AIAPIKey = "secret"
+	assert.NoError(t, cfg.Validate())
+	cfg.AIAPIKey = ""
+	cfg.AIBackend = ai.BackendOllama
+	assert.NoError(t, cfg.Validate())
+	cfg.AIOllamaURL = "localhost:11434"
+	assert.ErrorContains(t, cfg.Validate(), "AIOllamaURL must be an address like \"http://localhost:11434\", got \"localhost:11434\"")
+	cfg.AIOllamaURL = "http://gpu-box:11434"
+	assert.NoError(t, cfg.Validate())
+	cfg = DefaultConfig() // Reset
+
+	// Test AI enabled with a default profile instead of AICommand
+	cfg.AIEnabled = true
+	cfg.DefaultAIProfile = "gemini"
+	cfg.AIProfiles = map[string]AIProfile{"gemini": {Command: "gemini --prompt '{PROMPT} {TEXT}'"}}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestAIProfiles(t *testing.T) {
+	tmpfile := filepath.Join(t.TempDir(), "config.toml")
+	content := `ai_enabled = true
+ai_command = "fallback '{TEXT}'"
+default_ai_profile = "claude"
+
+[ai_profiles.gemini]
+command = "gemini --model {MODEL} --prompt '{PROMPT} {TEXT}'"
+model = "gemini-pro"
+
+[ai_profiles.claude]
+command = "claude --print '{PROMPT} {TEXT}'"
+prompt = "Summarize my day."
+`
+	assert.NoError(t, os.WriteFile(tmpfile, []byte(content), 0644))
+
+	// Test case 1: the default profile is used as AISummarizer and overrides AIPrompt
+	cfg, err := LoadConfig(tmpfile)
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, &ai.ExternalAISummarizer{CommandTemplate: "claude --print '{PROMPT} {TEXT}'"}, cfg.AISummarizer)
+	assert.Equal(t, "Summarize my day.", cfg.AIPrompt)
+
+	// Test case 2: profiles are selectable by name, with their settings filled in the command
+	summarizer, err := cfg.AISummarizerFor("gemini")
+	assert.NoError(t, err)
+	assert.Equal(t, &ai.ExternalAISummarizer{CommandTemplate: "gemini --model gemini-pro --prompt '{PROMPT} {TEXT}'"}, summarizer)
+
+	// Test case 3: an empty name selects the default summarizer
+	summarizer, err = cfg.AISummarizerFor("")
+	assert.NoError(t, err)
+	assert.Same(t, cfg.AISummarizer, summarizer)
+
+	// Test case 4: unknown profile
+	_, err = cfg.AISummarizerFor("copilot")
+	assert.ErrorContains(t, err, "unknown AI profile: copilot")
+}
+
+func TestLoadConfigAIBackend(t *testing.T) {
+	tmpfile := filepath.Join(t.TempDir(), "config.toml")
+	content := `ai_enabled = true
+ai_backend = "anthropic"
+ai_api_key = "secret"
+ai_model = "claude-sonnet"
+`
+	assert.NoError(t, os.WriteFile(tmpfile, []byte(content), 0644))
+
+	cfg, err := LoadConfig(tmpfile)
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, &ai.AnthropicSummarizer{APIKey: "secret", Model: "claude-sonnet"}, cfg.AISummarizer)
+
+	content = `ai_enabled = true
+ai_backend = "ollama"
+ai_ollama_url = "http://gpu-box:11434"
+ai_model = "mistral"
+`
+	assert.NoError(t, os.WriteFile(tmpfile, []byte(content), 0644))
+	cfg, err = LoadConfig(tmpfile)
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, &ai.OllamaSummarizer{BaseURL: "http://gpu-box:11434", Model: "mistral"}, cfg.AISummarizer)
+}
+
+func TestConfigValidateAll(t *testing.T) {
+	// Test case 1: Default config is valid
+	cfg := DefaultConfig()
+	assert.NoError(t, cfg.ValidateAll())
+
+	// Test case 2: Errors of Validate are reported
+	cfg.JournalDir = ""
+	assert.ErrorContains(t, cfg.ValidateAll(), "JournalDir cannot be empty")
+	cfg = DefaultConfig() // Reset
+
+	// Test case 3: Self-referencing template
+	cfg.DailyFileName = "{{.DailyFileName}}"
+	assert.ErrorContains(t, cfg.ValidateAll(), "DailyFileName is not a valid template: unknown template variable \".DailyFileName\"")
+	cfg = DefaultConfig() // Reset
+
+	// Test case 4: Template file referencing an unknown variable
+	templateFile := filepath.Join(t.TempDir(), "daily.md")
+	os.WriteFile(templateFile, []byte("# {{.Date | formatDate \"2006-01-02\"}} {{.Weather}}\n\n# LOG\n"), 0644)
+	cfg.DailyTemplateFile = templateFile
+	assert.ErrorContains(t, cfg.ValidateAll(), "Weather")
+	cfg = DefaultConfig() // Reset
+
+	// Test case 5: Unsafe function
+	cfg.OneLineTemplate = "{{call .Summary}}"
+	assert.ErrorContains(t, cfg.ValidateAll(), "function \"call\" is not allowed")
+	cfg = DefaultConfig() // Reset
+
+	// Test case 6: Issue links
+	cfg.IssueLinkPattern = `(?P<id>JIRA-\d+)`
+	cfg.IssueLinkTemplate = "https://jira.example.com/browse/{{.ID}}"
+	assert.NoError(t, cfg.ValidateAll())
+
+	cfg.IssueLinkPattern = `(?P<id>JIRA-\d+`
+	assert.ErrorContains(t, cfg.ValidateAll(), "IssueLinkPattern is not a valid regular expression: error parsing regexp")
+
+	cfg.IssueLinkPattern = `JIRA-\d+`
+	assert.ErrorContains(t, cfg.ValidateAll(), "IssueLinkPattern must have a named group \"id\"")
+
+	cfg.IssueLinkPattern = `(?P<id>JIRA-\d+)`
+	cfg.IssueLinkTemplate = ""
+	assert.ErrorContains(t, cfg.ValidateAll(), "IssueLinkTemplate cannot be empty")
+
+	cfg.IssueLinkTemplate = "https://jira.example.com/browse/{{.Key}}"
+	assert.ErrorContains(t, cfg.ValidateAll(), "IssueLinkTemplate is not a valid template")
+}
+
+func TestListTemplateFields(t *testing.T) {
+	// Test case 1: A default config has only default values
+	cfg := DefaultConfig()
+	fields := ListTemplateFields(cfg)
+	assert.Len(t, fields, 4)
+	for _, field := range fields {
+		assert.True(t, field.IsDefault, field.Name)
+		assert.Equal(t, field.DefaultValue, field.CurrentValue)
+	}
+	assert.Equal(t, "DailyFileName", fields[0].Name)
+	assert.Equal(t, "daily_file_name", fields[0].TOMLKey)
+
+	// Test case 2: Only the modified template is reported as non default
+	cfg.LogEntryTemplate = "{{.Time | formatTime \"15:04:05\"}} - {{.Entry}}"
+	for _, field := range ListTemplateFields(cfg) {
+		if field.Name == "LogEntryTemplate" {
+			assert.False(t, field.IsDefault)
+			assert.Equal(t, "log_entry_template", field.TOMLKey)
+			assert.Equal(t, cfg.LogEntryTemplate, field.CurrentValue)
+			assert.Equal(t, DefaultConfig().LogEntryTemplate, field.DefaultValue)
+		} else {
+			assert.True(t, field.IsDefault, field.Name)
+		}
+	}
+
+	// Test case 3: Every template field has a preview
+	for _, field := range ListTemplateFields(cfg) {
+		preview, err := PreviewTemplate(field.CurrentValue)
+		assert.NoError(t, err, field.Name)
+		assert.NotEmpty(t, preview, field.Name)
+	}
+	preview, err := PreviewTemplate(cfg.LogEntryTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, "14:30:00 - Sample log entry.", preview)
+
+	// Test case 4: Invalid template
+	_, err = PreviewTemplate("{{.Date")
+	assert.Error(t, err)
+}
+
+func TestExpandPath(t *testing.T) {
+	usr, err := user.Current()
+	assert.NoError(t, err)
+	t.Setenv("HOME", "/home/tester")
+	t.Setenv("LOGBOOK_TEST_DIR", "notes")
+
+	// Test case 1: A leading ~ is the home directory of the current user
+	assert.Equal(t, filepath.Join(usr.HomeDir, "foo"), ExpandPath("~/foo"))
+	assert.Equal(t, usr.HomeDir, ExpandPath("~"))
+
+	// Test case 2: $VAR and ${VAR} environment variables
+	assert.Equal(t, "/home/tester/foo", ExpandPath("$HOME/foo"))
+	assert.Equal(t, "/home/tester/foo", ExpandPath("${HOME}/foo"))
+	assert.Equal(t, "/home/tester/notes/journal", ExpandPath("$HOME/${LOGBOOK_TEST_DIR}/journal"))
+
+	// Test case 3: Paths without ~ and variables are unchanged
+	assert.Equal(t, "/var/lib/logbook", ExpandPath("/var/lib/logbook"))
+	assert.Equal(t, "/tmp/~foo", ExpandPath("/tmp/~foo"))
+	assert.Equal(t, "", ExpandPath(""))
+
+	// Test case 4: Unset variables expand to an empty string, leaving an empty component that Validate rejects
+	cfg := DefaultConfig()
+	cfg.JournalDir = ExpandPath("$HOME/$LOGBOOK_UNSET_VARIABLE/journal")
+	assert.Equal(t, "/home/tester//journal", cfg.JournalDir)
+	assert.ErrorContains(t, cfg.Validate(), "JournalDir has an empty path component")
+	cfg.JournalDir = ExpandPath("$LOGBOOK_UNSET_VARIABLE")
+	assert.ErrorContains(t, cfg.Validate(), "JournalDir cannot be empty")
+
+	// Test case 5: LoadConfig expands JournalDir and EncryptionKeyFile
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(configFile, []byte("journal_dir = \"${HOME}/Documents/logbook\"\nencryption_key_file = \"~/.config/logbook/journal.key\"\n"), 0644))
+	cfg, err = LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/tester/Documents/logbook", cfg.JournalDir)
+	assert.Equal(t, filepath.Join(usr.HomeDir, ".config", "logbook", "journal.key"), cfg.EncryptionKeyFile)
 }
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Environment variables set for the hooks by RunHook callers.
+const (
+	HookEnvFile = "LOGBOOK_FILE" // The daily journal file, or the review directory for the review hooks
+	HookEnvDate = "LOGBOOK_DATE" // The date of the journal file, or the current date, as YYYY-MM-DD
+)
+
+// Hooks are shell commands run before and after logging an entry and generating a review, e.g.
+// post_log = "cd $(dirname $LOGBOOK_FILE) && git add . && git commit -m 'logbook'".
+type Hooks struct {
+	PreLog     string `toml:"pre_log"`
+	PostLog    string `toml:"post_log"`
+	PreReview  string `toml:"pre_review"`
+	PostReview string `toml:"post_review"`
+}
+
+// RunHook runs hookCmd with "sh -c", adding env to the environment of LogBook. An empty hookCmd does nothing.
+// The standard output of the hook is shown; if the hook fails, the error includes its standard error.
+func RunHook(hookCmd string, env map[string]string) error {
+	if strings.TrimSpace(hookCmd) == "" {
+		return nil
+	}
+
+	cmd := exec.Command("sh", "-c", hookCmd)
+	cmd.Env = os.Environ()
+	for _, name := range sortedKeys(env) {
+		cmd.Env = append(cmd.Env, name+"="+env[name])
+	}
+	var stderr bytes.Buffer
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return fmt.Errorf("hook %q failed: %w: %s", hookCmd, err, detail)
+		}
+		return fmt.Errorf("hook %q failed: %w", hookCmd, err)
+	}
+	return nil
+}
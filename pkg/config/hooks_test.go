@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHook(t *testing.T) {
+	tmpDir := t.TempDir()
+	sentinel := filepath.Join(tmpDir, "sentinel")
+	script := filepath.Join(tmpDir, "hook.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho \"$LOGBOOK_FILE $LOGBOOK_DATE\" > \""+sentinel+"\"\n"), 0755)
+	env := map[string]string{HookEnvFile: "/journal/2025-09-15.md", HookEnvDate: "2025-09-15"}
+
+	// Test case 1: The hook runs with the LogBook variables in its environment
+	assert.NoError(t, RunHook(script, env))
+	content, err := os.ReadFile(sentinel)
+	assert.NoError(t, err)
+	assert.Equal(t, "/journal/2025-09-15.md 2025-09-15\n", string(content))
+
+	// Test case 2: Empty hooks do nothing
+	assert.NoError(t, RunHook("", env))
+	assert.NoError(t, RunHook("  ", env))
+
+	// Test case 3: A failing hook reports its standard error
+	err = RunHook("echo 'nothing to commit' >&2; exit 3", env)
+	assert.EqualError(t, err, `hook "echo 'nothing to commit' >&2; exit 3" failed: exit status 3: nothing to commit`)
+	err = RunHook("exit 1", env)
+	assert.EqualError(t, err, `hook "exit 1" failed: exit status 1`)
+}
+
+func TestLoadConfigHooks(t *testing.T) {
+	tmpfile := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(tmpfile, []byte("journal_dir = \"/tmp\"\n\n[hooks]\npost_log = \"git commit -am logbook\"\npre_review = \"git pull\"\n"), 0644)
+
+	cfg, err := LoadConfig(tmpfile)
+	assert.NoError(t, err)
+	assert.Equal(t, Hooks{PostLog: "git commit -am logbook", PreReview: "git pull"}, cfg.Hooks)
+	assert.Contains(t, FormatConfig(cfg), `* hooks = {post_log = "git commit -am logbook", pre_review = "git pull"}`)
+}
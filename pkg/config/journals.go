@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Journal is a named journal with its own directory, e.g. to keep "work" and "personal" apart:
+//
+//	[[journals]]
+//	name = "work"
+//	journal_dir = "~/work/journal"
+//
+// The other fields are optional and override the ones of the base configuration when set.
+type Journal struct {
+	Name              string `toml:"name"`
+	JournalDir        string `toml:"journal_dir"`
+	DailyFileName     string `toml:"daily_file_name"`
+	ReviewDir         string `toml:"review_dir"` // Empty for JournalDir, so that the reviews of the journals do not mix
+	DailyTemplate     string `toml:"daily_template"`
+	DailyTemplateFile string `toml:"daily_template_file"`
+	LogEntryTemplate  string `toml:"log_entry_template"`
+	EntryPrefix       string `toml:"entry_prefix"`
+	Timezone          string `toml:"timezone"`
+	AIPrompt          string `toml:"ai_prompt"`
+}
+
+// GetJournalConfig returns the configuration of the journal with the given name: cfg with the fields set by the
+// journal overriding its own. An empty name selects the first of cfg.Journals, or cfg itself if there is none.
+// cfg is not modified.
+func GetJournalConfig(cfg *Config, name string) (*Config, error) {
+	if len(cfg.Journals) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("unknown journal %q, no journals are configured", name)
+		}
+		return cfg, nil
+	}
+
+	journal := cfg.Journals[0]
+	if name != "" {
+		found := false
+		names := make([]string, len(cfg.Journals))
+		for i, j := range cfg.Journals {
+			names[i] = j.Name
+			if j.Name == name {
+				journal, found = j, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown journal %q, expected one of: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	merged := *cfg
+	merged.JournalDir = journal.JournalDir
+	merged.ReviewDir = journal.ReviewDir
+	for _, override := range []struct {
+		field *string
+		value string
+	}{
+		{&merged.DailyFileName, journal.DailyFileName},
+		{&merged.DailyTemplate, journal.DailyTemplate},
+		{&merged.DailyTemplateFile, journal.DailyTemplateFile},
+		{&merged.LogEntryTemplate, journal.LogEntryTemplate},
+		{&merged.EntryPrefix, journal.EntryPrefix},
+		{&merged.Timezone, journal.Timezone},
+		{&merged.AIPrompt, journal.AIPrompt},
+	} {
+		if override.value != "" {
+			*override.field = override.value
+		}
+	}
+	return &merged, nil
+}
+
+// validateJournals checks that the journals have a unique name and an absolute directory.
+func validateJournals(journals []Journal) error {
+	seen := make(map[string]bool)
+	for _, journal := range journals {
+		if journal.Name == "" {
+			return fmt.Errorf("Journals: name cannot be empty")
+		}
+		if seen[journal.Name] {
+			return fmt.Errorf("Journals: name %q is used more than once", journal.Name)
+		}
+		seen[journal.Name] = true
+		if journal.JournalDir == "" {
+			return fmt.Errorf("journal %q: journal_dir cannot be empty", journal.Name)
+		}
+		if !filepath.IsAbs(journal.JournalDir) {
+			return fmt.Errorf("journal %q: journal_dir must be an absolute path: %s", journal.Name, journal.JournalDir)
+		}
+		if journal.ReviewDir != "" && !filepath.IsAbs(journal.ReviewDir) {
+			return fmt.Errorf("journal %q: review_dir must be an absolute path: %s", journal.Name, journal.ReviewDir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJournalConfig(t *testing.T) {
+	base := DefaultConfig()
+	base.JournalDir = "/home/me/journal"
+	base.ReviewDir = "/home/me/reviews"
+	base.Timezone = "Europe/Rome"
+
+	// Test case 1: Without journals the base configuration is used
+	cfg, err := GetJournalConfig(base, "")
+	assert.NoError(t, err)
+	assert.Same(t, base, cfg)
+	_, err = GetJournalConfig(base, "work")
+	assert.EqualError(t, err, `unknown journal "work", no journals are configured`)
+
+	// Test case 2: The first journal is the default, its fields override the base ones
+	base.Journals = []Journal{
+		{Name: "work", JournalDir: "/home/me/work", DailyFileName: "work-{{.Date | formatDate \"2006-01-02\"}}.md", Timezone: "America/New_York"},
+		{Name: "health", JournalDir: "/home/me/health", ReviewDir: "/home/me/health/reviews", EntryPrefix: "[health]"},
+	}
+	cfg, err = GetJournalConfig(base, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/me/work", cfg.JournalDir)
+	assert.Equal(t, "work-{{.Date | formatDate \"2006-01-02\"}}.md", cfg.DailyFileName)
+	assert.Equal(t, "America/New_York", cfg.Timezone)
+	assert.Equal(t, "/home/me/work", cfg.ReviewDirectory(), "the reviews of a journal stay in its directory")
+	assert.Equal(t, base.DailyTemplate, cfg.DailyTemplate)
+	assert.Equal(t, base.AIPrompt, cfg.AIPrompt)
+
+	// Test case 3: A journal by name, the fields it does not set are the base ones
+	cfg, err = GetJournalConfig(base, "health")
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/me/health", cfg.JournalDir)
+	assert.Equal(t, "/home/me/health/reviews", cfg.ReviewDirectory())
+	assert.Equal(t, "[health]", cfg.EntryPrefix)
+	assert.Equal(t, base.DailyFileName, cfg.DailyFileName)
+	assert.Equal(t, "Europe/Rome", cfg.Timezone)
+
+	// Test case 4: The base configuration is not modified
+	assert.Equal(t, "/home/me/journal", base.JournalDir)
+	assert.Equal(t, "", base.EntryPrefix)
+
+	// Test case 5: Unknown journal
+	_, err = GetJournalConfig(base, "personal")
+	assert.EqualError(t, err, `unknown journal "personal", expected one of: work, health`)
+}
+
+func TestJournalsValidate(t *testing.T) {
+	// Test case 1: Valid journals
+	cfg := DefaultConfig()
+	cfg.Journals = []Journal{{Name: "work", JournalDir: "/tmp/work"}, {Name: "personal", JournalDir: "/tmp/personal", ReviewDir: "/tmp/reviews"}}
+	assert.NoError(t, cfg.Validate())
+
+	// Test case 2: Invalid journals
+	for _, tc := range []struct {
+		journals []Journal
+		err      string
+	}{
+		{[]Journal{{JournalDir: "/tmp/work"}}, "Journals: name cannot be empty"},
+		{[]Journal{{Name: "work", JournalDir: "/tmp/a"}, {Name: "work", JournalDir: "/tmp/b"}}, `Journals: name "work" is used more than once`},
+		{[]Journal{{Name: "work"}}, `journal "work": journal_dir cannot be empty`},
+		{[]Journal{{Name: "work", JournalDir: "work"}}, `journal "work": journal_dir must be an absolute path: work`},
+		{[]Journal{{Name: "work", JournalDir: "/tmp/work", ReviewDir: "reviews"}}, `journal "work": review_dir must be an absolute path: reviews`},
+	} {
+		cfg.Journals = tc.journals
+		assert.EqualError(t, cfg.Validate(), tc.err)
+	}
+
+	// Test case 3: The [[journals]] array is loaded from TOML, with the directories expanded
+	t.Setenv("HOME", "/home/tester")
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(configFile, []byte("journal_dir = \"/tmp/journal\"\n\n[[journals]]\nname = \"work\"\njournal_dir = \"$HOME/work\"\n\n[[journals]]\nname = \"personal\"\njournal_dir = \"/tmp/personal\"\nlog_entry_template = \"{{.Entry}}\"\n"), 0644)
+	cfg, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []Journal{
+		{Name: "work", JournalDir: "/home/tester/work"},
+		{Name: "personal", JournalDir: "/tmp/personal", LogEntryTemplate: "{{.Entry}}"},
+	}, cfg.Journals)
+	assert.NoError(t, cfg.Validate())
+	assert.Contains(t, FormatConfig(cfg), `journals = ["work", "personal"]`)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Names of the optional sections of the [sections] table.
+const (
+	SectionMood                = "mood"
+	SectionEnergy              = "energy"
+	SectionWeather             = "weather"
+	SectionHabitTracker        = "habit_tracker"
+	SectionReflectionQuestions = "reflection_questions"
+	SectionOneLineNotes        = "one_line_notes"
+	SectionWeeklyRecap         = "weekly_recap"
+)
+
+// defaultSections tells whether each section is enabled when [sections] does not mention it.
+var defaultSections = map[string]bool{
+	SectionMood:                false,
+	SectionEnergy:              false,
+	SectionWeather:             false,
+	SectionHabitTracker:        false,
+	SectionReflectionQuestions: false,
+	SectionOneLineNotes:        true,
+	SectionWeeklyRecap:         false,
+}
+
+// legacySections are the fields replaced by the [sections] table, still read from older configuration files.
+type legacySections struct {
+	WeatherEnabled bool `toml:"weather_enabled"`
+	MoodEnabled    bool `toml:"mood_enabled"`
+}
+
+// migrateLegacySections enables in cfg.Sections the sections enabled by the legacy mood_enabled and weather_enabled
+// fields of the configuration file at path, so that saving cfg moves them to the [sections] table.
+func migrateLegacySections(path string, cfg *Config) error {
+	var legacy legacySections
+	if _, err := toml.DecodeFile(path, &legacy); err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+	for name, enabled := range map[string]bool{SectionWeather: legacy.WeatherEnabled, SectionMood: legacy.MoodEnabled} {
+		if !enabled {
+			continue
+		}
+		if cfg.Sections == nil {
+			cfg.Sections = make(map[string]bool)
+		}
+		cfg.Sections[name] = true
+	}
+	return nil
+}
+
+// SectionEnabled reports whether the named section is enabled by the [sections] table, or by default.
+func SectionEnabled(cfg *Config, name string) bool {
+	if enabled, ok := cfg.Sections[name]; ok {
+		return enabled
+	}
+	return defaultSections[name]
+}
+
+// validateSections checks that the [sections] table only has known sections.
+func validateSections(sections map[string]bool) error {
+	for name := range sections {
+		if _, ok := defaultSections[name]; !ok {
+			return fmt.Errorf("unknown section %q in [sections]", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSectionEnabled(t *testing.T) {
+	// Test case 1: Defaults, only the one-line notes are enabled
+	cfg := DefaultConfig()
+	assert.True(t, SectionEnabled(cfg, SectionOneLineNotes))
+	for _, name := range []string{SectionMood, SectionEnergy, SectionWeather, SectionHabitTracker, SectionReflectionQuestions, SectionWeeklyRecap} {
+		assert.False(t, SectionEnabled(cfg, name), name)
+	}
+
+	// Test case 2: Enabling and disabling via the map
+	cfg.Sections = map[string]bool{SectionMood: true, SectionOneLineNotes: false}
+	assert.True(t, SectionEnabled(cfg, SectionMood))
+	assert.False(t, SectionEnabled(cfg, SectionOneLineNotes))
+
+	// Test case 3: The [sections] table and the legacy fields are loaded from TOML
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(configFile, []byte("journal_dir = \"/tmp/journal\"\nmood_enabled = true\n\n[sections]\nhabit_tracker = true\none_line_notes = false\n"), 0644)
+	cfg, err := LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.True(t, SectionEnabled(cfg, SectionMood))
+	assert.True(t, SectionEnabled(cfg, SectionHabitTracker))
+	assert.False(t, SectionEnabled(cfg, SectionOneLineNotes))
+	assert.False(t, SectionEnabled(cfg, SectionWeather))
+
+	// Test case 4: The legacy fields enable their section even if [sections] disables it, and are saved in [sections]
+	os.WriteFile(configFile, []byte("weather_enabled = true\n\n[sections]\nweather = false\n"), 0644)
+	cfg, err = LoadConfig(configFile)
+	assert.NoError(t, err)
+	assert.True(t, SectionEnabled(cfg, SectionWeather))
+	assert.NoError(t, SaveConfig(configFile, cfg))
+	content, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "weather_enabled")
+	assert.Contains(t, string(content), "[sections]\n  weather = true\n")
+
+	// Test case 5: Unknown sections are rejected
+	cfg = DefaultConfig()
+	cfg.Sections = map[string]bool{"horoscope": true}
+	assert.ErrorContains(t, cfg.Validate(), "unknown section \"horoscope\"")
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fieldDescriptions are the short descriptions of the TOML keys shown by FormatConfig.
+var fieldDescriptions = map[string]string{
+	"journal_dir":                    "Directory of the daily journal files, with ~ and $VAR expanded",
+	"review_dir":                     "Directory of the review files, empty for journal_dir",
+	"daily_file_name":                "Template of the name of the daily journal files",
+	"daily_template":                 "Template of the content of new daily journal files",
+	"daily_template_file":            "Markdown file used instead of daily_template",
+	"log_entry_template":             "Template of a log entry",
+	"log_entry_order":                "Order of the log entries: append (oldest first) or prepend (newest first)",
+	"trim_entries":                   "Remove trailing spaces and tabs from new log entries",
+	"entry_date_prefix":              "Date layout written before the entry time by log --prepend-date",
+	"entry_prefix":                   "Template written before the text of every log entry",
+	"log_entry_separator":            "Line written between log entries",
+	"day_boundary_hour":              "Hour the day starts at, earlier entries go to the previous day",
+	"timezone":                       "Timezone of the journal dates and times, empty for the system one",
+	"weather_auto_prefix":            "Prepend the current weather to every log entry",
+	"weather_location":               "City of the weather, empty to detect it from the IP address",
+	"weather_timeout":                "Maximum duration of the weather lookup",
+	"ai_enabled":                     "Generate the summaries with AI",
+	"ai_command":                     "AI command template of the command backend, with {PROMPT} and {TEXT}",
+	"ai_backend":                     "AI backend: command, openai, anthropic or ollama",
+	"ai_api_key":                     "API key of the openai and anthropic backends",
+	"ai_model":                       "Model of the openai, anthropic and ollama backends",
+	"ai_ollama_url":                  "Address of the Ollama server, empty for http://localhost:11434",
+	"auto_detected_ai":               "ai_command was detected by config set ai_command auto",
+	"ai_prompt":                      "Prompt of the daily summaries",
+	"default_ai_profile":             "AI profile used instead of ai_command",
+	"ai_title_enabled":               "Prefix the daily file title with an AI generated description of the day",
+	"ai_title_prompt":                "Prompt of the AI generated titles",
+	"learning_extraction_prompt":     "Prompt of the skills and tools learned in the yearly review",
+	"weekly_insight_prompt":          "Prompt of the key insight of the weekly review",
+	"review_week_prompt":             "Prompt of the weekly review summaries",
+	"review_month_prompt":            "Prompt of the monthly review summaries",
+	"review_year_prompt":             "Prompt of the yearly review summaries",
+	"auto_resummarize_after_entries": "Regenerate the daily summary every N log entries, 0 to disable",
+	"ai_max_context_tokens":          "Maximum size of the text sent to the AI, in words",
+	"default_ai_language":            "Language of the AI generated review summaries",
+	"one_line_template":              "Template of the one-line notes",
+	"color_theme":                    "Color theme: default, solarized, dracula or none",
+	"chart_height":                   "Number of rows of the ASCII charts in reviews",
+	"working_days_per_week":          "Days with entries expected by the productivity score, 5 or 7",
+	"default_word_goal":              "Words per week expected by the productivity score, 0 to ignore words",
+	"issue_link_pattern":             "Regular expression of the issue references linked in reviews",
+	"issue_link_template":            "URL of a referenced issue, with {{.ID}}",
+	"preserve_crlf":                  "Write journal files with Windows line endings (Windows only)",
+	"encrypt_summary":                "Store the generated summaries encrypted",
+	"encrypt":                        "Encrypt the daily journal files at rest with the key of encryption_key_file",
+	"encryption_key_file":            "File with the hexadecimal 32 bytes key of encrypt, e.g. from openssl rand -hex 32",
+	"habits":                         "Habits tracked by the monthly review",
+	"sections":                       "Optional sections enabled or disabled",
+	"ai_profiles":                    "Named AI configurations",
+	"review_custom_sections":         "Sections added to every review",
+	"journals":                       "Named journals selected with --journal, the first one is the default",
+	"hooks":                          "Shell commands run before and after log and review",
+}
+
+// secretKeys are the TOML keys whose value FormatConfig does not show.
+var secretKeys = map[string]bool{"ai_api_key": true}
+
+// FormatConfig returns the TOML fields of cfg in a human-readable format, one per line with its key and value,
+// followed by a line with its description. The fields whose value differs from DefaultConfig are marked with "*".
+func FormatConfig(cfg *Config) string {
+	current := reflect.ValueOf(cfg).Elem()
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+
+	var sb strings.Builder
+	sb.WriteString("Fields marked with * differ from the default configuration.\n\n")
+	for i := 0; i < current.NumField(); i++ {
+		key := current.Type().Field(i).Tag.Get("toml")
+		if key == "" || key == "-" {
+			continue
+		}
+		value := current.Field(i)
+
+		marker := " "
+		if !reflect.DeepEqual(value.Interface(), defaults.Field(i).Interface()) {
+			marker = "*"
+		}
+		formatted := formatValue(value)
+		if secretKeys[key] && value.String() != "" {
+			formatted = `"********"`
+		}
+		sb.WriteString(fmt.Sprintf("%s %s = %s\n", marker, key, formatted))
+		if description, ok := fieldDescriptions[key]; ok {
+			sb.WriteString(fmt.Sprintf("      %s\n", description))
+		}
+	}
+	return sb.String()
+}
+
+// formatValue returns a configuration value in a TOML-like format.
+// Maps are shown with their keys sorted; AI profiles, review sections and journals by name only, hiding their settings.
+func formatValue(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case time.Duration:
+		return fmt.Sprintf("%q", v.String())
+	case []string:
+		quoted := make([]string, len(v))
+		for i, item := range v {
+			quoted[i] = fmt.Sprintf("%q", item)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case map[string]bool:
+		names := sortedKeys(v)
+		for i, name := range names {
+			names[i] = fmt.Sprintf("%s = %t", name, v[name])
+		}
+		return "{" + strings.Join(names, ", ") + "}"
+	case map[string]AIProfile:
+		return "[" + strings.Join(sortedKeys(v), ", ") + "]"
+	case Hooks:
+		hooks := reflect.ValueOf(v)
+		var set []string
+		for i := 0; i < hooks.NumField(); i++ {
+			if command := hooks.Field(i).String(); command != "" {
+				set = append(set, fmt.Sprintf("%s = %q", hooks.Type().Field(i).Tag.Get("toml"), command))
+			}
+		}
+		return "{" + strings.Join(set, ", ") + "}"
+	case []ReviewCustomSection:
+		titles := make([]string, len(v))
+		for i, section := range v {
+			titles[i] = fmt.Sprintf("%q", section.Title)
+		}
+		return "[" + strings.Join(titles, ", ") + "]"
+	case []Journal:
+		names := make([]string, len(v))
+		for i, journal := range v {
+			names[i] = fmt.Sprintf("%q", journal.Name)
+		}
+		return "[" + strings.Join(names, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+// sortedKeys returns the keys of a map in alphabetical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
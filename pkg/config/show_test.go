@@ -0,0 +1,49 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatConfig(t *testing.T) {
+	// Test case 1: Every TOML field has a description
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		key := configType.Field(i).Tag.Get("toml")
+		if key != "-" {
+			assert.NotEmpty(t, fieldDescriptions[key], "missing description of %s", key)
+		}
+	}
+
+	// Test case 2: The default configuration has no field marked as changed
+	output := FormatConfig(DefaultConfig())
+	assert.Contains(t, output, "  journal_dir = ")
+	assert.Contains(t, output, "  log_entry_order = \"append\"\n      Order of the log entries: append (oldest first) or prepend (newest first)\n")
+	assert.Contains(t, output, "  weather_timeout = \"3s\"\n")
+	assert.NotContains(t, output, "\n* ")
+
+	// Test case 3: Changed fields are marked, secrets are hidden and maps are sorted
+	cfg := DefaultConfig()
+	cfg.LogEntryOrder = LogEntryOrderPrepend
+	cfg.WeatherTimeout = 5 * time.Second
+	cfg.AIAPIKey = "secret"
+	cfg.Habits = []string{"exercise", "reading"}
+	cfg.Sections = map[string]bool{SectionWeather: true, SectionMood: false}
+	cfg.AIProfiles = map[string]AIProfile{"gemini": {Command: "gemini", APIKey: "secret"}, "claude": {Command: "claude"}}
+	cfg.ReviewCustomSections = []ReviewCustomSection{{Title: "Action Items"}}
+	output = FormatConfig(cfg)
+	assert.Contains(t, output, "* log_entry_order = \"prepend\"\n")
+	assert.Contains(t, output, "* weather_timeout = \"5s\"\n")
+	assert.Contains(t, output, "* ai_api_key = \"********\"\n")
+	assert.Contains(t, output, "* habits = [\"exercise\", \"reading\"]\n")
+	assert.Contains(t, output, "* sections = {mood = false, weather = true}\n")
+	assert.Contains(t, output, "* ai_profiles = [claude, gemini]\n")
+	assert.Contains(t, output, "* review_custom_sections = [\"Action Items\"]\n")
+	assert.Contains(t, output, "  daily_file_name = ")
+	assert.NotContains(t, output, "secret")
+	assert.Equal(t, 7, strings.Count(output, "\n* "))
+}
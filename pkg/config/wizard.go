@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// titleDateFormat is the date format of the title of DefaultConfig's DailyTemplate, replaced by the
+// date format chosen in the wizard.
+const titleDateFormat = "Jan 02 2006 Monday"
+
+// DateFormatPresets are the date formats of the daily file title offered by the wizard of "logbook init".
+var DateFormatPresets = []string{
+	titleDateFormat,
+	"2006-01-02 Monday",
+	"Monday 02 January 2006",
+	"Monday, January 2, 2006",
+	"02/01/2006 Monday",
+}
+
+// Wizard asks the questions of "logbook init" and builds a configuration out of the answers.
+// When it is not interactive, e.g. with the standard input redirected, every question gets its default answer.
+type Wizard struct {
+	Reader      *bufio.Reader
+	Out         io.Writer
+	Interactive bool
+}
+
+// Confirm asks a yes or no question, no being the default answer.
+func (w *Wizard) Confirm(question string) (bool, error) {
+	for {
+		answer, err := w.ask(question + " [y/N]")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "", "n", "no":
+			return false, nil
+		case "y", "yes":
+			return true, nil
+		}
+		fmt.Fprintln(w.Out, "Please answer y or n.")
+	}
+}
+
+// Run asks for the journal directory, the AI command and the date format of the daily files,
+// and returns DefaultConfig with the answers.
+func (w *Wizard) Run() (*Config, error) {
+	cfg := DefaultConfig()
+
+	for {
+		answer, err := w.ask(fmt.Sprintf("Journal directory [%s]", cfg.JournalDir))
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			break
+		}
+		dir := ExpandPath(answer)
+		if filepath.IsAbs(dir) {
+			cfg.JournalDir = filepath.Clean(dir)
+			break
+		}
+		fmt.Fprintf(w.Out, "The journal directory must be an absolute path, got %s\n", dir)
+	}
+
+	enableAI, err := w.Confirm("Write the summaries with AI?")
+	if err != nil {
+		return nil, err
+	}
+	for enableAI {
+		answer, err := w.ask("AI command, with {PROMPT} and {TEXT} [detect gemini, claude, ollama, llm or sgpt]")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			command, err := AutoDetectAICommand()
+			if err != nil {
+				fmt.Fprintf(w.Out, "%v, please type the command\n", err)
+				continue
+			}
+			cfg.AICommand, cfg.AutoDetectedAI = command, true
+		} else if !strings.Contains(answer, "{TEXT}") {
+			fmt.Fprintln(w.Out, "The AI command must contain {TEXT}, replaced by the text to summarize")
+			continue
+		} else {
+			cfg.AICommand = answer
+		}
+		cfg.AIEnabled = true
+		fmt.Fprintf(w.Out, "Using the AI command: %s\n", cfg.AICommand)
+		break
+	}
+
+	if w.Interactive {
+		fmt.Fprintln(w.Out, "Date format of the daily files title:")
+		for i, format := range DateFormatPresets {
+			fmt.Fprintf(w.Out, "  %d) %s\n", i+1, format)
+		}
+	}
+	for {
+		answer, err := w.ask("Date format [1]")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			break
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(DateFormatPresets) {
+			fmt.Fprintf(w.Out, "Please type a number from 1 to %d\n", len(DateFormatPresets))
+			continue
+		}
+		cfg.DailyTemplate = strings.Replace(cfg.DailyTemplate, titleDateFormat, DateFormatPresets[n-1], 1)
+		break
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// ask prints a question and returns the trimmed answer, or an empty answer when the wizard is not interactive.
+func (w *Wizard) ask(question string) (string, error) {
+	if !w.Interactive {
+		return "", nil
+	}
+	fmt.Fprintf(w.Out, "%s: ", question)
+	answer, err := w.Reader.ReadString('\n')
+	if errors.Is(err, io.EOF) && answer == "" {
+		return "", fmt.Errorf("no answer typed")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the answer: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
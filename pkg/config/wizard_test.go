@@ -0,0 +1,66 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWizard(t *testing.T) {
+	t.Setenv("HOME", "/home/tester")
+	newWizard := func(input string, interactive bool) (*Wizard, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		return &Wizard{Reader: bufio.NewReader(strings.NewReader(input)), Out: out, Interactive: interactive}, out
+	}
+
+	// Test case 1: Non-interactive, the defaults are accepted without asking
+	wizard, out := newWizard("", false)
+	cfg, err := wizard.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+	overwrite, err := wizard.Confirm("Overwrite it?")
+	assert.NoError(t, err)
+	assert.False(t, overwrite)
+	assert.Empty(t, out.String())
+
+	// Test case 2: Every answer, the invalid ones are asked again
+	wizard, out = newWizard("journal\n$HOME/notes\nmaybe\ny\nmy-ai '{PROMPT}'\nmy-ai '{PROMPT} {TEXT}'\n9\n2\n", true)
+	cfg, err = wizard.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/tester/notes", cfg.JournalDir)
+	assert.True(t, cfg.AIEnabled)
+	assert.Equal(t, "my-ai '{PROMPT} {TEXT}'", cfg.AICommand)
+	assert.False(t, cfg.AutoDetectedAI)
+	assert.True(t, strings.HasPrefix(cfg.DailyTemplate, "# {{.Date | formatDate \"2006-01-02 Monday\"}}\n"))
+	assert.Contains(t, out.String(), "The journal directory must be an absolute path, got journal")
+	assert.Contains(t, out.String(), "Please answer y or n.")
+	assert.Contains(t, out.String(), "The AI command must contain {TEXT}")
+	assert.Contains(t, out.String(), "Please type a number from 1 to 5")
+
+	// Test case 3: Empty answers keep the defaults, the AI command is detected
+	origLookPath, origRunVersion := lookPath, runVersion
+	defer func() { lookPath, runVersion = origLookPath, origRunVersion }()
+	lookPath = func(file string) (string, error) {
+		if file == "llm" {
+			return "/usr/bin/llm", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	runVersion = func(path string) error { return nil }
+	wizard, _ = newWizard("\nyes\n\n\n", true)
+	cfg, err = wizard.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig().JournalDir, cfg.JournalDir)
+	assert.Equal(t, "llm '{PROMPT} {TEXT}'", cfg.AICommand)
+	assert.True(t, cfg.AutoDetectedAI)
+	assert.Equal(t, DefaultConfig().DailyTemplate, cfg.DailyTemplate)
+
+	// Test case 4: The input ends before the answers
+	wizard, _ = newWizard("/tmp/journal\n", true)
+	_, err = wizard.Run()
+	assert.EqualError(t, err, "no answer typed")
+}
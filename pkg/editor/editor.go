@@ -0,0 +1,89 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/encrypt"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// DefaultEditor is used when neither $EDITOR nor $VISUAL is set.
+const DefaultEditor = "nano"
+
+// Command returns the editor command: $EDITOR, else $VISUAL, else DefaultEditor.
+// The command may include arguments, e.g. "code --wait".
+func Command() string {
+	for _, name := range []string{"EDITOR", "VISUAL"} {
+		if command := strings.TrimSpace(os.Getenv(name)); command != "" {
+			return command
+		}
+	}
+	return DefaultEditor
+}
+
+// Open opens path in the editor returned by Command, connected to the terminal, and waits for it to exit.
+// A file encrypted with fileutil.EncryptionKey is edited as a decrypted temporary copy, encrypted back on exit.
+func Open(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if encrypt.IsEncrypted(raw) {
+		return openEncrypted(path)
+	}
+	return run(path)
+}
+
+// openEncrypted edits the decrypted content of an encrypted file in a temporary file readable by the user only.
+func openEncrypted(path string) error {
+	content, err := fileutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", "logbook-*"+filepath.Ext(path))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temporary file %s: %w", tmp.Name(), err)
+	}
+
+	if err := run(tmp.Name()); err != nil {
+		return err
+	}
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read temporary file %s: %w", tmp.Name(), err)
+	}
+	if bytes.Equal(edited, content) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return fileutil.AtomicWrite(path, edited, info.Mode().Perm())
+}
+
+// run runs the editor on path, connected to the terminal.
+func run(path string) error {
+	fields := strings.Fields(Command())
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to edit %s with %s: %w", path, fields[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	// Test case 1: $EDITOR comes first
+	t.Setenv("EDITOR", "vim")
+	t.Setenv("VISUAL", "code --wait")
+	assert.Equal(t, "vim", Command())
+
+	// Test case 2: $VISUAL without $EDITOR
+	t.Setenv("EDITOR", "")
+	assert.Equal(t, "code --wait", Command())
+
+	// Test case 3: nano without both
+	t.Setenv("VISUAL", " ")
+	assert.Equal(t, DefaultEditor, Command())
+}
+
+func TestOpen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the editor is a shell script in this test")
+	}
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "2025-09-15.md")
+	os.WriteFile(file, []byte("# Sep 15 2025\n"), 0644)
+
+	// Test case 1: The editor gets its arguments and the path
+	script := filepath.Join(tmpDir, "editor.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho \"$1\" >> \"$2\"\n"), 0755)
+	t.Setenv("EDITOR", script+" edited")
+	assert.NoError(t, Open(file))
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "# Sep 15 2025\nedited\n", string(content))
+
+	// Test case 2: The editor fails
+	t.Setenv("EDITOR", "false")
+	assert.ErrorContains(t, Open(file), "failed to edit "+file+" with false")
+
+	// Test case 3: An encrypted file is edited decrypted, and encrypted back
+	defer func() { fileutil.EncryptionKey = nil }()
+	fileutil.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	assert.NoError(t, fileutil.AtomicWrite(file, []byte("# Sep 15 2025\n"), 0644))
+	t.Setenv("EDITOR", script+" edited")
+	assert.NoError(t, Open(file))
+	raw, _ := os.ReadFile(file)
+	assert.NotContains(t, string(raw), "Sep 15")
+	content, err := fileutil.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 15 2025\nedited\n", string(content))
+
+	// Test case 4: Without the key the encrypted file is not opened
+	fileutil.EncryptionKey = nil
+	assert.ErrorContains(t, Open(file), "the file is encrypted")
+}
@@ -0,0 +1,107 @@
+// Package encrypt encrypts data with AES-256-GCM: whole journal files at rest with a key file,
+// and texts such as the daily summaries with a passphrase.
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of the keys, in bytes (AES-256).
+const KeySize = 32
+
+// Header starts the data returned by Encrypt, so that encrypted files can be told from plain text ones.
+var Header = []byte("LOGBOOK-AES-GCM-1\n")
+
+// ErrDecrypt is returned by Decrypt when the key is wrong or the data was modified.
+var ErrDecrypt = errors.New("failed to decrypt: wrong key or corrupted data")
+
+// Encrypt encrypts plaintext with the key and returns it after Header, with its random nonce.
+// Encrypting the same text twice gives different results.
+func Encrypt(plaintext, key []byte) ([]byte, error) {
+	sealed, err := seal(plaintext, key)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), Header...), sealed...), nil
+}
+
+// Decrypt returns the plaintext of data encrypted by Encrypt with the same key.
+func Decrypt(ciphertext, key []byte) ([]byte, error) {
+	if !IsEncrypted(ciphertext) {
+		return nil, fmt.Errorf("failed to decrypt: the data was not encrypted by LogBook")
+	}
+	return open(ciphertext[len(Header):], key)
+}
+
+// seal encrypts plaintext with the key and returns its random nonce followed by the ciphertext, without Header.
+// It is the building block of Encrypt and EncryptWithPassphrase, that frame the data differently.
+func seal(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open returns the plaintext of data sealed by seal with the same key.
+func open(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether data starts with Header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, Header)
+}
+
+// LoadKey reads a key file: KeySize bytes written as hexadecimal, e.g. by "openssl rand -hex 32".
+func LoadKey(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key file %s: expected %d bytes written as hexadecimal", path, KeySize)
+	}
+	return key, nil
+}
+
+// newGCM returns the AES-GCM cipher of the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size %d, expected %d bytes", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,69 @@
+package encrypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, KeySize)
+	plaintext := []byte("# Sep 18 2025\n\n# LOG\n09:00 Talked with HR about the new role\n")
+
+	// Test case 1: Round-trip
+	encrypted, err := Encrypt(plaintext, key)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "HR")
+	decrypted, err := Decrypt(encrypted, key)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// Test case 2: The same text is encrypted differently every time
+	again, err := Encrypt(plaintext, key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	// Test case 3: Wrong key and corrupted data
+	_, err = Decrypt(encrypted, bytes.Repeat([]byte{0x24}, KeySize))
+	assert.ErrorIs(t, err, ErrDecrypt)
+	corrupted := append([]byte(nil), encrypted...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	_, err = Decrypt(corrupted, key)
+	assert.ErrorIs(t, err, ErrDecrypt)
+	_, err = Decrypt(Header, key)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	// Test case 4: Plain text and invalid keys
+	assert.False(t, IsEncrypted(plaintext))
+	_, err = Decrypt(plaintext, key)
+	assert.ErrorContains(t, err, "the data was not encrypted by LogBook")
+	_, err = Encrypt(plaintext, []byte("short"))
+	assert.EqualError(t, err, "invalid key size 5, expected 32 bytes")
+}
+
+func TestLoadKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeKey := func(content string) string {
+		path := filepath.Join(tmpDir, "logbook.key")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	// Test case 1: A hexadecimal key, with a trailing newline
+	key, err := LoadKey(writeKey("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f\n"))
+	assert.NoError(t, err)
+	assert.Len(t, key, KeySize)
+	assert.Equal(t, byte(0x1f), key[31])
+
+	// Test case 2: Invalid keys
+	_, err = LoadKey(writeKey("00010203"))
+	assert.ErrorContains(t, err, "expected 32 bytes written as hexadecimal")
+	_, err = LoadKey(writeKey("not hexadecimal"))
+	assert.ErrorContains(t, err, "expected 32 bytes written as hexadecimal")
+	_, err = LoadKey(filepath.Join(tmpDir, "missing.key"))
+	assert.ErrorContains(t, err, "failed to read key file")
+}
@@ -0,0 +1,62 @@
+package encrypt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	saltSize   = 16
+	iterations = 200000
+)
+
+// ErrPassphrase is returned by DecryptWithPassphrase when the passphrase is wrong or the data was modified.
+var ErrPassphrase = errors.New("failed to decrypt: wrong passphrase or corrupted data")
+
+// EncryptWithPassphrase encrypts plaintext with a key derived from the passphrase by PBKDF2-SHA256
+// and returns it base64 encoded, with its random salt and nonce.
+// Encrypting the same text twice gives different results.
+func EncryptWithPassphrase(plaintext, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	sealed, err := seal([]byte(plaintext), deriveKey(passphrase, salt))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(append(salt, sealed...)), nil
+}
+
+// DecryptWithPassphrase returns the plaintext of a text encrypted by EncryptWithPassphrase.
+func DecryptWithPassphrase(encoded, passphrase string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted data: %w", err)
+	}
+	if len(data) < saltSize {
+		return "", ErrPassphrase
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+	plaintext, err := open(data, deriveKey(passphrase, salt))
+	if errors.Is(err, ErrDecrypt) {
+		return "", ErrPassphrase
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// deriveKey returns the AES-256 key of the passphrase and salt.
+func deriveKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, iterations, KeySize, sha256.New)
+}
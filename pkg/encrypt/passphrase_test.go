@@ -0,0 +1,41 @@
+package encrypt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	// Test case 1: Round-trip
+	encrypted, err := EncryptWithPassphrase("Talked with HR about the new role.", "s3cret")
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, "HR")
+	decrypted, err := DecryptWithPassphrase(encrypted, "s3cret")
+	assert.NoError(t, err)
+	assert.Equal(t, "Talked with HR about the new role.", decrypted)
+
+	// Test case 2: The same text is encrypted differently every time
+	again, err := EncryptWithPassphrase("Talked with HR about the new role.", "s3cret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	// Test case 3: Wrong passphrase and corrupted data
+	_, err = DecryptWithPassphrase(encrypted, "wrong")
+	assert.ErrorIs(t, err, ErrPassphrase)
+	_, err = DecryptWithPassphrase(encrypted[:len(encrypted)-8]+"AAAAAAA=", "s3cret")
+	assert.ErrorIs(t, err, ErrPassphrase)
+	_, err = DecryptWithPassphrase("AAAA", "s3cret")
+	assert.ErrorIs(t, err, ErrPassphrase)
+	_, err = DecryptWithPassphrase("not base64!", "s3cret")
+	assert.ErrorContains(t, err, "failed to decode")
+
+	// Test case 4: Empty passphrase
+	_, err = EncryptWithPassphrase("text", "")
+	assert.ErrorContains(t, err, "passphrase cannot be empty")
+
+	// Test case 5: The summaries encrypted by the previous versions are still readable
+	decrypted, err = DecryptWithPassphrase("lSXM8cvSC2Hrx+xhctvclIhgbZ0OjPjdtKs3ZVG9tsnNEPBuaegp6S5QRiEIXylH9AhL+koAHaSfKuA=", "s3cret")
+	assert.NoError(t, err)
+	assert.Equal(t, "Talked with HR.", decrypted)
+}
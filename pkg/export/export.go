@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// Day is the export of a daily journal file.
+type Day struct {
+	Date    string   `json:"date"` // As YYYY-MM-DD
+	Summary string   `json:"summary"`
+	Entries []Entry  `json:"entries"`
+	Tags    []string `json:"tags"`
+}
+
+// Entry is the export of an entry of the LOG.
+type Entry struct {
+	Timestamp *time.Time `json:"timestamp"` // Nil for the text before the first timestamped entry
+	Text      string     `json:"text"`
+}
+
+// Days returns the export of the daily files from startDate to endDate, sorted by date.
+// A zero startDate or endDate leaves the range open on that side.
+func Days(cfg *config.Config, startDate, endDate time.Time) ([]Day, error) {
+	files, err := journal.ListJournalFiles(cfg, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []Day{}
+	for _, filePath := range files {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := journal.ExtractSummaryFull(filePath)
+		if err != nil {
+			return nil, err
+		}
+		logEntries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := journal.ExtractTags(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []Entry{}
+		for _, logEntry := range logEntries {
+			entry := Entry{Text: logEntry.Text}
+			if !logEntry.Time.IsZero() {
+				timestamp := logEntry.On(date)
+				entry.Timestamp = &timestamp
+			}
+			entries = append(entries, entry)
+		}
+		days = append(days, Day{Date: date.Format("2006-01-02"), Summary: summary.Full, Entries: entries, Tags: tags})
+	}
+	return days, nil
+}
+
+// ToJSON writes the Days from startDate to endDate to w as an indented JSON array.
+func ToJSON(cfg *config.Config, startDate, endDate time.Time, w io.Writer) error {
+	days, err := Days(cfg, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to export journal: %w", err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(days); err != nil {
+		return fmt.Errorf("failed to write JSON export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\nReleased v2.\n\n# LOG\n09:00 Released #v2\n10:30 Wrote the notes\nwith the changelog\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-20.md"), []byte("# Sep 20 2025\n\n# LOG\n08:00 Hiking\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_38.md"), []byte("# Weekly Review\n"), 0644)
+
+	// Test case 1: All the days, with their entries parsed back
+	var buf bytes.Buffer
+	assert.NoError(t, ToJSON(cfg, time.Time{}, time.Time{}, &buf))
+	var days []Day
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &days))
+	if assert.Len(t, days, 3) {
+		assert.Equal(t, "2025-09-15", days[0].Date)
+		assert.Equal(t, "Released v2.", days[0].Summary)
+		assert.Equal(t, []string{"v2"}, days[0].Tags)
+		if assert.Len(t, days[0].Entries, 2) {
+			assert.Equal(t, time.Date(2025, time.September, 15, 9, 0, 0, 0, time.UTC), *days[0].Entries[0].Timestamp)
+			assert.Equal(t, "Released #v2", days[0].Entries[0].Text)
+			assert.Equal(t, time.Date(2025, time.September, 15, 10, 30, 0, 0, time.UTC), *days[0].Entries[1].Timestamp)
+			assert.Equal(t, "Wrote the notes\nwith the changelog", days[0].Entries[1].Text)
+		}
+		assert.Empty(t, days[1].Summary)
+		assert.Empty(t, days[1].Entries)
+	}
+	assert.Contains(t, buf.String(), "\"timestamp\": \"2025-09-15T09:00:00Z\"")
+	assert.Contains(t, buf.String(), "\"entries\": [],\n    \"tags\": []")
+
+	// Test case 2: The date range is inclusive
+	buf.Reset()
+	assert.NoError(t, ToJSON(cfg, time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC), &buf))
+	days = nil
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &days))
+	if assert.Len(t, days, 2) {
+		assert.Equal(t, "2025-09-16", days[0].Date)
+		assert.Equal(t, "2025-09-20", days[1].Date)
+	}
+
+	// Test case 3: An empty range is an empty array
+	buf.Reset()
+	assert.NoError(t, ToJSON(cfg, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC), &buf))
+	assert.Equal(t, "[]\n", buf.String())
+
+	// Test case 4: Custom entry template
+	cfg.LogEntryTemplate = "[{{.Time | formatTime \"15:04\"}}] {{.Entry}}"
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-20.md"), []byte("# Sep 20 2025\n\n# LOG\n[08:00] Hiking\n"), 0644)
+	days, err := Days(cfg, time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC), time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, days, 1) && assert.Len(t, days[0].Entries, 1) {
+		assert.Equal(t, time.Date(2025, time.September, 20, 8, 0, 0, 0, time.UTC), *days[0].Entries[0].Timestamp)
+		assert.Equal(t, "Hiking", days[0].Entries[0].Text)
+	}
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+)
+
+// maxSnippetLength is the maximum number of characters of the summary snippets of index.html.
+const maxSnippetLength = 140
+
+// htmlStyle is the style sheet of the pages, inlined so that the site is self-contained.
+const htmlStyle = `body { font-family: sans-serif; max-width: 48em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+a { color: #0b61a4; }
+nav { display: flex; justify-content: space-between; margin: 1em 0; }
+.summary { font-style: italic; }
+.entries { list-style: none; padding: 0; }
+.entries li { margin: 0.5em 0; white-space: pre-wrap; }
+time { color: #777; margin-right: 0.5em; }
+.tags span { background: #eee; border-radius: 3px; padding: 0 0.3em; margin-right: 0.3em; }`
+
+// dayTemplate is the page of a day, named <date>.html.
+var dayTemplate = template.Must(template.New("day").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Day.Date}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<nav><a href="index.html">All days</a><span>{{if .Previous}}<a href="{{.Previous}}.html">&larr; {{.Previous}}</a>{{end}} {{if .Next}}<a href="{{.Next}}.html">{{.Next}} &rarr;</a>{{end}}</span></nav>
+<h1>{{.Heading}}</h1>
+{{if .Day.Summary}}<p class="summary">{{.Day.Summary}}</p>
+{{end}}{{if .Day.Tags}}<p class="tags">{{range .Day.Tags}}<span>#{{.}}</span>{{end}}</p>
+{{end}}<ul class="entries">
+{{range .Day.Entries}}<li>{{if .Timestamp}}<time>{{.Timestamp.Format "15:04"}}</time>{{end}}{{.Text}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// indexTemplate is index.html, listing the days newest first.
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>LogBook</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<h1>LogBook</h1>
+<ul>
+{{range .}}<li><a href="{{.Date}}.html">{{.Date}}</a>{{if .Snippet}}: {{.Snippet}}{{end}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// dayPage is the data of dayTemplate.
+type dayPage struct {
+	Day      Day
+	Heading  string // e.g. "Monday, September 15 2025"
+	Previous string // Date of the previous day, empty for the first one
+	Next     string // Date of the next day, empty for the last one
+}
+
+// indexItem is a day of indexTemplate.
+type indexItem struct {
+	Date    string
+	Snippet string
+}
+
+// ToHTML writes a static site of the journal to outputDir: a <date>.html page for each daily file,
+// with its summary and entries, and an index.html listing the days with a snippet of their summary.
+// The pages have no external dependencies.
+func ToHTML(cfg *config.Config, outputDir string) error {
+	days, err := Days(cfg, time.Time{}, time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to export journal: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
+	index := make([]indexItem, 0, len(days))
+	for i, day := range days {
+		page := dayPage{Day: day, Heading: day.Date}
+		if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+			page.Heading = date.Format("Monday, January 2 2006")
+		}
+		if i > 0 {
+			page.Previous = days[i-1].Date
+		}
+		if i < len(days)-1 {
+			page.Next = days[i+1].Date
+		}
+		if err := writeHTMLPage(filepath.Join(outputDir, day.Date+".html"), dayTemplate, page); err != nil {
+			return err
+		}
+		index = append(index, indexItem{Date: day.Date, Snippet: snippet(day.Summary)})
+	}
+
+	// Newest first
+	for i, j := 0, len(index)-1; i < j; i, j = i+1, j-1 {
+		index[i], index[j] = index[j], index[i]
+	}
+	return writeHTMLPage(filepath.Join(outputDir, "index.html"), indexTemplate, index)
+}
+
+// writeHTMLPage renders tmpl with data to path.
+func writeHTMLPage(path string, tmpl *template.Template, data any) error {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// snippet returns the first sentence of a summary, cut to maxSnippetLength characters.
+func snippet(summary string) string {
+	sentence := []rune(ai.FirstSentence(summary))
+	if len(sentence) <= maxSnippetLength {
+		return string(sentence)
+	}
+	return strings.TrimSpace(string(sentence[:maxSnippetLength-1])) + "…"
+}
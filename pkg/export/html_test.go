@@ -0,0 +1,48 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\nReleased v2. Then wrote the notes.\n\n# LOG\n09:00 Released #v2\n10:30 Fixed <script> escaping\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_38.md"), []byte("# Weekly Review\n"), 0644)
+	outputDir := filepath.Join(t.TempDir(), "site")
+
+	// Test case 1: The index links each day, newest first, with a snippet of its summary
+	assert.NoError(t, ToHTML(cfg, outputDir))
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `<li><a href="2025-09-15.html">2025-09-15</a>: Released v2.</li>`)
+	assert.Contains(t, string(index), `<li><a href="2025-09-16.html">2025-09-16</a></li>`)
+	assert.Less(t, strings.Index(string(index), "2025-09-16.html"), strings.Index(string(index), "2025-09-15.html"))
+	assert.NotContains(t, string(index), "review")
+
+	// Test case 2: Each day page is titled with its date, with its summary and escaped entries
+	page, err := os.ReadFile(filepath.Join(outputDir, "2025-09-15.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<title>2025-09-15</title>")
+	assert.Contains(t, string(page), "<h1>Monday, September 15 2025</h1>")
+	assert.Contains(t, string(page), `<p class="summary">Released v2. Then wrote the notes.</p>`)
+	assert.Contains(t, string(page), "<li><time>09:00</time>Released #v2</li>")
+	assert.Contains(t, string(page), "<li><time>10:30</time>Fixed &lt;script&gt; escaping</li>")
+	assert.Contains(t, string(page), `<a href="2025-09-16.html">2025-09-16 &rarr;</a>`)
+	page, err = os.ReadFile(filepath.Join(outputDir, "2025-09-16.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<title>2025-09-16</title>")
+	assert.NotContains(t, string(page), `class="summary"`)
+
+	// Test case 3: Long summaries are cut in the index
+	assert.Equal(t, "Short one.", snippet("Short one. Second sentence."))
+	assert.Equal(t, maxSnippetLength, len([]rune(snippet(strings.Repeat("a", 200)))))
+}
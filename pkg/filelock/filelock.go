@@ -0,0 +1,85 @@
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrTimeout is returned by TryLock when the lock is still held by someone else after the timeout.
+var ErrTimeout = errors.New("timed out waiting for the file lock")
+
+// retryInterval is the time TryLock waits between two attempts.
+const retryInterval = 10 * time.Millisecond
+
+// FileLock is an exclusive lock on a file, held across processes.
+type FileLock struct {
+	file *os.File
+}
+
+// lockPath returns the path of the lock file of path, e.g. "2025-09-15.md.lock" next to "2025-09-15.md".
+// The lock is not taken on path itself, as fileutil.AtomicWrite replaces it with a new file.
+// Lock files are kept after Unlock, removing them would let two processes lock different files.
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+// openLockFile opens, creating it if needed, the lock file of path.
+func openLockFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file of %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Lock takes the exclusive lock of path, waiting for as long as someone else holds it.
+func Lock(path string) (*FileLock, error) {
+	file, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file, true); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+// TryLock takes the exclusive lock of path, waiting up to timeout for someone else to release it.
+// Returns ErrTimeout if the lock is still held after timeout.
+func TryLock(path string, timeout time.Duration) (*FileLock, error) {
+	file, err := openLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		err := lockFile(file, false)
+		if err == nil {
+			return &FileLock{file: file}, nil
+		}
+		if !isWouldBlock(err) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, ErrTimeout)
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.file.Name(), err)
+	}
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close lock file %s: %w", l.file.Name(), err)
+	}
+	return nil
+}
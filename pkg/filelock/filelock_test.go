@@ -0,0 +1,64 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "2025-09-15.md")
+
+	// Test case 1: The lock file is next to the locked file, that does not need to exist
+	lock, err := Lock(path)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(tmpDir, "2025-09-15.md.lock"))
+	assert.NoFileExists(t, path)
+
+	// Test case 2: TryLock times out while the lock is held
+	start := time.Now()
+	_, err = TryLock(path, 50*time.Millisecond)
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Test case 3: TryLock gets the lock once released
+	go func(held *FileLock) {
+		time.Sleep(30 * time.Millisecond)
+		held.Unlock()
+	}(lock)
+	lock, err = TryLock(path, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, lock.Unlock())
+
+	// Test case 4: Lock serialises the critical sections
+	counter := filepath.Join(tmpDir, "counter")
+	os.WriteFile(counter, nil, 0644)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := Lock(counter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer lock.Unlock()
+			content, _ := os.ReadFile(counter)
+			time.Sleep(time.Millisecond)
+			os.WriteFile(counter, append(content, 'x'), 0644)
+		}()
+	}
+	wg.Wait()
+	content, _ := os.ReadFile(counter)
+	assert.Equal(t, "xxxxxxxxxx", string(content))
+
+	// Test case 5: Locking fails if the lock file cannot be created
+	_, err = Lock(filepath.Join(tmpDir, "missing", "2025-09-15.md"))
+	assert.ErrorContains(t, err, "failed to open lock file")
+}
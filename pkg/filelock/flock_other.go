@@ -0,0 +1,34 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile takes the exclusive lock of file with flock. Without blocking, it fails if the lock is already held.
+func lockFile(file *os.File, blocking bool) error {
+	how := unix.LOCK_EX
+	if !blocking {
+		how |= unix.LOCK_NB
+	}
+	for {
+		err := unix.Flock(int(file.Fd()), how)
+		if !errors.Is(err, unix.EINTR) {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the lock of file.
+func unlockFile(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
+
+// isWouldBlock reports whether a non blocking lockFile failed because the lock is held.
+func isWouldBlock(err error) bool {
+	return errors.Is(err, unix.EWOULDBLOCK)
+}
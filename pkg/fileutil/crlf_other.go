@@ -0,0 +1,8 @@
+//go:build !windows
+
+package fileutil
+
+// platformLineEndings returns data unchanged: PreserveCRLF only applies on Windows.
+func platformLineEndings(data []byte) []byte {
+	return data
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package fileutil
+
+// platformLineEndings converts data to Windows line endings when PreserveCRLF is set.
+func platformLineEndings(data []byte) []byte {
+	if PreserveCRLF {
+		return ToCRLF(data)
+	}
+	return data
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicWritePreserveCRLF(t *testing.T) {
+	t.Cleanup(func() { PreserveCRLF = false })
+	filePath := filepath.Join(t.TempDir(), "2025-09-18.md")
+
+	// Test case 1: Windows line endings are written when PreserveCRLF is set
+	PreserveCRLF = true
+	err := AtomicWrite(filePath, []byte("# Title\n\n# LOG\n"), 0644)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\r\n\r\n# LOG\r\n", string(content))
+
+	// Test case 2: Data is written as is otherwise
+	PreserveCRLF = false
+	err = AtomicWrite(filePath, []byte("# Title\n"), 0644)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n", string(content))
+}
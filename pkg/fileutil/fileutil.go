@@ -0,0 +1,80 @@
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/encrypt"
+)
+
+// TmpSuffix is appended to the path of a file being written by AtomicWrite.
+const TmpSuffix = ".tmp"
+
+// PreserveCRLF makes AtomicWrite write Windows line endings on Windows, see Config.PreserveCRLF.
+var PreserveCRLF bool
+
+// EncryptionKey makes AtomicWrite encrypt the files it writes and ReadFile decrypt them, see Config.Encrypt.
+// Nil to write plain text files.
+var EncryptionKey []byte
+
+// AtomicWrite writes data to path + TmpSuffix and then renames it over path,
+// so that an interrupted write never leaves path half written.
+// The data is encrypted when EncryptionKey is set.
+func AtomicWrite(path string, data []byte, perm os.FileMode) error {
+	data = platformLineEndings(data)
+	if EncryptionKey != nil {
+		encrypted, err := encrypt.Encrypt(data, EncryptionKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		data = encrypted
+	}
+	tmpPath := path + TmpSuffix
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return fmt.Errorf("failed to write temporary file %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s with %s: %w", path, tmpPath, err)
+	}
+	return nil
+}
+
+// ReadFile returns the content of a file written by AtomicWrite, decrypted if it is encrypted.
+// Plain text files are returned as they are, so that encryption can be enabled on an existing journal.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !encrypt.IsEncrypted(data) {
+		return data, err
+	}
+	if EncryptionKey == nil {
+		return nil, fmt.Errorf("the file is encrypted, set encrypt and encryption_key_file in the configuration")
+	}
+	return encrypt.Decrypt(data, EncryptionKey)
+}
+
+// SplitFrontMatter returns the lines of the YAML front matter of a Markdown file, without the fences, and the index
+// of the first line after it. The front matter opens with "---" and is closed by "---" or "...". A file without
+// front matter, or with one that is not closed, gives nil and 0.
+func SplitFrontMatter(lines []string) ([]string, int) {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed == "---" || trimmed == "..." {
+			return lines[1:i], i + 1
+		}
+	}
+	return nil, 0 // Not closed, it is not front matter
+}
+
+// NormaliseCRLF converts Windows (\r\n) and old Mac (\r) line endings to \n.
+func NormaliseCRLF(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\r", "\n")
+}
+
+// ToCRLF converts all line endings of data to \r\n.
+func ToCRLF(data []byte) []byte {
+	return []byte(strings.ReplaceAll(NormaliseCRLF(string(data)), "\n", "\r\n"))
+}
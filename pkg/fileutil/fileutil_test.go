@@ -0,0 +1,93 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+
+	// Test case 1: New file
+	err := AtomicWrite(filePath, []byte("first"), 0644)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+	assert.NoFileExists(t, filePath+TmpSuffix)
+
+	// Test case 2: Existing file is replaced
+	err = AtomicWrite(filePath, []byte("second"), 0644)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	assert.NoFileExists(t, filePath+TmpSuffix)
+
+	// Test case 3: Missing directory
+	err = AtomicWrite(filepath.Join(tmpDir, "missing", "file.md"), []byte("data"), 0644)
+	assert.ErrorContains(t, err, "failed to write temporary file")
+}
+
+func TestNormaliseCRLF(t *testing.T) {
+	// Test case 1: Windows, old Mac and Unix line endings
+	assert.Equal(t, "a\nb\nc\nd", NormaliseCRLF("a\r\nb\rc\nd"))
+	assert.Equal(t, "\n\n", NormaliseCRLF("\r\n\r\n"))
+
+	// Test case 2: Conversion back to Windows line endings does not double the \r
+	assert.Equal(t, "a\r\nb\r\nc\r\n", string(ToCRLF([]byte("a\r\nb\nc\r"))))
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	// Test case 1: The front matter lines and the index of the title, closed by "---" or "..."
+	for _, closing := range []string{"---", "..."} {
+		frontMatter, end := SplitFrontMatter([]string{"---", "title: Day", "tags: [go]", closing, "# Title"})
+		assert.Equal(t, []string{"title: Day", "tags: [go]"}, frontMatter)
+		assert.Equal(t, 4, end)
+	}
+
+	// Test case 2: No front matter, or one that is not closed
+	for _, lines := range [][]string{{"# Title", "---"}, {"---", "title: Day"}, nil} {
+		frontMatter, end := SplitFrontMatter(lines)
+		assert.Nil(t, frontMatter)
+		assert.Equal(t, 0, end)
+	}
+}
+
+func TestEncryptedWrite(t *testing.T) {
+	defer func() { EncryptionKey = nil }()
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+
+	// Test case 1: Plain text files are read as they are, with or without key
+	assert.NoError(t, AtomicWrite(filePath, []byte("# LOG\n09:00 Plain\n"), 0644))
+	content, err := ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# LOG\n09:00 Plain\n", string(content))
+
+	// Test case 2: With a key the file is encrypted on disk and decrypted by ReadFile
+	EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	assert.NoError(t, AtomicWrite(filePath, []byte("# LOG\n09:00 Secret\n"), 0644))
+	raw, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "Secret")
+	content, err = ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# LOG\n09:00 Secret\n", string(content))
+
+	// Test case 3: An encrypted file without key, or with the wrong one
+	EncryptionKey = nil
+	_, err = ReadFile(filePath)
+	assert.ErrorContains(t, err, "the file is encrypted")
+	EncryptionKey = []byte("fedcba9876543210fedcba9876543210")
+	_, err = ReadFile(filePath)
+	assert.ErrorContains(t, err, "wrong key")
+
+	// Test case 4: Missing file
+	_, err = ReadFile(filepath.Join(tmpDir, "missing.md"))
+	assert.True(t, os.IsNotExist(err))
+}
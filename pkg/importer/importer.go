@@ -0,0 +1,167 @@
+// Package importer copies Markdown notes written with other tools, e.g. Obsidian or Notion, into the journal.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// ConflictStrategy tells ImportFile what to do when the journal already has a file for the date of the note.
+type ConflictStrategy int
+
+const (
+	ConflictFail      ConflictStrategy = iota // Return ErrFileExists
+	ConflictSkip                              // Keep the journal file and return ErrSkipped
+	ConflictOverwrite                         // Replace the journal file with the note
+)
+
+var (
+	// ErrFileExists is returned by ImportFile with ConflictFail when the journal already has a file for the date.
+	ErrFileExists = errors.New("a journal file already exists for this date")
+	// ErrSkipped is returned by ImportFile with ConflictSkip when the note was not imported.
+	ErrSkipped = errors.New("skipped")
+)
+
+// dateLayouts are the accepted formats of the "date:" field of the front matter.
+var dateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// importConcurrency is the number of journal files ImportFiles writes at the same time.
+const importConcurrency = 4
+
+// ImportFile copies the Markdown file srcPath into cfg.JournalDir as the daily file of its date, named after
+// cfg.DailyFileName. The date is the "date:" field of the YAML front matter, or the modification time of the file.
+// The content is copied unchanged; an empty note gives a file from the daily template.
+func ImportFile(cfg *config.Config, srcPath string, strategy ConflictStrategy) error {
+	return ImportFiles(cfg, []string{srcPath}, strategy)[0]
+}
+
+// ImportFiles imports the Markdown files srcPaths as ImportFile does, writing the journal files with
+// journal.BatchCreateFiles. The notes of the same date are imported in order: with ConflictOverwrite the last one
+// is kept. It returns the error of each note, in the order of srcPaths, nil for the imported ones.
+func ImportFiles(cfg *config.Config, srcPaths []string, strategy ConflictStrategy) []error {
+	errs := make([]error, len(srcPaths))
+	var entries []journal.BatchEntry
+	var notes []int // Index in srcPaths of each entry
+	for i, srcPath := range srcPaths {
+		content, date, err := readNote(srcPath)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		entries = append(entries, journal.BatchEntry{Date: date, Content: string(content)})
+		notes = append(notes, i)
+	}
+
+	results, err := journal.BatchCreateFiles(cfg, entries, importConcurrency)
+	if err != nil {
+		for _, i := range notes {
+			errs[i] = fmt.Errorf("failed to import %s: %w", srcPaths[i], err)
+		}
+		return errs
+	}
+	for j, result := range results {
+		i := notes[j]
+		switch {
+		case result.Err != nil:
+			errs[i] = fmt.Errorf("failed to import %s: %w", srcPaths[i], result.Err)
+		case result.Created:
+		case strategy == ConflictSkip:
+			errs[i] = fmt.Errorf("%w, %s already exists", ErrSkipped, result.Path)
+		case strategy == ConflictOverwrite:
+			if err := fileutil.AtomicWrite(result.Path, []byte(entries[j].Content), 0644); err != nil {
+				errs[i] = fmt.Errorf("failed to write journal file %s: %w", result.Path, err)
+			}
+		default:
+			errs[i] = fmt.Errorf("failed to import %s to %s: %w", srcPaths[i], result.Path, ErrFileExists)
+		}
+	}
+	return errs
+}
+
+// readNote returns the content of a note and its date, see ImportFile.
+func readNote(srcPath string) ([]byte, time.Time, error) {
+	content, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read %s: %w", srcPath, err)
+	}
+	date, ok, err := FrontMatterDate(string(content))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to import %s: %w", srcPath, err)
+	}
+	if !ok {
+		info, err := os.Stat(srcPath)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to read %s: %w", srcPath, err)
+		}
+		date = info.ModTime()
+	}
+	return content, date, nil
+}
+
+// FrontMatterDate returns the "date:" field of the YAML front matter, see journal.SplitFrontMatter, of a Markdown file.
+// ok is false if the file has no front matter or no date field; an error is returned for a date in unknown format.
+func FrontMatterDate(content string) (date time.Time, ok bool, err error) {
+	frontMatter, _ := journal.SplitFrontMatter(strings.Split(fileutil.NormaliseCRLF(content), "\n"))
+	for _, line := range frontMatter {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found || strings.TrimSpace(key) != "date" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		for _, layout := range dateLayouts {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date, true, nil
+			}
+		}
+		return time.Time{}, false, fmt.Errorf("invalid front matter date %q, expected YYYY-MM-DD", value)
+	}
+	return time.Time{}, false, nil
+}
+
+// MarkdownFiles returns the Markdown files matching pathOrGlob, sorted: the file itself, the files matching
+// a glob pattern like "notes/*.md", or all the .md files under a directory.
+func MarkdownFiles(pathOrGlob string) ([]string, error) {
+	matches, err := filepath.Glob(pathOrGlob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pathOrGlob, err)
+	}
+
+	var files []string
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", match, err)
+		}
+		if !info.IsDir() {
+			files = append(files, match)
+			continue
+		}
+		err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", match, err)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Markdown files found in %s", pathOrGlob)
+	}
+	sort.Strings(files)
+	return files, nil
+}
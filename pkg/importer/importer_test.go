@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontMatterDate(t *testing.T) {
+	// Test case 1: Date field, quoted or not, with or without time
+	date, ok, err := FrontMatterDate("---\ntitle: Notes\ndate: 2023-04-05\n---\n# Notes\n")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2023, time.April, 5, 0, 0, 0, 0, time.UTC), date)
+	date, ok, err = FrontMatterDate("---\r\ndate: \"2023-04-05T10:30:00Z\"\r\n---\r\n")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2023, time.April, 5, 10, 30, 0, 0, time.UTC), date)
+
+	// Test case 2: No front matter, no date field, or front matter not closed
+	for _, content := range []string{"# Notes\ndate: 2023-04-05\n", "---\ntitle: Notes\n---\n", "---\ndate 2023-04-05\n", ""} {
+		_, ok, err = FrontMatterDate(content)
+		assert.NoError(t, err, content)
+		assert.False(t, ok, content)
+	}
+
+	// Test case 3: Unknown date format
+	_, _, err = FrontMatterDate("---\ndate: 05/04/2023\n---\n")
+	assert.EqualError(t, err, `invalid front matter date "05/04/2023", expected YYYY-MM-DD`)
+}
+
+func TestImportFile(t *testing.T) {
+	srcDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = t.TempDir()
+	writeNote := func(name, content string) string {
+		path := filepath.Join(srcDir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	// Test case 1: The date of the front matter names the journal file, the content is unchanged
+	note := writeNote("meeting.md", "---\ndate: 2023-04-05\n---\n# Meeting\n\nDiscussed the roadmap.\n")
+	assert.NoError(t, ImportFile(cfg, note, ConflictFail))
+	content, err := os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-05.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ndate: 2023-04-05\n---\n# Meeting\n\nDiscussed the roadmap.\n", string(content))
+
+	// Test case 2: Without front matter the modification time is used
+	note = writeNote("old.md", "# Old note\n")
+	mtime := time.Date(2021, time.March, 14, 12, 0, 0, 0, time.Local)
+	assert.NoError(t, os.Chtimes(note, mtime, mtime))
+	assert.NoError(t, ImportFile(cfg, note, ConflictFail))
+	assert.FileExists(t, filepath.Join(cfg.JournalDir, "2021-03-14.md"))
+
+	// Test case 3: A file for the same date is not replaced by default
+	note = writeNote("other.md", "---\ndate: 2023-04-05\n---\n# Other\n")
+	err = ImportFile(cfg, note, ConflictFail)
+	assert.ErrorIs(t, err, ErrFileExists)
+
+	// Test case 4: Skip keeps the existing file
+	err = ImportFile(cfg, note, ConflictSkip)
+	assert.ErrorIs(t, err, ErrSkipped)
+	content, _ = os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-05.md"))
+	assert.Contains(t, string(content), "# Meeting")
+
+	// Test case 5: Overwrite replaces the existing file
+	assert.NoError(t, ImportFile(cfg, note, ConflictOverwrite))
+	content, _ = os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-05.md"))
+	assert.Equal(t, "---\ndate: 2023-04-05\n---\n# Other\n", string(content))
+
+	// Test case 6: Missing file and invalid date
+	assert.ErrorContains(t, ImportFile(cfg, filepath.Join(srcDir, "missing.md"), ConflictFail), "failed to read")
+	note = writeNote("bad.md", "---\ndate: yesterday\n---\n")
+	assert.ErrorContains(t, ImportFile(cfg, note, ConflictFail), "invalid front matter date")
+}
+
+func TestMarkdownFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "vault", "2023"), 0755)
+	for _, name := range []string{"vault/a.md", "vault/2023/b.MD", "vault/image.png", "c.md"} {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte("# Note\n"), 0644)
+	}
+
+	// Test case 1: A directory gives its Markdown files, recursively
+	files, err := MarkdownFiles(filepath.Join(tmpDir, "vault"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "vault", "2023", "b.MD"), filepath.Join(tmpDir, "vault", "a.md")}, files)
+
+	// Test case 2: Glob pattern and single file
+	files, err = MarkdownFiles(filepath.Join(tmpDir, "*.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "c.md")}, files)
+	files, err = MarkdownFiles(filepath.Join(tmpDir, "c.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "c.md")}, files)
+
+	// Test case 3: Nothing found
+	_, err = MarkdownFiles(filepath.Join(tmpDir, "missing"))
+	assert.ErrorContains(t, err, "no Markdown files found")
+}
+
+func TestImportFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = t.TempDir()
+	var notes []string
+	for i, content := range []string{
+		"---\ndate: 2023-04-05\n---\n# First\n",
+		"---\ndate: 2023-04-06\n---\n# Other day\n",
+		"---\ndate: 2023-04-05\n---\n# Second\n",
+		"---\ndate: tomorrow\n---\n",
+	} {
+		notes = append(notes, filepath.Join(srcDir, fmt.Sprintf("note%d.md", i)))
+		os.WriteFile(notes[i], []byte(content), 0644)
+	}
+
+	// Test case 1: The errors are in the order of the notes, the first note of a date is imported
+	errs := ImportFiles(cfg, notes, ConflictFail)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], ErrFileExists)
+	assert.ErrorContains(t, errs[3], "invalid front matter date")
+	content, _ := os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-05.md"))
+	assert.Equal(t, "---\ndate: 2023-04-05\n---\n# First\n", string(content))
+	content, _ = os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-06.md"))
+	assert.Equal(t, "---\ndate: 2023-04-06\n---\n# Other day\n", string(content))
+
+	// Test case 2: With overwrite the last note of a date is kept
+	errs = ImportFiles(cfg, notes[:3], ConflictOverwrite)
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	content, _ = os.ReadFile(filepath.Join(cfg.JournalDir, "2023-04-05.md"))
+	assert.Equal(t, "---\ndate: 2023-04-05\n---\n# Second\n", string(content))
+
+	// Test case 3: The journal files are created in the directories of DailyFileName
+	cfg.DailyFileName = "{{.Date | formatDate \"2006/01/2006-01-02\"}}.md"
+	errs = ImportFiles(cfg, notes[1:2], ConflictFail)
+	assert.NoError(t, errs[0])
+	assert.FileExists(t, filepath.Join(cfg.JournalDir, "2023", "04", "2023-04-06.md"))
+}
@@ -0,0 +1,124 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// BatchEntry is a daily journal file to create with BatchCreateFiles.
+type BatchEntry struct {
+	Date    time.Time
+	Content string // Content of the file. If empty, the file is created from the daily template.
+}
+
+// BatchResult is the outcome of a BatchEntry.
+type BatchResult struct {
+	Path    string
+	Created bool // False if the file already existed, it is left untouched
+	Err     error
+}
+
+// fileLocks serialises the writes to the same daily file, also across concurrent batches.
+var fileLocks sync.Map // path -> *sync.Mutex
+
+// lockFile locks the given file path and returns the function to unlock it.
+func lockFile(path string) func() {
+	value, _ := fileLocks.LoadOrStore(path, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// BatchCreateFiles creates the daily journal files of the entries with a pool of concurrency workers.
+// Results are returned in the order of the entries. The entries of the same date are written one after the
+// other, in their order, so only the first one creates the file.
+// The returned error is only about the whole batch; the error of each entry is in its BatchResult.
+func BatchCreateFiles(cfg *config.Config, entries []BatchEntry, concurrency int) ([]BatchResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(entries))
+
+	// Group the entries by file, so that a single worker handles each file in the order of the entries
+	var groups [][]int
+	groupOf := make(map[string]int)
+	for i, entry := range entries {
+		path, err := DailyFilePath(cfg, entry.Date)
+		if err != nil {
+			results[i] = BatchResult{Err: err}
+			continue
+		}
+		results[i].Path = path
+		g, ok := groupOf[path]
+		if !ok {
+			g = len(groups)
+			groupOf[path] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				unlock := lockFile(results[group[0]].Path)
+				for _, i := range group {
+					results[i].Created, results[i].Err = createBatchFile(cfg, results[i].Path, entries[i])
+				}
+				unlock()
+			}
+		}()
+	}
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+// createBatchFile creates the file of a BatchEntry at path, if it does not exist.
+func createBatchFile(cfg *config.Config, path string, entry BatchEntry) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to check file %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create directory for journal file: %w", err)
+	}
+
+	// The end of the day is never before Config.DayBoundaryHour, so the file of entry.Date is created.
+	// It is built in the configured timezone, as CreateDailyJournalFile moves the date there.
+	location := entry.Date.Location()
+	if cfg.Timezone != "" {
+		location = cfg.Location()
+	}
+	endOfDay := time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 23, 59, 59, 0, location)
+	if _, _, err := CreateDailyJournalFile(cfg, endOfDay, nil, nil); err != nil {
+		return false, err
+	}
+	if entry.Content == "" {
+		return true, nil
+	}
+	if err := fileutil.AtomicWrite(path, []byte(entry.Content), 0644); err != nil {
+		return true, fmt.Errorf("failed to write journal file %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,93 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchCreateFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"Jan 02 2006\"}}\n\n# LOG\n"
+
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 0, 0, 0, 0, time.UTC) }
+
+	// Test case 1: All files are created, results are in the order of the entries
+	var entries []BatchEntry
+	for d := 1; d <= 20; d++ {
+		entries = append(entries, BatchEntry{Date: day(d), Content: "# Imported " + day(d).Format("2006-01-02") + "\n"})
+	}
+	results, err := BatchCreateFiles(cfg, entries, 4)
+	assert.NoError(t, err)
+	assert.Len(t, results, 20)
+	for i, result := range results {
+		expectedPath := filepath.Join(tmpDir, day(i+1).Format("2006-01-02")+".md")
+		assert.NoError(t, result.Err)
+		assert.True(t, result.Created)
+		assert.Equal(t, expectedPath, result.Path)
+		content, _ := os.ReadFile(expectedPath)
+		assert.Equal(t, entries[i].Content, string(content))
+	}
+
+	// Test case 2: Entries of the same date do not overwrite each other, only the first one creates the file
+	results, err = BatchCreateFiles(cfg, []BatchEntry{
+		{Date: day(21), Content: "first\n"},
+		{Date: day(22), Content: "other\n"},
+		{Date: day(21), Content: "second\n"},
+	}, 3)
+	assert.NoError(t, err)
+	assert.True(t, results[0].Created)
+	assert.True(t, results[1].Created)
+	assert.False(t, results[2].Created)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, results[0].Path, results[2].Path)
+	content, _ := os.ReadFile(results[0].Path)
+	assert.Equal(t, "first\n", string(content))
+
+	// Test case 3: Existing files are left untouched, entries without content use the daily template
+	cfg.DayBoundaryHour = 4
+	results, err = BatchCreateFiles(cfg, []BatchEntry{{Date: day(1), Content: "replaced\n"}, {Date: day(23)}}, 0)
+	assert.NoError(t, err)
+	assert.False(t, results[0].Created)
+	content, _ = os.ReadFile(results[0].Path)
+	assert.Equal(t, entries[0].Content, string(content))
+	assert.True(t, results[1].Created)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-23.md"), results[1].Path)
+	content, _ = os.ReadFile(results[1].Path)
+	assert.Equal(t, "# Sep 23 2025\n\n# LOG\n", string(content))
+
+	// Test case 4: Errors of single entries are in their result
+	cfg.JournalDir = "relative/path"
+	results, err = BatchCreateFiles(cfg, entries[:2], 2)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "JournalDir must be an absolute path")
+	assert.ErrorContains(t, results[1].Err, "JournalDir must be an absolute path")
+
+	// Test case 5: Dates in UTC with a timezone ahead of it, the content of each date goes in its file
+	cfg.JournalDir = t.TempDir()
+	cfg.DayBoundaryHour = 0
+	cfg.Timezone = "Europe/Rome"
+	results, err = BatchCreateFiles(cfg, []BatchEntry{{Date: day(1), Content: "A\n"}, {Date: day(2), Content: "B\n"}}, 2)
+	assert.NoError(t, err)
+	for i, expected := range []string{"A\n", "B\n"} {
+		assert.NoError(t, results[i].Err)
+		assert.True(t, results[i].Created)
+		assert.Equal(t, filepath.Join(cfg.JournalDir, day(i+1).Format("2006-01-02")+".md"), results[i].Path)
+		content, _ = os.ReadFile(results[i].Path)
+		assert.Equal(t, expected, string(content))
+	}
+	files, _ := os.ReadDir(cfg.JournalDir)
+	assert.Len(t, files, 2)
+	cfg.Timezone = ""
+
+	// Test case 6: Invalid configuration
+	cfg.DayBoundaryHour = 24
+	_, err = BatchCreateFiles(cfg, entries, 2)
+	assert.ErrorContains(t, err, "invalid configuration")
+}
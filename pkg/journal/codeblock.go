@@ -0,0 +1,77 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// CodeBlock is a fenced code block of the "LOG" chapter of a journal file.
+type CodeBlock struct {
+	Language   string // Language after the opening fence, e.g. "go", empty if none
+	Content    string // Lines between the fences, without the final newline
+	LineNumber int    // 1-based line number of the opening fence
+}
+
+// codeFence opens and closes a code block.
+const codeFence = "```"
+
+// ExtractCodeBlocks returns the fenced code blocks of the "LOG" chapter of a journal file, in file order.
+// Code blocks elsewhere, e.g. in the summary, are ignored. A block missing its closing fence ends with the file.
+func ExtractCodeBlocks(filePath string) ([]CodeBlock, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	var blocks []CodeBlock
+	var current *CodeBlock
+	var blockLines []string
+	inLogChapter := false
+	for i, line := range strings.Split(NormaliseCRLF(string(content)), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current != nil {
+			if trimmed == codeFence {
+				current.Content = strings.Join(blockLines, "\n")
+				blocks = append(blocks, *current)
+				current = nil
+				continue
+			}
+			blockLines = append(blockLines, line)
+			continue
+		}
+
+		if isLogHeader(trimmed) {
+			inLogChapter = true
+			continue
+		}
+		if !inLogChapter {
+			continue
+		}
+		if IsSectionHeader(trimmed) {
+			break // Reached the next chapter
+		}
+		if strings.HasPrefix(trimmed, codeFence) {
+			current = &CodeBlock{Language: strings.TrimSpace(strings.TrimPrefix(trimmed, codeFence)), LineNumber: i + 1}
+			blockLines = nil
+		}
+	}
+	if current != nil {
+		current.Content = strings.TrimRight(strings.Join(blockLines, "\n"), "\n")
+		blocks = append(blocks, *current)
+	}
+
+	return blocks, nil
+}
+
+// FilterCodeBlocksByLanguage returns the code blocks of the given language, compared case insensitively.
+func FilterCodeBlocksByLanguage(blocks []CodeBlock, language string) []CodeBlock {
+	var filtered []CodeBlock
+	for _, block := range blocks {
+		if strings.EqualFold(block.Language, language) {
+			filtered = append(filtered, block)
+		}
+	}
+	return filtered
+}
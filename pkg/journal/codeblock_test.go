@@ -0,0 +1,61 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	content := "# Sep 15 2025\n" +
+		"Summary with an example:\n" +
+		"```sh\necho summary\n```\n\n" +
+		"# LOG\n" +
+		"09:00 Found the bug\n" +
+		"```go\n" +
+		"func main() {\n" +
+		"\tfmt.Println(\"hello\")\n" +
+		"}\n" +
+		"```\n" +
+		"10:00 Tested the script\n" +
+		"```python\n# Not a chapter header\nprint(1)\n```\n" +
+		"11:00 Plain block\n" +
+		"```\nmake test\n```\n" +
+		"12:00 Another Go snippet\n" +
+		"```Go\nx := 1\n```\n" +
+		"\n# Notes\n```go\nignored := true\n```\n"
+	os.WriteFile(filePath, []byte(content), 0644)
+
+	// Test case 1: Only the blocks of the LOG chapter, with multiple lines kept intact
+	blocks, err := ExtractCodeBlocks(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []CodeBlock{
+		{Language: "go", Content: "func main() {\n\tfmt.Println(\"hello\")\n}", LineNumber: 9},
+		{Language: "python", Content: "# Not a chapter header\nprint(1)", LineNumber: 15},
+		{Language: "", Content: "make test", LineNumber: 20},
+		{Language: "Go", Content: "x := 1", LineNumber: 24},
+	}, blocks)
+
+	// Test case 2: Filtering by language
+	goBlocks := FilterCodeBlocksByLanguage(blocks, "go")
+	assert.Len(t, goBlocks, 2)
+	assert.Equal(t, "x := 1", goBlocks[1].Content)
+	pythonBlocks := FilterCodeBlocksByLanguage(blocks, "python")
+	assert.Len(t, pythonBlocks, 1)
+	assert.Empty(t, FilterCodeBlocksByLanguage(blocks, "rust"))
+
+	// Test case 3: A single block without closing fence
+	filePath = filepath.Join(tmpDir, "2025-09-16.md")
+	os.WriteFile(filePath, []byte("# Sep 16 2025\n\n# LOG\n09:00 Query\n```sql\nSELECT 1;\n"), 0644)
+	blocks, err = ExtractCodeBlocks(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []CodeBlock{{Language: "sql", Content: "SELECT 1;", LineNumber: 5}}, blocks)
+
+	// Test case 4: Missing file
+	_, err = ExtractCodeBlocks(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
@@ -0,0 +1,73 @@
+package journal
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+)
+
+// dailyFile is a daily journal file with its date.
+type dailyFile struct {
+	path string
+	date time.Time
+}
+
+// listDailyFiles returns all the daily files of cfg.JournalDir, sorted by date.
+// Files whose name does not match cfg.DailyFileName, e.g. the reviews, are ignored.
+func listDailyFiles(cfg *config.Config) ([]dailyFile, error) {
+	var files []dailyFile
+	err := filepath.WalkDir(cfg.JournalDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if date, err := DateFromFilePath(cfg, path); err == nil {
+			files = append(files, dailyFile{path: path, date: date})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files: %w", err)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].date.Before(files[j].date)
+	})
+	return files, nil
+}
+
+// listDailyFilesInRange returns the daily files of cfg.JournalDir from startDate to endDate, sorted by date.
+// A zero startDate or endDate leaves the range open on that side.
+func listDailyFilesInRange(cfg *config.Config, startDate, endDate time.Time) ([]dailyFile, error) {
+	files, err := listDailyFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var inRange []dailyFile
+	for _, file := range files {
+		if (!startDate.IsZero() && file.date.Before(startDate)) || (!endDate.IsZero() && file.date.After(endDate)) {
+			continue
+		}
+		inRange = append(inRange, file)
+	}
+	return inRange, nil
+}
+
+// ListJournalFiles returns the paths of the daily files of cfg.JournalDir from startDate to endDate, sorted by date.
+// Unlike ListJournalFilesByPeriod, a zero startDate or endDate leaves the range open on that side.
+func ListJournalFiles(cfg *config.Config, startDate, endDate time.Time) ([]string, error) {
+	files, err := listDailyFilesInRange(cfg, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+	return paths, nil
+}
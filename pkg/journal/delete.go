@@ -0,0 +1,64 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// DeletePreviewLines is the number of lines of a journal file shown by ConfirmDelete.
+const DeletePreviewLines = 5
+
+// DeleteDailyFile removes the daily journal file of the given date.
+func DeleteDailyFile(cfg *config.Config, date time.Time) error {
+	filePath, err := DailyFilePath(cfg, date)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no journal file for %s: %s does not exist", date.Format("2006-01-02"), filePath)
+		}
+		return fmt.Errorf("failed to delete journal file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// ConfirmDelete writes to w the first DeletePreviewLines lines of a journal file and asks whether to delete it,
+// reading the answer from reader. Only "y" or "yes" confirm the deletion.
+func ConfirmDelete(filePath string, reader io.Reader, w io.Writer) (bool, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, fmt.Errorf("journal file %s does not exist", filePath)
+		}
+		return false, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(NormaliseCRLF(string(content)), "\n"), "\n")
+	fmt.Fprintln(w, filePath)
+	for i, line := range lines {
+		if i == DeletePreviewLines {
+			fmt.Fprintf(w, "... (%d more lines)\n", len(lines)-DeletePreviewLines)
+			break
+		}
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+
+	fmt.Fprint(w, "Delete this file? [y/N] ")
+	scanner := bufio.NewScanner(reader)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return false, fmt.Errorf("failed to read answer: %w", err)
+		}
+		return false, nil // No answer means no
+	}
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,62 @@
+package journal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteDailyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	date := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	content := "# Sep 15 2025\n\nTest day.\n\n# LOG\n09:00 First\n09:05 Second\n"
+
+	// Test case 1: Answering "n" keeps the file, after showing its first lines
+	os.WriteFile(filePath, []byte(content), 0644)
+	var out bytes.Buffer
+	confirmed, err := ConfirmDelete(filePath, strings.NewReader("n\n"), &out)
+	assert.NoError(t, err)
+	assert.False(t, confirmed)
+	assert.Equal(t, filePath+"\n  # Sep 15 2025\n  \n  Test day.\n  \n  # LOG\n... (2 more lines)\nDelete this file? [y/N] ", out.String())
+	assert.FileExists(t, filePath)
+
+	// Test case 2: Answering "y" confirms, and the file is deleted
+	for _, answer := range []string{"y\n", "Y", " yes \n"} {
+		confirmed, err = ConfirmDelete(filePath, strings.NewReader(answer), &bytes.Buffer{})
+		assert.NoError(t, err)
+		assert.True(t, confirmed, "answer %q", answer)
+	}
+	assert.NoError(t, DeleteDailyFile(cfg, date))
+	assert.NoFileExists(t, filePath)
+
+	// Test case 3: No answer or anything else than yes does not confirm
+	os.WriteFile(filePath, []byte(content), 0644)
+	for _, answer := range []string{"", "\n", "nope\n", "delete\n"} {
+		confirmed, err = ConfirmDelete(filePath, strings.NewReader(answer), &bytes.Buffer{})
+		assert.NoError(t, err)
+		assert.False(t, confirmed, "answer %q", answer)
+	}
+
+	// Test case 4: Short files are shown entirely
+	os.WriteFile(filePath, []byte("# Sep 15 2025\n"), 0644)
+	out.Reset()
+	_, err = ConfirmDelete(filePath, strings.NewReader("n\n"), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, filePath+"\n  # Sep 15 2025\nDelete this file? [y/N] ", out.String())
+
+	// Test case 5: Missing files
+	os.Remove(filePath)
+	_, err = ConfirmDelete(filePath, strings.NewReader("y\n"), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "does not exist")
+	err = DeleteDailyFile(cfg, date)
+	assert.EqualError(t, err, "no journal file for 2025-09-15: "+filePath+" does not exist")
+}
@@ -0,0 +1,73 @@
+package journal
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+)
+
+// Emotion is a mood recorded in a log entry with a "mood:" label, e.g. "10:00 Demo went well mood:happy".
+type Emotion struct {
+	Time  time.Time // Entry time on the day of the journal file
+	Mood  string    // The emoji of the mood
+	Label string    // The mood as written after "mood:", e.g. "Happy"
+	Text  string    // The entry text without the label
+}
+
+// moodLabelPattern matches the "mood:" label of a log entry, optionally in brackets as "[mood:happy]".
+// The mood is a word or an emoji.
+var moodLabelPattern = regexp.MustCompile(`(?i)\[?\bmood:\s*([^\s\]]+)\]?`)
+
+// moodEmojis maps the mood words to their emoji. Other moods, e.g. emojis, are kept as written.
+var moodEmojis = map[string]string{
+	"happy":    "😊",
+	"good":     "😊",
+	"neutral":  "😐",
+	"ok":       "😐",
+	"sad":      "😔",
+	"bad":      "😔",
+	"excited":  "🎉",
+	"great":    "🎉",
+	"angry":    "😠",
+	"tired":    "😴",
+	"stressed": "😰",
+	"anxious":  "😰",
+}
+
+// ExtractEmotions returns the moods recorded in the log entries of a journal file, in chronological order.
+// Entries are placed on the date of the file when its name matches cfg.DailyFileName.
+func ExtractEmotions(cfg *config.Config, filePath string) ([]Emotion, error) {
+	entries, err := ExtractLogEntries(cfg, filePath)
+	if err != nil {
+		return nil, err
+	}
+	date, dateErr := DateFromFilePath(cfg, filePath)
+
+	var emotions []Emotion
+	for _, entry := range entries {
+		match := moodLabelPattern.FindStringSubmatch(entry.Text)
+		if match == nil {
+			continue
+		}
+		label := strings.TrimRight(match[1], ".,;:!?)")
+		mood := label
+		if emoji, ok := moodEmojis[strings.ToLower(label)]; ok {
+			mood = emoji
+		}
+		timestamp := entry.Time
+		if dateErr == nil {
+			timestamp = entry.On(date)
+		}
+		text := strings.Join(strings.Fields(moodLabelPattern.ReplaceAllString(entry.Text, "")), " ")
+		emotions = append(emotions, Emotion{Time: timestamp, Mood: mood, Label: label, Text: text})
+	}
+
+	// Files written with LogEntryOrder "prepend" list the newest entries first
+	sort.SliceStable(emotions, func(i, j int) bool {
+		return emotions[i].Time.Before(emotions[j].Time)
+	})
+	return emotions, nil
+}
@@ -0,0 +1,54 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractEmotions(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	// Test case 1: Mood words are mapped to emojis, other moods are kept as written
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	content := "# Sep 15 2025\n\n# LOG\n" +
+		"09:00 Standup mood:neutral\n" +
+		"11:00 Fixed the flaky test\n" +
+		"14:00 Demo went well [mood:Happy]\n" +
+		"18:00 Long day mood: 🥱\n"
+	os.WriteFile(filePath, []byte(content), 0644)
+
+	emotions, err := ExtractEmotions(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []Emotion{
+		{Time: time.Date(2025, time.September, 15, 9, 0, 0, 0, time.UTC), Mood: "😐", Label: "neutral", Text: "Standup"},
+		{Time: time.Date(2025, time.September, 15, 14, 0, 0, 0, time.UTC), Mood: "😊", Label: "Happy", Text: "Demo went well"},
+		{Time: time.Date(2025, time.September, 15, 18, 0, 0, 0, time.UTC), Mood: "🥱", Label: "🥱", Text: "Long day"},
+	}, emotions)
+
+	// Test case 2: Prepended entries are returned in chronological order
+	filePath = filepath.Join(tmpDir, "2025-09-16.md")
+	os.WriteFile(filePath, []byte("# Sep 16 2025\n\n# LOG\n17:00 Release mood:excited\n10:00 Outage mood:stressed\n"), 0644)
+	emotions, err = ExtractEmotions(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, emotions, 2)
+	assert.Equal(t, "😰", emotions[0].Mood)
+	assert.Equal(t, "🎉", emotions[1].Mood)
+
+	// Test case 3: No moods
+	filePath = filepath.Join(tmpDir, "2025-09-17.md")
+	os.WriteFile(filePath, []byte("# Sep 17 2025\n\n# LOG\n10:00 Nothing special\n"), 0644)
+	emotions, err = ExtractEmotions(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Empty(t, emotions)
+
+	// Test case 4: Missing file
+	_, err = ExtractEmotions(cfg, filepath.Join(tmpDir, "2025-09-18.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
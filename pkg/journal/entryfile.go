@@ -0,0 +1,94 @@
+package journal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxEntryFileSize is the maximum size of a file read by ReadEntryFromFile.
+const MaxEntryFileSize = 64 * 1024
+
+// ErrFileTooLarge is returned by ReadEntryFromFile for files larger than MaxEntryFileSize.
+var ErrFileTooLarge = errors.New("file too large")
+
+// ReadEntryFromFile returns the content of a text or Markdown file as the text of a log entry.
+// YAML frontmatter is stripped, and the "# Title" first line of a Markdown file becomes the first line of the entry.
+// Empty lines are removed, as they would end the entry in the LOG chapter.
+func ReadEntryFromFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read entry file %s: %w", path, err)
+	}
+	if info.Size() > MaxEntryFileSize {
+		return "", fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrFileTooLarge, path, info.Size(), MaxEntryFileSize)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read entry file %s: %w", path, err)
+	}
+
+	lines := strings.Split(stripFrontmatter(NormaliseCRLF(string(content))), "\n")
+	var entryLines []string
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(entryLines) == 0 && strings.EqualFold(filepath.Ext(path), ".md") && strings.HasPrefix(line, "# ") {
+			line = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+		entryLines = append(entryLines, line)
+	}
+	if len(entryLines) == 0 {
+		return "", fmt.Errorf("entry file %s is empty", path)
+	}
+	return strings.Join(entryLines, "\n"), nil
+}
+
+// stripFrontmatter removes the YAML frontmatter, see SplitFrontMatter, at the beginning of a file.
+func stripFrontmatter(content string) string {
+	lines := strings.Split(content, "\n")
+	_, end := SplitFrontMatter(lines)
+	return strings.Join(lines[end:], "\n")
+}
+
+// ReadEntries returns the log entries typed or piped to the standard input, read from reader.
+// When interactive, that is the input is a terminal, it writes a prompt to w and reads a single line.
+// Otherwise it reads up to the end of the input and every non-empty line becomes a separate entry,
+// e.g. to log each line of "git log --oneline"; surrounding spaces are trimmed.
+func ReadEntries(reader io.Reader, interactive bool, w io.Writer) ([]string, error) {
+	scanner := bufio.NewScanner(io.LimitReader(reader, MaxEntryFileSize))
+	if interactive {
+		fmt.Fprint(w, "Entry: ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("failed to read entry: %w", err)
+			}
+			return nil, fmt.Errorf("no entry typed")
+		}
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" {
+			return nil, fmt.Errorf("no entry typed")
+		}
+		return []string{entry}, nil
+	}
+
+	var entries []string
+	for scanner.Scan() {
+		if entry := strings.TrimSpace(scanner.Text()); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entry read from the standard input")
+	}
+	return entries, nil
+}
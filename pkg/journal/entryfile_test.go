@@ -0,0 +1,69 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadEntryFromFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	// Test case 1: Plain text file
+	entry, err := ReadEntryFromFile(writeFile("entry.txt", "Investigated the memory leak.\n\nIt was the cache.\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Investigated the memory leak.\nIt was the cache.", entry)
+
+	// Test case 2: Markdown with frontmatter and title
+	entry, err = ReadEntryFromFile(writeFile("entry.md", "---\ntags: [work]\ndate: 2025-09-15\n---\n# Memory leak fixed\n\nThe cache was never evicted.\n- Added a TTL\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Memory leak fixed\nThe cache was never evicted.\n- Added a TTL", entry)
+
+	// Test case 3: A title is kept as is in text files, an unclosed frontmatter is not stripped
+	entry, err = ReadEntryFromFile(writeFile("notes.txt", "# Not a title\n---\nkey: value\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# Not a title\n---\nkey: value", entry)
+	entry, err = ReadEntryFromFile(writeFile("unclosed.md", "---\nkey: value\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "---\nkey: value", entry)
+
+	// Test case 4: Oversized file
+	_, err = ReadEntryFromFile(writeFile("big.txt", strings.Repeat("a", MaxEntryFileSize+1)))
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+
+	// Test case 5: Non-existent and empty files
+	_, err = ReadEntryFromFile(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read entry file")
+	assert.ErrorContains(t, err, "missing.md")
+	_, err = ReadEntryFromFile(writeFile("empty.md", "---\ntitle: x\n---\n\n"))
+	assert.ErrorContains(t, err, "is empty")
+}
+
+func TestReadEntries(t *testing.T) {
+	// Test case 1: Piped input, every non-empty line is an entry
+	var out strings.Builder
+	entries, err := ReadEntries(strings.NewReader("Fixed bug #123\n\n  Reviewed PR #45  \nDeployed\n"), false, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Fixed bug #123", "Reviewed PR #45", "Deployed"}, entries)
+	assert.Empty(t, out.String())
+
+	// Test case 2: Interactive input, a prompt is shown and only the first line is read
+	entries, err = ReadEntries(strings.NewReader("Typed entry\nnot read\n"), true, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Typed entry"}, entries)
+	assert.Equal(t, "Entry: ", out.String())
+
+	// Test case 3: Empty input
+	_, err = ReadEntries(strings.NewReader(" \n\n"), false, &out)
+	assert.ErrorContains(t, err, "no entry read")
+	_, err = ReadEntries(strings.NewReader(""), true, &out)
+	assert.ErrorContains(t, err, "no entry typed")
+}
@@ -0,0 +1,59 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+)
+
+// FollowLog appends every non-empty line read from reader to the daily journal file of the time returned by now,
+// until the end of the input, e.g. Ctrl-D in a terminal. A confirmation is written to w for each entry.
+// The daily files are finalized with FinalizeDailyFile once, when the day changes and at the end of the input,
+// rather than after each entry.
+func FollowLog(cfg *config.Config, reader io.Reader, w io.Writer, now func() time.Time, opts AppendOptions) error {
+	var filePath string
+	var fileDate time.Time
+	finalize := func() error {
+		if filePath == "" {
+			return nil
+		}
+		if err := FinalizeDailyFile(cfg, filePath, fileDate); err != nil {
+			return fmt.Errorf("failed to finalize daily file %s: %w", filePath, err)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		entry := scanner.Text()
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		timestamp := inTimezone(cfg, now())
+		entryFilePath, _, err := CreateDailyJournalFile(cfg, timestamp, nil, nil)
+		if err != nil {
+			return err
+		}
+		if entryFilePath != filePath {
+			// The day changed: the previous file is complete
+			if err := finalize(); err != nil {
+				return err
+			}
+			filePath, fileDate = entryFilePath, EffectiveDate(timestamp, cfg.DayBoundaryHour)
+		}
+
+		if err := AppendToLogWithOptions(cfg, filePath, entry, timestamp, opts); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Entry added to log at %s.\n", timestamp.Format("15:04"))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+	return finalize()
+}
@@ -0,0 +1,54 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# One-line note\n\n# LOG\n"
+
+	times := []time.Time{
+		time.Date(2025, time.September, 18, 23, 50, 0, 0, time.UTC),
+		time.Date(2025, time.September, 18, 23, 55, 0, 0, time.UTC),
+		time.Date(2025, time.September, 19, 0, 5, 0, 0, time.UTC),
+	}
+	now := func() time.Time {
+		next := times[0]
+		times = times[1:]
+		return next
+	}
+
+	// Test case 1: Each non-empty line is an entry, with the time it was read, in the file of its day
+	var out strings.Builder
+	err := FollowLog(cfg, strings.NewReader("Started the migration\n\n   \nStill migrating\nDone after midnight\n"), &out, now, AppendOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Entry added to log at 23:50.\nEntry added to log at 23:55.\nEntry added to log at 00:05.\n", out.String())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "2025-09-18.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "23:50 Started the migration\n23:55 Still migrating\n")
+	assert.NotContains(t, string(content), "Done after midnight")
+	content, err = os.ReadFile(filepath.Join(tmpDir, "2025-09-19.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "00:05 Done after midnight\n")
+
+	// Test case 2: Both files are finalized, with their one-line notes
+	assert.Contains(t, string(content), "# One-line note\n* [[2025-09-12]] (1 week ago)")
+	content, _ = os.ReadFile(filepath.Join(tmpDir, "2025-09-18.md"))
+	assert.Contains(t, string(content), "# One-line note\n* [[2025-09-11]] (1 week ago)")
+
+	// Test case 3: Empty input adds nothing
+	out.Reset()
+	assert.NoError(t, FollowLog(cfg, strings.NewReader(""), &out, now, AppendOptions{}))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,189 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// frontMatterFence opens and closes the YAML front matter at the top of a daily file.
+const frontMatterFence = "---"
+
+// FrontMatter is the YAML front matter of a daily file, the "---" delimited block at its top used by
+// tools like Hugo, Jekyll and Obsidian. Only the fields below are parsed, a small subset of YAML:
+// "key: value" lines, and lists written either as "[a, b]" or as "- item" lines.
+type FrontMatter struct {
+	Title string
+	Tags  []string
+	Mood  string
+	Other []string // The lines of the other keys, kept as they are
+}
+
+// SplitFrontMatter returns the lines of the front matter of a Markdown file, without the fences, and the index
+// of the first line after it, see fileutil.SplitFrontMatter.
+func SplitFrontMatter(lines []string) ([]string, int) {
+	return fileutil.SplitFrontMatter(lines)
+}
+
+// titleIndex returns the index of the title line of a daily file, that is the first line after the front matter.
+func titleIndex(lines []string) int {
+	_, end := SplitFrontMatter(lines)
+	return end
+}
+
+// ParseFrontMatter reads the front matter of a daily file. It returns nil, without error, if the file has none.
+func ParseFrontMatter(filePath string) (*FrontMatter, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	lines, end := SplitFrontMatter(strings.Split(NormaliseCRLF(string(content)), "\n"))
+	if end == 0 {
+		return nil, nil
+	}
+
+	fm := &FrontMatter{}
+	for i := 0; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok || strings.HasPrefix(lines[i], " ") || strings.HasPrefix(lines[i], "-") {
+			fm.Other = append(fm.Other, lines[i])
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "title":
+			fm.Title = unquoteYAML(value)
+		case "mood":
+			fm.Mood = unquoteYAML(value)
+		case "tags":
+			if value != "" {
+				fm.Tags = parseYAMLList(value)
+				continue
+			}
+			// A block list, one "- item" per line
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "-") {
+				i++
+				fm.Tags = append(fm.Tags, unquoteYAML(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "-"))))
+			}
+		default:
+			fm.Other = append(fm.Other, lines[i])
+		}
+	}
+	return fm, nil
+}
+
+// WriteFrontMatter writes fm at the top of a daily file, replacing its front matter if it has one.
+// The rest of the file is left as it is.
+func WriteFrontMatter(filePath string, fm *FrontMatter) error {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	lines := strings.Split(string(content), "\n")
+	_, end := SplitFrontMatter(lines)
+
+	header := []string{frontMatterFence}
+	if fm.Title != "" {
+		header = append(header, "title: "+quoteYAML(fm.Title))
+	}
+	if len(fm.Tags) > 0 {
+		header = append(header, "tags:")
+		for _, tag := range fm.Tags {
+			header = append(header, "  - "+quoteYAML(tag))
+		}
+	}
+	if fm.Mood != "" {
+		header = append(header, "mood: "+quoteYAML(fm.Mood))
+	}
+	header = append(header, fm.Other...)
+	header = append(header, frontMatterFence)
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat journal file %s: %w", filePath, err)
+	}
+	err = fileutil.AtomicWrite(filePath, []byte(strings.Join(append(header, lines[end:]...), "\n")), info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to write to journal file: %w", err)
+	}
+	return nil
+}
+
+// AddFrontMatterTags adds the tags missing from the front matter of a daily file, normalized as NormalizeTag does.
+// Files without front matter are left unchanged. The file is locked with filelock from reading to writing.
+func AddFrontMatterTags(filePath string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	fm, err := ParseFrontMatter(filePath)
+	if err != nil || fm == nil {
+		return err
+	}
+	changed := false
+	for _, tag := range tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, existing := range fm.Tags {
+			if strings.EqualFold(strings.TrimPrefix(existing, "#"), normalized) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fm.Tags = append(fm.Tags, normalized)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return WriteFrontMatter(filePath, fm)
+}
+
+// parseYAMLList parses a flow list like "[work, golang]"; a single value gives a list of one item.
+func parseYAMLList(value string) []string {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return []string{unquoteYAML(value)}
+	}
+	var items []string
+	for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+		if item = unquoteYAML(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// unquoteYAML returns a scalar without its single or double quotes.
+func unquoteYAML(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// quoteYAML quotes a scalar when YAML would not read it back as the same string.
+func quoteYAML(value string) string {
+	if value == strings.TrimSpace(value) && !strings.ContainsAny(value, ":#[]{},\"'&*!|>%@`") && !strings.HasPrefix(value, "-") {
+		return value
+	}
+	return strconv.Quote(value)
+}
@@ -0,0 +1,136 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontMatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	body := "# Sep 18 2025 Thursday\n\nFixed the parser and reviewed two PRs.\n\n# LOG\n09:00 Fixed the parser #work\n"
+
+	// Test case 1: A file without front matter
+	os.WriteFile(filePath, []byte(body), 0644)
+	fm, err := ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, fm)
+	assert.NoError(t, AddFrontMatterTags(filePath, []string{"work"}))
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, body, string(content), "files without front matter are not changed")
+
+	// Test case 2: Title, mood, a flow list of tags and the other keys
+	os.WriteFile(filePath, []byte("---\ntitle: \"Parser: day 2\"\ntags: [work, golang]\nmood: happy\naliases:\n  - parser\ndraft: false\n---\n"+body), 0644)
+	fm, err = ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, &FrontMatter{
+		Title: "Parser: day 2",
+		Tags:  []string{"work", "golang"},
+		Mood:  "happy",
+		Other: []string{"aliases:", "  - parser", "draft: false"},
+	}, fm)
+	summary, err := ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed the parser and reviewed two PRs.", summary)
+
+	// Test case 3: Tags are added once, the other keys and the body are unaffected
+	assert.NoError(t, AddFrontMatterTags(filePath, []string{"#Golang", "review"}))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "---\ntitle: \"Parser: day 2\"\ntags:\n  - work\n  - golang\n  - review\nmood: happy\naliases:\n  - parser\ndraft: false\n---\n"+body, string(content))
+	fm, err = ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"work", "golang", "review"}, fm.Tags)
+	assert.Equal(t, []string{"aliases:", "  - parser", "draft: false"}, fm.Other)
+
+	// Test case 4: Writing front matter to a file without it, quoted values are read back
+	os.WriteFile(filePath, []byte(body), 0644)
+	assert.NoError(t, WriteFrontMatter(filePath, &FrontMatter{Title: "It's #1", Tags: []string{"work"}}))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "---\ntitle: \"It's #1\"\ntags:\n  - work\n---\n"+body, string(content))
+	fm, err = ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "It's #1", fm.Title)
+	summary, err = ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed the parser and reviewed two PRs.", summary)
+
+	// Test case 5: A "---" line that is not closed is not front matter
+	os.WriteFile(filePath, []byte("---\ntitle: draft\n"+body), 0644)
+	fm, err = ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, fm)
+
+	// Test case 6: Missing file
+	_, err = ParseFrontMatter(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
+
+func TestFrontMatterSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	frontMatter := "---\ntitle: Day\ntags: [work]\n---\n"
+	summarizer := &ai.RecordingMockSummarizer{Summary: "New summary."}
+
+	// Test case 1: The missing summary is written after the title, the front matter is not a summary
+	os.WriteFile(filePath, []byte(frontMatter+"# Sep 18 2025\n<!-- summary below -->\n\n# LOG\n09:00 Fixed the parser\n"), 0644)
+	assert.NoError(t, GenerateSummaryIfMissing(context.Background(), filePath, cfg, summarizer, "Summarize", nil))
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, frontMatter+"# Sep 18 2025\n<!-- summary below -->\nNew summary.\n\n# LOG\n09:00 Fixed the parser\n", string(content))
+	assert.NotContains(t, summarizer.Calls[0].Text, "tags:")
+
+	// Test case 2: A forced regeneration replaces the summary only
+	summarizer.Summary = "Newer summary."
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", summarizer, nil, true))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, frontMatter+"# Sep 18 2025\n<!-- summary below -->\nNewer summary.\n\n# LOG\n09:00 Fixed the parser\n", string(content))
+	fm, err := ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, &FrontMatter{Title: "Day", Tags: []string{"work"}}, fm)
+
+	// Test case 3: The AI title goes in the title line, not in the front matter
+	assert.NoError(t, UpdateFileTitle(filePath, "Parser day"))
+	content, _ = os.ReadFile(filePath)
+	assert.True(t, strings.HasPrefix(string(content), frontMatter+"# Parser day"+titleSeparator+"Sep 18 2025\n"))
+}
+
+func TestAddFrontMatterTagsConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	os.WriteFile(filePath, []byte("---\ntags: [work]\n---\n# Sep 18 2025\n\n# LOG\n"), 0644)
+	date := time.Date(2025, time.September, 18, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1: No entry appended while the tags are added is lost, and no tag
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, AppendToLog(cfg, filePath, fmt.Sprintf("Entry %d", i), date))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, AddFrontMatterTags(filePath, []string{fmt.Sprintf("tag%d", i)}))
+		}(i)
+	}
+	wg.Wait()
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 10)
+	fm, err := ParseFrontMatter(filePath)
+	assert.NoError(t, err)
+	assert.Len(t, fm.Tags, 11)
+}
@@ -0,0 +1,11 @@
+package journal
+
+import "strings"
+
+// CheckHabitEntry reports whether the text of a log entry mentions the habit, ignoring case.
+func CheckHabitEntry(logEntry string, habit string) bool {
+	if habit == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(logEntry), strings.ToLower(habit))
+}
@@ -0,0 +1,17 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHabitEntry(t *testing.T) {
+	// Test case 1: Case insensitive mention
+	assert.True(t, CheckHabitEntry("Morning Exercise at the park", "exercise"))
+	assert.True(t, CheckHabitEntry("30 min reading", "Reading"))
+
+	// Test case 2: No mention or empty habit
+	assert.False(t, CheckHabitEntry("Worked on the release", "exercise"))
+	assert.False(t, CheckHabitEntry("Worked on the release", ""))
+}
@@ -2,22 +2,27 @@ package journal
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/clobrano/LogBook/pkg/ai"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
 	"github.com/clobrano/LogBook/pkg/oneline"
 	"github.com/clobrano/LogBook/pkg/template"
-
-	"github.com/fatih/color"
+	"github.com/clobrano/LogBook/pkg/theme"
 )
 
 // CreateDailyJournalFile creates a new daily journal file based on the current date and configuration.
+// With Config.DayBoundaryHour set, the file of the previous day is used before that hour, see EffectiveDate.
 func CreateDailyJournalFile(cfg *config.Config, date time.Time, summarizer ai.AISummarizer, reader io.Reader) (string, string, error) {
 	if err := cfg.Validate(); err != nil {
 		return "", "", fmt.Errorf("invalid configuration: %w", err)
@@ -35,6 +40,11 @@ func CreateDailyJournalFile(cfg *config.Config, date time.Time, summarizer ai.AI
 		}
 	}
 
+	date = inTimezone(cfg, date)
+	if cfg.DayBoundaryHour > 0 {
+		date = EffectiveDate(date, cfg.DayBoundaryHour)
+	}
+
 	// Render the file name using the template engine
 
 	data := template.TemplateData{Date: date}
@@ -47,55 +57,335 @@ func CreateDailyJournalFile(cfg *config.Config, date time.Time, summarizer ai.AI
 
 	// Check if file already exists
 	if _, err := os.Stat(filePath); err == nil {
-		return filePath, color.GreenString("Daily journal file already exists: %s", filePath), nil
+		return filePath, theme.Success("Daily journal file already exists: %s", filePath), nil
+	}
+
+	templateString, err := dailyTemplateString(cfg)
+	if err != nil {
+		return "", "", err
 	}
 
-	file, err := os.Create(filePath)
+	templateContent, err := template.Render(templateString, data)
 	if err != nil {
+		return "", "", fmt.Errorf("failed to render daily template: %w", err)
+	}
+
+	if err := fileutil.AtomicWrite(filePath, []byte(templateContent), 0644); err != nil {
 		return "", "", fmt.Errorf("failed to create daily journal file: %w", err)
 	}
-	defer file.Close()
 
-	// Use hardcoded template
-	templateContent := fmt.Sprintf("# %s\n<!-- add today summary below this line. If missing, the AI will generate one for you according to configuration file -->\n\n# One-line note\n\n# LOG\n\n", date.Format("Jan 02 2006 Monday"))
+	return filePath, theme.Success("Daily journal file created: %s", filePath), nil
+}
+
+// EffectiveDate returns the day a time belongs to when days start at dayBoundaryHour instead of midnight,
+// that is the previous day for the times before that hour.
+func EffectiveDate(now time.Time, dayBoundaryHour int) time.Time {
+	if now.Hour() < dayBoundaryHour {
+		return now.AddDate(0, 0, -1)
+	}
+	return now
+}
+
+// ParseDay returns the time of a YYYY-MM-DD date whose daily file is the file of that date, e.g. to log to a past day:
+// the start of the day in the configured timezone, that is at Config.DayBoundaryHour.
+func ParseDay(cfg *config.Config, day string) (time.Time, error) {
+	parsed, err := time.ParseInLocation("2006-01-02", day, cfg.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", day)
+	}
+	return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), cfg.DayBoundaryHour, 0, 0, 0, parsed.Location()), nil
+}
 
-	_, err = file.WriteString(templateContent)
+// AtClock returns now with the time of day replaced by clock, given as HH:MM, e.g. to back-date an entry.
+func AtClock(now time.Time, clock string) (time.Time, error) {
+	parsed, err := time.Parse("15:04", clock)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to write daily template to file: %w", err)
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", clock)
 	}
+	return time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location()), nil
+}
 
-	return filePath, color.GreenString("Daily journal file created: %s", filePath), nil
+// inTimezone returns t in the location of Config.Timezone, or t unchanged if no timezone is configured.
+func inTimezone(cfg *config.Config, t time.Time) time.Time {
+	if cfg.Timezone == "" {
+		return t
+	}
+	return t.In(cfg.Location())
 }
 
-// FinalizeDailyFile embeds one-line notes for a daily journal file.
+// dailyTemplateString returns the template used for new daily files.
+// The content of DailyTemplateFile takes precedence over DailyTemplate; if the file is absent, DailyTemplate is used.
+func dailyTemplateString(cfg *config.Config) (string, error) {
+	if cfg.DailyTemplateFile == "" {
+		return cfg.DailyTemplate, nil
+	}
+
+	content, err := os.ReadFile(cfg.DailyTemplateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg.DailyTemplate, nil
+		}
+		return "", fmt.Errorf("failed to read daily template file %s: %w", cfg.DailyTemplateFile, err)
+	}
+	return string(content), nil
+}
+
+// FinalizeDailyFile embeds one-line notes for a daily journal file, unless the "one_line_notes" section is disabled.
+// With Config.AITitleEnabled, it also generates the title of the file, if it has none yet.
 // This should be called after all log entries have been added for the day.
 func FinalizeDailyFile(cfg *config.Config, filePath string, date time.Time) error {
-	// Embed one-line notes from past entries
-	pastSummaries, err := oneline.GetPastSummaries(cfg, date)
+	if config.SectionEnabled(cfg, config.SectionOneLineNotes) {
+		// Files created from an older template may lack the one-line note section
+		if err := EnsureOneLineNoteSection(filePath, oneLineNoteSectionMarker); err != nil {
+			return fmt.Errorf("failed to ensure one-line note section: %w", err)
+		}
+
+		// Embed one-line notes from past entries
+		pastSummaries, err := oneline.GetPastSummaries(cfg, date)
+		if err != nil {
+			return fmt.Errorf("failed to get past summaries for one-line notes: %w", err)
+		}
+
+		err = oneline.EmbedOneLineNotes(filePath, date, pastSummaries)
+		if err != nil {
+			return fmt.Errorf("failed to embed one-line notes: %w", err)
+		}
+	}
+
+	if cfg.AITitleEnabled && cfg.AISummarizer != nil {
+		titled, err := hasGeneratedTitle(filePath)
+		if err != nil {
+			return err
+		}
+		if titled {
+			return nil // The title is generated once, not on every entry
+		}
+		entries, err := ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read log entries for the title: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil // Nothing to describe yet
+		}
+
+		texts := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			texts = append(texts, entry.Text)
+		}
+		title, err := ai.GenerateTitle(context.Background(), strings.Join(texts, "\n"), cfg.AITitlePrompt, cfg.AISummarizer)
+		if err != nil {
+			return fmt.Errorf("failed to generate title: %w", err)
+		}
+		if err := updateFileTitle(filePath, title, false); err != nil {
+			return fmt.Errorf("failed to update title: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hasGeneratedTitle reports whether the title of a journal file was already set by UpdateFileTitle.
+func hasGeneratedTitle(filePath string) (bool, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	lines := strings.Split(string(content), "\n")
+	title := titleIndex(lines)
+	if title >= len(lines) || !strings.HasPrefix(lines[title], "# ") {
+		return false, fmt.Errorf("title not found in file: %s", filePath)
+	}
+	return strings.Contains(lines[title], titleSeparator), nil
+}
+
+// titleSeparator separates a generated title from the original date heading in the first line of a daily file.
+const titleSeparator = " – "
+
+// UpdateFileTitle sets the first line of a journal file to "# <newTitle> – <original heading>".
+// The original heading (usually the date) is preserved, so the function can be called again to replace the title.
+// The file is locked with filelock while it is read and rewritten.
+func UpdateFileTitle(filePath string, newTitle string) error {
+	return updateFileTitle(filePath, newTitle, true)
+}
+
+// updateFileTitle implements UpdateFileTitle. Unless replace is set, a previously generated title is kept.
+func updateFileTitle(filePath string, newTitle string, replace bool) error {
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	title := titleIndex(lines)
+	if title >= len(lines) || !strings.HasPrefix(lines[title], "# ") {
+		return fmt.Errorf("title not found in file: %s", filePath)
+	}
+
+	heading := strings.TrimSpace(strings.TrimPrefix(lines[title], "# "))
+	if idx := strings.LastIndex(heading, titleSeparator); idx != -1 {
+		if !replace {
+			return nil // Titled by someone else meanwhile
+		}
+		heading = heading[idx+len(titleSeparator):] // Drop the previously generated title
+	}
+	lines[title] = "# " + strings.TrimSpace(newTitle) + titleSeparator + heading
+
+	err = fileutil.AtomicWrite(filePath, []byte(strings.Join(lines, "\n")), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write to journal file: %w", err)
+	}
+	return nil
+}
+
+// oneLineNoteSectionMarker is the header of the section holding the one-line notes.
+const oneLineNoteSectionMarker = "# One-line note"
+
+// EnsureOneLineNoteSection adds the sectionMarker header right before the "LOG" chapter if the file does not have it yet.
+// A header with the same name at any level, e.g. "## One-line note", counts as present, as in oneline.EmbedOneLineNotes.
+// The file is locked with filelock while it is read and rewritten.
+func EnsureOneLineNoteSection(filePath string, sectionMarker string) error {
+	lock, err := filelock.Lock(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to get past summaries for one-line notes: %w", err)
+		return err
 	}
+	defer lock.Unlock()
 
-	err = oneline.EmbedOneLineNotes(filePath, pastSummaries)
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to embed one-line notes: %w", err)
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	sectionName := strings.TrimSpace(strings.TrimLeft(sectionMarker, "#"))
+	lines := strings.Split(string(content), "\n")
+	logChapterIndex := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if IsSectionHeader(trimmed) && strings.TrimSpace(strings.TrimLeft(trimmed, "#")) == sectionName {
+			return nil // Section already present
+		}
+		if logChapterIndex == -1 && isLogHeader(trimmed) {
+			logChapterIndex = i
+		}
+	}
+
+	if logChapterIndex == -1 {
+		return fmt.Errorf("LOG chapter not found in file: %s", filePath)
 	}
 
+	before := strings.TrimRight(strings.Join(lines[:logChapterIndex], "\n"), "\n")
+	if before != "" {
+		before += "\n\n"
+	}
+	modifiedContent := before + sectionMarker + "\n\n" + strings.Join(lines[logChapterIndex:], "\n")
+
+	err = fileutil.AtomicWrite(filePath, []byte(modifiedContent), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write to journal file: %w", err)
+	}
 	return nil
 }
 
+// AppendOptions controls where AppendToLogWithOptions places a new entry.
+type AppendOptions struct {
+	Prepend     bool     // Insert the entry at the top of the "LOG" chapter (newest first)
+	PrependDate bool     // Write the date before the entry time, formatted with cfg.EntryDatePrefix
+	Tags        []string // Tags appended to the entry as hashtags, see AppendTags
+}
+
 // AppendToLog appends a new entry to the "LOG" chapter of a daily journal file.
+// Entries are placed according to cfg.LogEntryOrder.
 func AppendToLog(cfg *config.Config, filePath, entry string, timestamp time.Time) error {
-	content, err := os.ReadFile(filePath)
+	return AppendToLogWithOptions(cfg, filePath, entry, timestamp, AppendOptions{
+		Prepend: cfg.LogEntryOrder == config.LogEntryOrderPrepend,
+	})
+}
+
+// AppendToLogWithOptions adds a new entry to the "LOG" chapter of a daily journal file.
+// By default the entry goes after the last existing one, with opts.Prepend it goes right after the chapter header.
+// The file is locked with filelock from reading to writing.
+func AppendToLogWithOptions(cfg *config.Config, filePath, entry string, timestamp time.Time, opts AppendOptions) error {
+	newEntryLine, err := FormatLogEntry(cfg, entry, timestamp, opts)
+	if err != nil {
+		return err
+	}
+	if err := insertLogEntry(cfg, filePath, newEntryLine, opts); err != nil {
+		return err
+	}
+
+	// The file is unlocked: regenerating the summary locks it again
+	if cfg.AutoReSummarizeAfterEntries > 0 && cfg.AISummarizer != nil {
+		entries, err := ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return err
+		}
+		if len(entries)%cfg.AutoReSummarizeAfterEntries == 0 {
+			if err := RegenerateSummary(context.Background(), cfg, filePath, cfg.AIPrompt, cfg.AISummarizer, nil, true); err != nil {
+				fmt.Println(theme.Warning("Failed to regenerate the summary of %s: %v", filePath, err))
+			}
+		}
+	}
+	return nil
+}
+
+// FormatLogEntry returns the line of the "LOG" chapter that AppendToLogWithOptions writes for the entry, without writing it:
+// the entry with its tags, trimmed and prefixed according to cfg, rendered with cfg.LogEntryTemplate.
+func FormatLogEntry(cfg *config.Config, entry string, timestamp time.Time, opts AppendOptions) (string, error) {
+	timestamp = inTimezone(cfg, timestamp)
+
+	entry, err := AppendTags(entry, opts.Tags)
+	if err != nil {
+		return "", err
+	}
+	if cfg.TrimEntries {
+		entry = strings.TrimRight(entry, " \t")
+	}
+	if cfg.EntryPrefix != "" {
+		prefix, err := template.Render(cfg.EntryPrefix, template.TemplateData{Date: timestamp, Time: timestamp})
+		if err != nil {
+			return "", fmt.Errorf("failed to render entry prefix: %w", err)
+		}
+		entry = prefix + " " + entry
+	}
+
+	// Render the log entry using the configurable template
+	data := template.TemplateData{
+		Date:       timestamp,
+		Time:       timestamp,
+		Entry:      entry,
+		DatePrefix: cfg.EntryDatePrefix,
+	}
+	newEntryLine, err := template.Render(logEntryTemplate(cfg, opts.PrependDate), data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render log entry template: %w", err)
+	}
+	return newEntryLine, nil
+}
+
+// insertLogEntry writes the rendered entry line of AppendToLogWithOptions, holding the lock of the file.
+func insertLogEntry(cfg *config.Config, filePath, newEntryLine string, opts AppendOptions) error {
+	// Other logbook processes may be writing the same file
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(NormaliseCRLF(string(content)), "\n")
 	logChapterIndex := -1
 
 	for i, line := range lines {
-		if strings.HasPrefix(line, "# LOG") {
+		if isLogHeader(line) {
 			logChapterIndex = i
 			break
 		}
@@ -110,25 +400,24 @@ func AppendToLog(cfg *config.Config, filePath, entry string, timestamp time.Time
 	for insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) == "" {
 		insertIndex++
 	}
-	// ... then find where the last already existing entry lies
-	for insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) != "" {
+	hasEntries := insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) != ""
+	// ... then find where the last already existing entry lies, unless the entry goes on top
+	for !opts.Prepend && insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) != "" {
 		insertIndex++
 	}
 
-	// Render the log entry using the configurable template
-	data := template.TemplateData{
-		Time:  timestamp,
-		Entry: entry,
-	}
-	newEntryLine, err := template.Render(cfg.LogEntryTemplate, data)
-	if err != nil {
-		return fmt.Errorf("failed to render log entry template: %w", err)
+	// Insert the new entry, separated from the existing ones
+	newEntryLines := []string{newEntryLine}
+	if cfg.LogEntrySeparator != "" && hasEntries {
+		if opts.Prepend {
+			newEntryLines = append(newEntryLines, cfg.LogEntrySeparator)
+		} else {
+			newEntryLines = append([]string{cfg.LogEntrySeparator}, newEntryLines...)
+		}
 	}
-
-	// Insert the new entry
-	newLines := make([]string, 0, len(lines)+1)
+	newLines := make([]string, 0, len(lines)+len(newEntryLines))
 	newLines = append(newLines, lines[:insertIndex]...)
-	newLines = append(newLines, newEntryLine)
+	newLines = append(newLines, newEntryLines...)
 	newLines = append(newLines, lines[insertIndex:]...)
 
 	modifiedContent := strings.Join(newLines, "\n")
@@ -138,56 +427,51 @@ func AppendToLog(cfg *config.Config, filePath, entry string, timestamp time.Time
 		modifiedContent += "\n"
 	}
 
-	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
+	err = fileutil.AtomicWrite(filePath, []byte(modifiedContent), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write to journal file: %w", err)
 	}
 
-	fmt.Println(color.GreenString("Log entry appended to %s", filePath))
+	if opts.Prepend {
+		fmt.Println(theme.Success("Log entry prepended to %s", filePath))
+	} else {
+		fmt.Println(theme.Success("Log entry appended to %s", filePath))
+	}
 	return nil
 }
 
 // GenerateSummaryIfMissing reads a journal file, and if no summary exists, generates one using the provided AI summarizer.
 // Summary is inserted right after the first header line.
-func GenerateSummaryIfMissing(filePath string, cfg *config.Config, summarizer ai.AISummarizer, aiPrompt string, reader io.Reader) error {
-	content, err := os.ReadFile(filePath)
+// The file is locked with filelock while the summary is inserted, not while it is generated:
+// the entries logged in the meantime are kept, and the summary is dropped if another one was written.
+// The generation is stopped when ctx is done, returning its error.
+func GenerateSummaryIfMissing(ctx context.Context, filePath string, cfg *config.Config, summarizer ai.AISummarizer, aiPrompt string, reader io.Reader) error {
+	return writeSummary(ctx, filePath, cfg, summarizer, aiPrompt, reader, false)
+}
+
+// writeSummary is GenerateSummaryIfMissing; with replace, an existing summary is left out of the text to summarize
+// and replaced by the new one. Nothing is written if no new summary is generated.
+func writeSummary(ctx context.Context, filePath string, cfg *config.Config, summarizer ai.AISummarizer, aiPrompt string, reader io.Reader, replace bool) error {
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read journal file: %w", err)
 	}
 
 	lines := strings.Split(string(content), "\n")
-
-	// Check if summary already exists:
-	// Line 0: # Title
-	// Line 1: might be HTML comment (<!-- ... -->)
-	// Summary exists if there's non-empty, non-comment, non-header content after title
-
-	isSummaryMissing := true
-	for i := 1; i < len(lines); i++ {
-		trimmed := strings.TrimSpace(lines[i])
-		if trimmed == "" {
-			continue // Skip empty lines
-		}
-		if strings.HasPrefix(trimmed, "<!--") {
-			continue // Skip HTML comments
-		}
-		if strings.HasPrefix(trimmed, "#") {
-			break // Hit a section header, no summary found
-		}
-		// Found non-empty, non-comment, non-header content = summary exists
-		isSummaryMissing = false
-		break
-	}
-
-	if !isSummaryMissing {
+	if replace {
+		lines = withoutSummary(lines)
+	} else if !isSummaryMissing(lines) {
 		return nil // Summary already exists
 	}
 
 	var finalSummary string
 
 	if summarizer != nil {
-		// Extract content to summarize (skip title, exclude "One-line note" section)
-		contentToSummarize := strings.Join(lines[1:], "\n")
+		// Extract content to summarize (skip front matter and title, exclude "One-line note" section)
+		contentToSummarize := ""
+		if title := titleIndex(lines); title < len(lines) {
+			contentToSummarize = strings.Join(lines[title+1:], "\n")
+		}
 		oneLineNoteSection := "## One-line note"
 		idx := strings.Index(contentToSummarize, oneLineNoteSection)
 		if idx != -1 {
@@ -196,7 +480,7 @@ func GenerateSummaryIfMissing(filePath string, cfg *config.Config, summarizer ai
 		contentToSummarize = strings.TrimSpace(contentToSummarize)
 
 		// Generate summary using AI agent
-		generatedSummary, err := summarizer.GenerateSummary(contentToSummarize, aiPrompt)
+		generatedSummary, err := summarizer.GenerateSummary(ctx, contentToSummarize, aiPrompt)
 		if err != nil {
 			return fmt.Errorf("failed to generate summary with AI: %w", err)
 		}
@@ -212,25 +496,49 @@ func GenerateSummaryIfMissing(filePath string, cfg *config.Config, summarizer ai
 		}
 
 		if strings.TrimSpace(finalSummary) == "" {
-			fmt.Println(color.YellowString("Manual summary skipped."))
+			fmt.Println(theme.Warning("Manual summary skipped."))
 			return nil // User skipped manual summary
 		}
 	}
 
-	// Insert summary after title and HTML comment (if present)
+	// Other logbook processes may have written the file while the summary was generated
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	content, err = fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file: %w", err)
+	}
+	lines = strings.Split(string(content), "\n")
+	if replace {
+		lines = withoutSummary(lines)
+	} else if !isSummaryMissing(lines) {
+		return nil // Summary written in the meantime
+	}
+
+	// Insert summary after front matter, title and HTML comment (if present)
 	var newContentBuilder strings.Builder
-	newContentBuilder.WriteString(lines[0]) // Title
-	newContentBuilder.WriteString("\n")
+	startIdx := titleIndex(lines) + 1
+	for _, line := range lines[:min(startIdx, len(lines))] { // Front matter and title
+		newContentBuilder.WriteString(line)
+		newContentBuilder.WriteString("\n")
+	}
 
-	// Check if line 1 is HTML comment, if so include it
-	startIdx := 1
-	if len(lines) > 1 && strings.HasPrefix(strings.TrimSpace(lines[1]), "<!--") {
-		newContentBuilder.WriteString(lines[1])
+	// Check if the line after the title is HTML comment, if so include it
+	if len(lines) > startIdx && strings.HasPrefix(strings.TrimSpace(lines[startIdx]), "<!--") {
+		newContentBuilder.WriteString(lines[startIdx])
 		newContentBuilder.WriteString("\n")
-		startIdx = 2
+		startIdx++
 	}
 
-	newContentBuilder.WriteString(strings.TrimSpace(finalSummary))
+	summaryText, err := summaryForFile(cfg, strings.TrimSpace(finalSummary))
+	if err != nil {
+		return err
+	}
+	newContentBuilder.WriteString(summaryText)
 	newContentBuilder.WriteString("\n\n")
 
 	// Skip any empty lines after comment
@@ -244,7 +552,7 @@ func GenerateSummaryIfMissing(filePath string, cfg *config.Config, summarizer ai
 
 	modifiedContent := newContentBuilder.String()
 
-	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
+	err = fileutil.AtomicWrite(filePath, []byte(modifiedContent), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write generated summary to file: %w", err)
 	}
@@ -252,17 +560,105 @@ func GenerateSummaryIfMissing(filePath string, cfg *config.Config, summarizer ai
 	return nil
 }
 
-// ListJournalFilesByPeriod returns a list of absolute paths to journal files within the specified date range.
+// isSummaryMissing reports whether the lines of a journal file have no summary:
+// the title follows the front matter, if any, the next line might be an HTML comment (<!-- ... -->),
+// and the summary is the first non-empty, non-comment content after the title, before any section header.
+func isSummaryMissing(lines []string) bool {
+	for i := titleIndex(lines) + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue // Skip empty lines
+		}
+		if strings.HasPrefix(trimmed, "<!--") {
+			continue // Skip HTML comments
+		}
+		// A section header means no summary, any other content is the summary
+		return strings.HasPrefix(trimmed, "#")
+	}
+	return true
+}
+
+// summaryLineRange returns the range [start, end) of the lines of the summary of a journal file,
+// from its first line to the next chapter, without the blank lines before the chapter.
+// start is -1 if the file has no summary.
+func summaryLineRange(lines []string) (start, end int) {
+	start, end = -1, len(lines)
+	for i := titleIndex(lines) + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if isLogHeader(trimmed) || strings.HasPrefix(trimmed, "# One-line note") {
+			end = i
+			break
+		}
+		if IsSectionHeader(trimmed) {
+			if start != -1 {
+				end = i
+				break
+			}
+			continue // Skip any sub-headings before the summary
+		}
+		if start == -1 && (trimmed == "" || strings.HasPrefix(trimmed, "<!--")) {
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	if start == -1 {
+		return -1, -1
+	}
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return start, end
+}
+
+// withoutSummary returns the lines of a journal file without the lines of its summary.
+func withoutSummary(lines []string) []string {
+	start, end := summaryLineRange(lines)
+	if start == -1 {
+		return lines
+	}
+	return append(lines[:start:start], lines[end:]...)
+}
+
+// RegenerateSummary writes the summary of a journal file as GenerateSummaryIfMissing does. With force, an existing
+// summary is replaced, for example after new log entries made it stale: the new summary is generated without it,
+// then swapped in under the file lock, so the entries logged in the meantime are kept. If no new summary is
+// generated, the previous one is kept. Without a summarizer the summary is read from reader.
+func RegenerateSummary(ctx context.Context, cfg *config.Config, filePath, prompt string, summarizer ai.AISummarizer, reader io.Reader, force bool) error {
+	if summarizer == nil && reader == nil {
+		return fmt.Errorf("AI summarizer is not configured")
+	}
+	return writeSummary(ctx, filePath, cfg, summarizer, prompt, reader, force)
+}
+
+// ListJournalFilesByPeriod returns a list of absolute paths to journal files within the specified date range,
+// sorted by date. When the dates can be parsed back from the file names, the journal directory is listed once
+// rather than checking the file of each day of the range.
 func ListJournalFilesByPeriod(cfg *config.Config, startDate, endDate time.Time) ([]string, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	journalDir := cfg.JournalDir
-	if !filepath.IsAbs(journalDir) {
-		return nil, fmt.Errorf("JournalDir must be an absolute path: %s", journalDir)
+	if !filepath.IsAbs(cfg.JournalDir) {
+		return nil, fmt.Errorf("JournalDir must be an absolute path: %s", cfg.JournalDir)
+	}
+
+	// The dates are calendar days, the same in every timezone
+	if cfg.Timezone != "" {
+		location := cfg.Location()
+		startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, location)
+		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, location)
 	}
 
+	if files, ok, err := listJournalFilesByGlob(cfg, startDate, endDate); ok {
+		return files, err
+	}
+	return listJournalFilesByDay(cfg, startDate, endDate)
+}
+
+// listJournalFilesByDay is ListJournalFilesByPeriod looking for the file of each day of the period.
+func listJournalFilesByDay(cfg *config.Config, startDate, endDate time.Time) ([]string, error) {
 	var files []string
 
 	// Iterate through the date range
@@ -273,7 +669,7 @@ func ListJournalFilesByPeriod(cfg *config.Config, startDate, endDate time.Time)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render daily file name for date %s: %w", d.Format("2006-01-02"), err)
 		}
-		filePath := filepath.Join(journalDir, fileName)
+		filePath := filepath.Join(cfg.JournalDir, fileName)
 
 		// Check if the file exists
 		if _, err := os.Stat(filePath); err == nil {
@@ -285,34 +681,222 @@ func ListJournalFilesByPeriod(cfg *config.Config, startDate, endDate time.Time)
 	return files, nil
 }
 
+// listJournalFilesByGlob is ListJournalFilesByPeriod listing the journal directory once, and parsing the date
+// of the file names with the layout of cfg.DailyFileName. ok is false if the file names are not in a single
+// directory or their date cannot be parsed back, e.g. with a weekday only, so that each day must be checked.
+func listJournalFilesByGlob(cfg *config.Config, startDate, endDate time.Time) (files []string, ok bool, err error) {
+	layout, ok := dailyFileNameLayout(cfg)
+	if !ok {
+		return nil, false, nil
+	}
+
+	// The period has the days of the iterations of listJournalFilesByDay
+	first := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+	if clockSeconds(startDate) > clockSeconds(endDate) {
+		last = last.AddDate(0, 0, -1)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(cfg.JournalDir, "*"+filepath.Ext(layout)))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to list journal files: %w", err)
+	}
+	var dated []dailyFile
+	for _, match := range matches {
+		date, err := time.Parse(layout, filepath.Base(match))
+		if err != nil || date.Before(first) || date.After(last) {
+			continue // Not a daily file, e.g. a review, or out of the period
+		}
+		if date.Format(layout) != filepath.Base(match) {
+			continue // Parsed, but not the name of the daily file of that date, e.g. a different case
+		}
+		dated = append(dated, dailyFile{path: match, date: date})
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].date.Before(dated[j].date)
+	})
+	for _, file := range dated {
+		files = append(files, file.path)
+	}
+	return files, true, nil
+}
+
+// dailyFileNameLayout returns cfg.DailyFileName as a Go date layout, rendering it with the reference date.
+// ok is false if the layout is in a subdirectory or does not give back the date of the file names.
+func dailyFileNameLayout(cfg *config.Config) (string, bool) {
+	reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	layout, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: reference})
+	if err != nil || strings.ContainsAny(layout, `/\`) {
+		return "", false
+	}
+
+	// A sentinel date with distinct fields must survive a round trip through the layout
+	sentinel := time.Date(2023, time.November, 28, 0, 0, 0, 0, time.UTC)
+	fileName, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: sentinel})
+	if err != nil || fileName != sentinel.Format(layout) {
+		return "", false
+	}
+	parsed, err := time.Parse(layout, fileName)
+	if err != nil || !parsed.Equal(sentinel) {
+		return "", false
+	}
+	return layout, true
+}
+
+// clockSeconds returns the time of day of t, in seconds.
+func clockSeconds(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+// ErrWriteCompleted is returned by RecoverPartialWrite and DiscardPartialWrite when the temporary file is gone:
+// it was written by a write still running, that has completed since.
+var ErrWriteCompleted = errors.New("the write has completed")
+
+// FindPartialWrites returns the temporary files left in journalDir by interrupted writes of journal files.
+// The files of journal files locked with filelock are skipped, they are being written by another process.
+func FindPartialWrites(journalDir string) ([]string, error) {
+	tmpFiles, err := filepath.Glob(filepath.Join(journalDir, "*.md"+fileutil.TmpSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for partial writes in %s: %w", journalDir, err)
+	}
+	var partialWrites []string
+	for _, tmpPath := range tmpFiles {
+		lock, err := filelock.TryLock(strings.TrimSuffix(tmpPath, fileutil.TmpSuffix), 0)
+		if errors.Is(err, filelock.ErrTimeout) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		lock.Unlock()
+		partialWrites = append(partialWrites, tmpPath)
+	}
+	return partialWrites, nil
+}
+
+// RecoverPartialWrite replaces the original journal file with the content of the temporary file left by an interrupted write.
+// The journal file is locked with filelock; ErrWriteCompleted is returned if the temporary file no longer exists.
+func RecoverPartialWrite(tmpPath string) error {
+	return withPartialWrite(tmpPath, func(originalPath string) error {
+		if err := os.Rename(tmpPath, originalPath); err != nil {
+			return fmt.Errorf("failed to recover %s: %w", originalPath, err)
+		}
+		return nil
+	})
+}
+
+// DiscardPartialWrite deletes the temporary file left by an interrupted write, keeping the original journal file.
+// The journal file is locked with filelock; ErrWriteCompleted is returned if the temporary file no longer exists.
+func DiscardPartialWrite(tmpPath string) error {
+	return withPartialWrite(tmpPath, func(string) error {
+		if err := os.Remove(tmpPath); err != nil {
+			return fmt.Errorf("failed to discard %s: %w", tmpPath, err)
+		}
+		return nil
+	})
+}
+
+// withPartialWrite runs fn with the path of the journal file of tmpPath, locked, if tmpPath still exists.
+func withPartialWrite(tmpPath string, fn func(originalPath string) error) error {
+	if !strings.HasSuffix(tmpPath, fileutil.TmpSuffix) {
+		return fmt.Errorf("not a partial write file: %s", tmpPath)
+	}
+	originalPath := strings.TrimSuffix(tmpPath, fileutil.TmpSuffix)
+	lock, err := filelock.Lock(originalPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if _, err := os.Stat(tmpPath); os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", tmpPath, ErrWriteCompleted)
+	} else if err != nil {
+		return fmt.Errorf("failed to check %s: %w", tmpPath, err)
+	}
+	return fn(originalPath)
+}
+
+// CountWords returns the number of words written in a journal file, ignoring headers, HTML comments
+// and the cfg.LogEntrySeparator lines.
+func CountWords(cfg *config.Config, filePath string) (int, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	count := 0
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if IsSectionHeader(trimmed) || strings.HasPrefix(trimmed, "<!--") || isLogEntrySeparator(cfg, trimmed) {
+			continue
+		}
+		count += len(strings.Fields(trimmed))
+	}
+	return count, nil
+}
+
+// CountLogWords returns the number of whitespace-separated words of the LOG chapter of a journal file,
+// timestamps included. A file without LOG chapter, or with an empty one, has no words.
+func CountLogWords(filePath string) (int, error) {
+	log, err := ExtractLogSection(filePath)
+	if err != nil {
+		return 0, err
+	}
+	return len(strings.Fields(log)), nil
+}
+
+// ExtractSummaryResult holds the summary of a journal file.
+type ExtractSummaryResult struct {
+	Short          string // First paragraph, used for one-line notes
+	Full           string // All paragraphs, separated by a blank line
+	ParagraphCount int
+	Encrypted      bool // The summary is encrypted, Short and Full are EncryptedSummary, see DecryptSummary
+}
+
 // ExtractSummary reads a journal file and returns its first paragraph as the summary.
 func ExtractSummary(filePath string) (string, error) {
-	content, err := os.ReadFile(filePath)
+	result, err := ExtractSummaryFull(filePath)
+	if err != nil {
+		return "", err
+	}
+	return result.Short, nil
+}
+
+// ExtractSummaryFull reads a journal file and returns its summary, that is the paragraphs after the title
+// and before the next chapter, skipping the front matter. The lines of each paragraph are joined with a space.
+func ExtractSummaryFull(filePath string) (ExtractSummaryResult, error) {
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil // File does not exist, return empty summary and no error
+			return ExtractSummaryResult{}, nil // File does not exist, return empty summary and no error
 		}
-		return "", fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+		return ExtractSummaryResult{}, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(NormaliseCRLF(string(content)), "\n")
+	if _, ok := summaryCipherText(lines); ok {
+		return ExtractSummaryResult{Short: EncryptedSummary, Full: EncryptedSummary, ParagraphCount: 1, Encrypted: true}, nil
+	}
 
-	// The first paragraph after the title and before the "LOG" chapter is considered the summary.
-	var summaryLines []string
-	readingSummary := false
+	var paragraphs []string
+	var paragraphLines []string
+	endParagraph := func() {
+		if len(paragraphLines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraphLines, " "))
+			paragraphLines = nil
+		}
+	}
 
-	for i := 1; i < len(lines); i++ {
+	for i := titleIndex(lines) + 1; i < len(lines); i++ {
 		trimmedLine := strings.TrimSpace(lines[i])
 
-		if strings.HasPrefix(trimmedLine, "# LOG") || strings.HasPrefix(trimmedLine, "# One-line note") {
+		if isLogHeader(trimmedLine) || strings.HasPrefix(trimmedLine, "# One-line note") {
 			break // Reached the LOG or One-line note section, stop reading summary
 		}
 
 		if trimmedLine == "" {
-			if readingSummary { // If we were reading summary and hit an empty line, the paragraph ends
-				break
-			}
-			continue // Skip empty lines before the summary starts
+			endParagraph()
+			continue
 		}
 
 		// Skip HTML comments
@@ -320,18 +904,268 @@ func ExtractSummary(filePath string) (string, error) {
 			continue
 		}
 
-		if !readingSummary && strings.HasPrefix(trimmedLine, "#") {
+		if IsSectionHeader(trimmedLine) {
+			if len(paragraphs) > 0 || len(paragraphLines) > 0 {
+				break // The summary ends at the next chapter
+			}
 			continue // Skip any sub-headings before the actual summary paragraph
 		}
 
-		readingSummary = true
-		summaryLines = append(summaryLines, trimmedLine)
+		paragraphLines = append(paragraphLines, trimmedLine)
+	}
+	endParagraph()
+
+	if len(paragraphs) == 0 {
+		return ExtractSummaryResult{}, nil // No summary found
+	}
+	return ExtractSummaryResult{
+		Short:          paragraphs[0],
+		Full:           strings.Join(paragraphs, "\n\n"),
+		ParagraphCount: len(paragraphs),
+	}, nil
+}
+
+// NormaliseCRLF returns content with \n line endings only, so that files edited on Windows are parsed as any other.
+func NormaliseCRLF(content string) string {
+	return fileutil.NormaliseCRLF(content)
+}
+
+// TrimAllLines removes the trailing spaces and tabs of every line, converting line endings to \n.
+func TrimAllLines(content string) string {
+	lines := strings.Split(NormaliseCRLF(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RepairFile cleans up a journal file edited by hand or pasted from a terminal:
+// line endings are converted to \n and trailing whitespace is removed from every line.
+// The file is only written if something changed, repaired reports whether it was.
+func RepairFile(filePath string) (repaired bool, err error) {
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	trimmed := TrimAllLines(string(content))
+	if trimmed == string(content) {
+		return false, nil
+	}
+	if err := fileutil.AtomicWrite(filePath, []byte(trimmed), 0644); err != nil {
+		return false, fmt.Errorf("failed to write to journal file: %w", err)
+	}
+	return true, nil
+}
+
+// LogEntry is a single timestamped entry of the "LOG" chapter.
+// Time only carries the components rendered by LogEntryTemplate (by default hours and minutes),
+// use On to place it on the day of the journal file.
+type LogEntry struct {
+	Time time.Time
+	Text string
+}
+
+// On returns the entry timestamp on the given day.
+func (e LogEntry) On(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), e.Time.Hour(), e.Time.Minute(), e.Time.Second(), 0, date.Location())
+}
+
+// DailyFilePath returns the path of the daily journal file for the given date, whether it exists or not.
+func DailyFilePath(cfg *config.Config, date time.Time) (string, error) {
+	fileName, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: date})
+	if err != nil {
+		return "", fmt.Errorf("failed to render daily file name for date %s: %w", date.Format("2006-01-02"), err)
+	}
+	return filepath.Join(cfg.JournalDir, fileName), nil
+}
+
+// DateFromFilePath returns the date of a daily journal file, parsing its name with the layout of cfg.DailyFileName.
+func DateFromFilePath(cfg *config.Config, filePath string) (time.Time, error) {
+	reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	layout, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: reference})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to render daily file name layout: %w", err)
+	}
+	date, err := time.Parse(layout, filepath.Base(filePath))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("file %s does not match the daily file name: %w", filePath, err)
+	}
+	return date, nil
+}
+
+// ExtractLogEntries reads a journal file and returns the entries of its "LOG" chapter in file order,
+// that is newest first for files written with LogEntryOrder "prepend".
+// The timestamp of each entry is parsed back using the layout of cfg.LogEntryTemplate.
+// Lines that do not start with a timestamp are considered a continuation of the previous entry.
+func ExtractLogEntries(cfg *config.Config, filePath string) ([]LogEntry, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	layouts, err := logEntryTimeLayouts(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LogEntry
+	inLogChapter := false
+	for _, line := range strings.Split(NormaliseCRLF(string(content)), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isLogHeader(trimmed) {
+			inLogChapter = true
+			continue
+		}
+		if !inLogChapter {
+			continue
+		}
+		if IsSectionHeader(trimmed) {
+			break // Reached the next chapter
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "<!--") || isLogEntrySeparator(cfg, trimmed) {
+			continue
+		}
+
+		if timestamp, text, ok := parseLogEntryLineWithLayouts(trimmed, layouts); ok {
+			entries = append(entries, LogEntry{Time: timestamp, Text: text})
+			continue
+		}
+		if len(entries) > 0 {
+			entries[len(entries)-1].Text += "\n" + trimmed
+			continue
+		}
+		entries = append(entries, LogEntry{Text: trimmed})
+	}
+
+	return entries, nil
+}
+
+// ExtractLogSection returns the content of the "LOG" chapter of a journal file as written, that is the lines
+// between the "# LOG" (or "## LOG") header and the next header, without the surrounding empty lines.
+// Returns an empty string if the file has no LOG chapter.
+func ExtractLogSection(filePath string) (string, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	var logLines []string
+	inLogChapter := false
+	for _, line := range strings.Split(NormaliseCRLF(string(content)), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inLogChapter {
+			inLogChapter = isLogHeader(trimmed)
+			continue
+		}
+		if IsSectionHeader(trimmed) {
+			break // Reached the next chapter
+		}
+		logLines = append(logLines, line)
+	}
+	return strings.Trim(strings.Join(logLines, "\n"), "\n"), nil
+}
+
+// isLogHeader reports whether a line is the header of the "LOG" chapter, written "# LOG" or "## LOG".
+// The first of them in a file is its LOG chapter.
+func isLogHeader(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# LOG") || strings.HasPrefix(trimmed, "## LOG")
+}
+
+// IsSectionHeader reports whether a trimmed line is a Markdown header, e.g. "# LOG", unlike a "#tag".
+func IsSectionHeader(trimmedLine string) bool {
+	header := strings.TrimLeft(trimmedLine, "#")
+	return header != trimmedLine && (header == "" || strings.HasPrefix(header, " "))
+}
+
+// isLogEntrySeparator reports whether a trimmed line is the configured separator between log entries.
+func isLogEntrySeparator(cfg *config.Config, trimmedLine string) bool {
+	return cfg.LogEntrySeparator != "" && trimmedLine == strings.TrimSpace(cfg.LogEntrySeparator)
+}
+
+// logEntryTemplate returns the template of a log entry line, with the date prefix when requested and configured.
+func logEntryTemplate(cfg *config.Config, prependDate bool) string {
+	if prependDate && cfg.EntryDatePrefix != "" {
+		return "{{.Date | formatDate .DatePrefix}}" + cfg.LogEntryTemplate
 	}
+	return cfg.LogEntryTemplate
+}
 
-	if len(summaryLines) > 0 {
-		return strings.Join(summaryLines, " "), nil
+// logEntryTimeLayout returns the time layout that the log entry template renders before the entry text.
+// It renders the template with Go's reference time, so that the timestamp comes out as its own layout.
+func logEntryTimeLayout(cfg *config.Config, entryTemplate string) (string, error) {
+	const entryMarker = "\x00"
+	reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	data := template.TemplateData{Date: reference, Time: reference, Entry: entryMarker, DatePrefix: cfg.EntryDatePrefix}
+	rendered, err := template.Render(entryTemplate, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render log entry template: %w", err)
 	}
+	idx := strings.Index(rendered, entryMarker)
+	if idx == -1 {
+		return "", nil
+	}
+	return strings.TrimSpace(rendered[:idx]), nil
+}
 
-	return "", nil // No summary found
+// logEntryTimeLayouts returns the time layouts of the log entry lines.
+// Entries written with a date prefix come first, as their layout is longer.
+func logEntryTimeLayouts(cfg *config.Config) ([]string, error) {
+	var layouts []string
+	for _, prependDate := range []bool{true, false} {
+		if prependDate && cfg.EntryDatePrefix == "" {
+			continue
+		}
+		layout, err := logEntryTimeLayout(cfg, logEntryTemplate(cfg, prependDate))
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, layout)
+	}
+	return layouts, nil
 }
 
+// parseLogEntryLineWithLayouts tries parseLogEntryLine with each layout in turn.
+func parseLogEntryLineWithLayouts(line string, layouts []string) (time.Time, string, bool) {
+	for _, layout := range layouts {
+		if timestamp, text, ok := parseLogEntryLine(line, layout); ok {
+			return timestamp, text, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseLogEntryLine splits a LOG line into its timestamp and text using the given layout.
+func parseLogEntryLine(line, layout string) (time.Time, string, bool) {
+	if layout == "" {
+		return time.Time{}, "", false
+	}
+	layoutFields := len(strings.Fields(layout))
+	fields := strings.Fields(line)
+	if len(fields) < layoutFields {
+		return time.Time{}, "", false
+	}
+	timestamp, err := time.Parse(layout, strings.Join(fields[:layoutFields], " "))
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	// Skip the timestamp fields while preserving the spacing of the entry text
+	text := line
+	for i := 0; i < layoutFields; i++ {
+		text = strings.TrimLeft(text, " \t")
+		if end := strings.IndexAny(text, " \t"); end != -1 {
+			text = text[end:]
+		} else {
+			text = ""
+		}
+	}
+	return timestamp, strings.TrimSpace(text), true
+}
@@ -1,16 +1,20 @@
 package journal
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/clobrano/LogBook/pkg/ai"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
 	"github.com/clobrano/LogBook/pkg/oneline"
 	"github.com/clobrano/LogBook/pkg/template"
 
@@ -77,8 +81,9 @@ func TestCreateDailyJournalFile(t *testing.T) {
 	// Test case 6: Custom file naming convention
 	cfg.DailyFileName = `{{.Date | formatDate "02"}}-{{.Date | formatDate "01"}}-{{.Date | formatDate "2006"}}.log`
 	date = time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC)
-		filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
-	    	assert.NoError(t, err)
+	expectedFilePath = filepath.Join(tmpDir, "25-12-2025.log")
+	filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
 	assert.Equal(t, expectedFilePath, filePath)
 	assert.FileExists(t, expectedFilePath)
 
@@ -86,9 +91,9 @@ func TestCreateDailyJournalFile(t *testing.T) {
 	cfg = config.DefaultConfig()
 	cfg.JournalDir = tmpDir
 	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}} - My Daily Log\n\n[SUMMARY_PLACEHOLDER]\n\n## LOG\n"
-	    date = time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC)
-	    		filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
-	    	    	assert.NoError(t, err)
+	date = time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC)
+	filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
 	assert.FileExists(t, filePath)
 
 	content, err := os.ReadFile(filePath)
@@ -110,6 +115,36 @@ func TestCreateDailyJournalFile(t *testing.T) {
 	assert.True(t, strings.HasPrefix(string(content), "This is the summary.\n\n"))
 }
 
+func TestCreateDailyJournalFileFromTemplateFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	templateFile := filepath.Join(t.TempDir(), "daily.md")
+	templateContent := "# {{.Date | formatDate \"Jan 02 2006 Monday\"}}\n<!-- summary -->\n\n# One-line note\n\n\n# LOG\n\n"
+	err := os.WriteFile(templateFile, []byte(templateContent), 0644)
+	assert.NoError(t, err)
+
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# Should not be used\n\n# LOG\n"
+	cfg.DailyTemplateFile = templateFile
+
+	// Test case 1: The template file content is rendered instead of DailyTemplate
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 18 2025 Thursday\n<!-- summary -->\n\n# One-line note\n\n\n# LOG\n\n", string(content))
+
+	// Test case 2: A missing template file is reported by the config validation
+	cfg.DailyTemplateFile = filepath.Join(t.TempDir(), "missing.md")
+	date = time.Date(2025, time.September, 19, 0, 0, 0, 0, time.UTC)
+	_, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid configuration: DailyTemplateFile does not exist")
+}
+
 func TestAppendToLog(t *testing.T) {
 	// Setup a temporary journal directory and file
 	tmpDir := t.TempDir()
@@ -154,6 +189,28 @@ func TestAppendToLog(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "LOG chapter not found in file")
 
+	// Test case 4: Append to a file using a single hash "# LOG" chapter
+	singleHashFilePath := filepath.Join(tmpDir, "single_hash.md")
+	err = os.WriteFile(singleHashFilePath, []byte("# Title\n\n# LOG\n"), 0644)
+	assert.NoError(t, err)
+
+	err = AppendToLog(cfg, singleHashFilePath, "Single hash entry.", appendDate)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(singleHashFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n\n# LOG\n\n14:30 Single hash entry.\n", string(content))
+
+	// Test case 5: The first of "## LOG" and "# LOG" is the LOG chapter
+	bothFilePath := filepath.Join(tmpDir, "both_markers.md")
+	err = os.WriteFile(bothFilePath, []byte("# Title\n\n## LOG\n09:00 Existing entry.\n\n# LOG archive\n"), 0644)
+	assert.NoError(t, err)
+
+	err = AppendToLog(cfg, bothFilePath, "First marker entry.", appendDate)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(bothFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n\n## LOG\n09:00 Existing entry.\n14:30 First marker entry.\n\n# LOG archive\n", string(content))
+
 	// Test GenerateSummaryIfMissing
 	// Setup a temporary journal directory and file for summary tests
 	summaryTmpDir := t.TempDir()
@@ -171,7 +228,7 @@ func TestAppendToLog(t *testing.T) {
 	aiCfg := config.DefaultConfig()
 	aiCfg.AISummarizer = mockAI // Set the AI summarizer in the config
 
-	err = GenerateSummaryIfMissing(summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	content, err = os.ReadFile(summaryFilePath)
@@ -185,7 +242,7 @@ func TestAppendToLog(t *testing.T) {
 	summaryFilePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
 	assert.NoError(t, err)
 
-	err = GenerateSummaryIfMissing(summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	content, err = os.ReadFile(summaryFilePath)
@@ -195,6 +252,7 @@ func TestAppendToLog(t *testing.T) {
 
 	// Test case 3: AI summarizer returns an error
 	cfg.DailyTemplate = "# Daily Log\n\n## LOG\n"
+	date = time.Date(2025, time.November, 12, 0, 0, 0, 0, time.UTC)
 	summaryFilePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
 	assert.NoError(t, err)
 
@@ -202,7 +260,7 @@ func TestAppendToLog(t *testing.T) {
 	aiCfgWithError := config.DefaultConfig()
 	aiCfgWithError.AISummarizer = mockAIWithError
 
-	err = GenerateSummaryIfMissing(summaryFilePath, aiCfgWithError, mockAIWithError, aiPrompt, strings.NewReader(""))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, aiCfgWithError, mockAIWithError, aiPrompt, strings.NewReader(""))
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to generate summary with AI: AI error during summary generation")
 
@@ -218,7 +276,7 @@ func TestAppendToLog(t *testing.T) {
 	noAICfg := config.DefaultConfig()
 	noAICfg.AISummarizer = nil
 
-	err = GenerateSummaryIfMissing(summaryFilePath, noAICfg, nil, aiPrompt, strings.NewReader(manualSummaryInput))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, noAICfg, nil, aiPrompt, strings.NewReader(manualSummaryInput))
 	assert.NoError(t, err)
 
 	content, err = os.ReadFile(summaryFilePath)
@@ -228,10 +286,11 @@ func TestAppendToLog(t *testing.T) {
 
 	// Test case 5: No AI agent configured, user skips manual summary
 	cfg.DailyTemplate = "# Daily Log\n\n## LOG\n"
-	    	summaryFilePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
-	    	assert.NoError(t, err)
+	date = time.Date(2025, time.November, 14, 0, 0, 0, 0, time.UTC)
+	summaryFilePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
 	// Empty input to simulate skipping
-	err = GenerateSummaryIfMissing(summaryFilePath, noAICfg, nil, aiPrompt, strings.NewReader("\n"))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, noAICfg, nil, aiPrompt, strings.NewReader("\n"))
 	assert.NoError(t, err)
 
 	content, err = os.ReadFile(summaryFilePath)
@@ -247,7 +306,7 @@ func TestAppendToLog(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Simulate an error during read
-	err = GenerateSummaryIfMissing(summaryFilePath, noAICfg, nil, aiPrompt, &ErrorReader{Err: errors.New("read error")})
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, noAICfg, nil, aiPrompt, &ErrorReader{Err: errors.New("read error")})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read manual summary: read error")
 
@@ -261,7 +320,7 @@ func TestAppendToLog(t *testing.T) {
 	aiCfg = config.DefaultConfig()
 	aiCfg.AISummarizer = mockAI
 
-	err = GenerateSummaryIfMissing(summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
+	err = GenerateSummaryIfMissing(context.Background(), summaryFilePath, aiCfg, mockAI, aiPrompt, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	var contentBytesForOneLineNoteTest []byte
@@ -442,6 +501,15 @@ func TestExtractSummary(t *testing.T) {
 	summary, err = ExtractSummary(filePath6)
 	assert.NoError(t, err)
 	assert.Equal(t, "Summary after title.", summary)
+
+	// Test case 7: File with a single hash "# LOG" chapter and no summary
+	filePath7 := filepath.Join(tmpDir, "file7.md")
+	err = os.WriteFile(filePath7, []byte("# Title\n\n# LOG\nEntry 1"), 0644)
+	assert.NoError(t, err)
+
+	summary, err = ExtractSummary(filePath7)
+	assert.NoError(t, err)
+	assert.Empty(t, summary)
 }
 
 func TestEmbedOneLineNotes(t *testing.T) {
@@ -463,15 +531,15 @@ func TestEmbedOneLineNotes(t *testing.T) {
 	os.WriteFile(filePath, []byte(initialContent), 0644)
 
 	// Sample summaries to embed
-	summaries := map[string]string{
-		"1_week_ago":   "Summary from 1 week ago.",
-		"1_month_ago":  "Summary from 1 month ago.",
-		"6_months_ago": "Summary from 6 months ago.",
-		"1_year_ago":   "Summary from 1 year ago.",
-		"2_years_ago":  "Summary from 2 years ago.",
+	summaries := map[time.Time]string{
+		date.AddDate(0, 0, -7): "Summary from 1 week ago.",
+		date.AddDate(0, -1, 0): "Summary from 1 month ago.",
+		date.AddDate(0, -6, 0): "Summary from 6 months ago.",
+		date.AddDate(-1, 0, 0): "Summary from 1 year ago.",
+		date.AddDate(-2, 0, 0): "Summary from 2 years ago.",
 	}
 
-	err := oneline.EmbedOneLineNotes(filePath, summaries)
+	err := oneline.EmbedOneLineNotes(filePath, date, summaries)
 	assert.NoError(t, err)
 
 	// Read the updated file content
@@ -479,15 +547,1169 @@ func TestEmbedOneLineNotes(t *testing.T) {
 	assert.NoError(t, err)
 	updatedContent := string(updatedContentBytes)
 
-	// Assert that each summary line is present in the updated content
-	assert.Contains(t, updatedContent, "- 1 week ago: Summary from 1 week ago.\n")
-	assert.Contains(t, updatedContent, "- 1 month ago: Summary from 1 month ago.\n")
-	assert.Contains(t, updatedContent, "- 6 months ago: Summary from 6 months ago.\n")
-	assert.Contains(t, updatedContent, "- 1 year ago: Summary from 1 year ago.\n")
-	assert.Contains(t, updatedContent, "- 2 years ago: Summary from 2 years ago.\n")
+	// Assert that the summary lines are present, most recent first, labeled with their period
+	assert.Contains(t, updatedContent, "## One-line note\n"+
+		"* [[2025-09-13]] (1 week ago): Summary from 1 week ago.\n"+
+		"* [[2025-08-20]] (1 month ago): Summary from 1 month ago.\n"+
+		"* [[2025-03-20]] (6 months ago): Summary from 6 months ago.\n"+
+		"* [[2024-09-20]] (1 year ago): Summary from 1 year ago.\n"+
+		"* [[2023-09-20]] (2 years ago): Summary from 2 years ago.\n\n")
 
 	// Also assert the overall structure around the one-line notes section
 	assert.Contains(t, updatedContent, "## LOG\n\n## One-line note\n")
 	assert.Contains(t, updatedContent, "# Sep 20 2025 Saturday\n\nInitial summary.\n\n")
 }
 
+func TestExtractLogEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	// Test case 1: Entries are parsed with their timestamp, continuation lines are kept with their entry
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	content := "# Sep 18 2025 Thursday\nSummary.\n\n# One-line note\n* [[2025-09-11]]: past\n\n# LOG\n\n09:00 First  entry\n  more details\n14:30 Second entry\n"
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "First  entry\nmore details", entries[0].Text)
+	assert.Equal(t, 9, entries[0].Time.Hour())
+	assert.Equal(t, "Second entry", entries[1].Text)
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, time.September, 18, 14, 30, 0, 0, time.UTC), entries[1].On(date))
+
+	// Test case 2: The LOG chapter ends at the next header
+	filePath = filepath.Join(tmpDir, "2025-09-19.md")
+	content = "# Title\n\n# LOG\n10:00 Only entry\n\n# Notes\n11:00 Not an entry\n"
+	err = os.WriteFile(filePath, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	entries, err = ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Only entry", entries[0].Text)
+
+	// Test case 3: Missing file
+	_, err = ExtractLogEntries(cfg, filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
+
+func TestEnsureOneLineNoteSection(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Test case 1: The section is inserted between the summary and the LOG chapter
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	err := os.WriteFile(filePath, []byte("# Sep 18 2025 Thursday\nSummary.\n\n# LOG\n\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+
+	err = EnsureOneLineNoteSection(filePath, "# One-line note")
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	expected := "# Sep 18 2025 Thursday\nSummary.\n\n# One-line note\n\n# LOG\n\n09:00 Entry\n"
+	assert.Equal(t, expected, string(content))
+
+	// Test case 2: Running it again is idempotent
+	err = EnsureOneLineNoteSection(filePath, "# One-line note")
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+
+	// Test case 3: A file without LOG chapter returns an error
+	noLogFilePath := filepath.Join(tmpDir, "no_log.md")
+	err = os.WriteFile(noLogFilePath, []byte("# Title\nSummary.\n"), 0644)
+	assert.NoError(t, err)
+	err = EnsureOneLineNoteSection(noLogFilePath, "# One-line note")
+	assert.ErrorContains(t, err, "LOG chapter not found in file")
+
+	// Test case 4: The section at another header level is found too
+	subHeaderPath := filepath.Join(tmpDir, "2025-09-19.md")
+	subHeaderContent := "# Sep 19 2025 Friday\n\n## One-line note \n\n## LOG\n\n09:00 Entry\n"
+	assert.NoError(t, os.WriteFile(subHeaderPath, []byte(subHeaderContent), 0644))
+	assert.NoError(t, EnsureOneLineNoteSection(subHeaderPath, "# One-line note"))
+	content, err = os.ReadFile(subHeaderPath)
+	assert.NoError(t, err)
+	assert.Equal(t, subHeaderContent, string(content))
+
+	// Test case 5: An entry logged while the file is locked is kept
+	lockedPath := filepath.Join(tmpDir, "2025-09-20.md")
+	assert.NoError(t, os.WriteFile(lockedPath, []byte("# Sep 20 2025 Saturday\n\n# LOG\n\n09:00 Entry\n"), 0644))
+	lock, err := filelock.Lock(lockedPath)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- EnsureOneLineNoteSection(lockedPath, "# One-line note") }()
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(lockedPath, []byte("# Sep 20 2025 Saturday\n\n# LOG\n\n09:00 Entry\n10:00 Another entry\n"), 0644))
+	lock.Unlock()
+	assert.NoError(t, <-done)
+	content, err = os.ReadFile(lockedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 20 2025 Saturday\n\n# One-line note\n\n# LOG\n\n09:00 Entry\n10:00 Another entry\n", string(content))
+}
+
+func TestAppendToLogPrepend(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	cfg.LogEntryOrder = config.LogEntryOrderPrepend
+	date := time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: Entries are stored newest first
+	for _, hour := range []int{9, 10, 11} {
+		err = AppendToLog(cfg, filePath, fmt.Sprintf("Entry at %d", hour), date.Add(time.Duration(hour)*time.Hour))
+		assert.NoError(t, err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-10-26\n\n# LOG\n\n11:00 Entry at 11\n10:00 Entry at 10\n09:00 Entry at 9\n", string(content))
+
+	// Test case 2: ExtractLogEntries keeps the file order
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "Entry at 11", entries[0].Text)
+	assert.Equal(t, "Entry at 10", entries[1].Text)
+	assert.Equal(t, "Entry at 9", entries[2].Text)
+
+	// Test case 3: Switching from append to prepend mode keeps the existing entries
+	cfg.LogEntryOrder = config.LogEntryOrderAppend
+	date = time.Date(2025, time.October, 27, 0, 0, 0, 0, time.UTC)
+	filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, AppendToLog(cfg, filePath, "First", date.Add(8*time.Hour)))
+	assert.NoError(t, AppendToLog(cfg, filePath, "Second", date.Add(9*time.Hour)))
+	assert.NoError(t, AppendToLogWithOptions(cfg, filePath, "Third", date.Add(10*time.Hour), AppendOptions{Prepend: true}))
+
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-10-27\n\n# LOG\n\n10:00 Third\n08:00 First\n09:00 Second\n", string(content))
+}
+
+func TestUpdateFileTitle(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Test case 1: The title is prepended to the original date heading
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	err := os.WriteFile(filePath, []byte("# Sep 18 2025 Thursday\nSummary.\n\n# LOG\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+
+	err = UpdateFileTitle(filePath, "Parser refactoring")
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Parser refactoring – Sep 18 2025 Thursday\nSummary.\n\n# LOG\n09:00 Entry\n", string(content))
+
+	// Test case 2: Calling it again replaces the title and keeps the date
+	err = UpdateFileTitle(filePath, "Parser refactoring")
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Parser refactoring – Sep 18 2025 Thursday\nSummary.\n\n# LOG\n09:00 Entry\n", string(content))
+
+	err = UpdateFileTitle(filePath, "Release day")
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Release day – Sep 18 2025 Thursday\n"))
+
+	// Test case 3: File without a title
+	noTitlePath := filepath.Join(tmpDir, "no-title.md")
+	err = os.WriteFile(noTitlePath, []byte("Just text\n"), 0644)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, UpdateFileTitle(noTitlePath, "Title"), "title not found in file")
+
+	// Test case 4: FinalizeDailyFile generates the title from the LOG entries when enabled
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.AITitleEnabled = true
+	mockAI := &ai.RecordingMockSummarizer{Summary: "Shipped the title feature"}
+	cfg.AISummarizer = mockAI
+	date := time.Date(2025, time.September, 19, 0, 0, 0, 0, time.UTC)
+	filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, AppendToLog(cfg, filePath, "Wrote the title feature", date.Add(9*time.Hour)))
+
+	err = FinalizeDailyFile(cfg, filePath, date)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Shipped the title feature – Sep 19 2025 Friday\n"))
+	assert.Equal(t, "Wrote the title feature", mockAI.Calls[len(mockAI.Calls)-1].Text)
+	assert.Equal(t, cfg.AITitlePrompt, mockAI.Calls[len(mockAI.Calls)-1].Prompt)
+
+	// Test case 5: The title is not generated again for the next entries
+	calls := len(mockAI.Calls)
+	mockAI.Summary = "Another title"
+	assert.NoError(t, AppendToLog(cfg, filePath, "Tested the title feature", date.Add(10*time.Hour)))
+	assert.NoError(t, FinalizeDailyFile(cfg, filePath, date))
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Shipped the title feature – Sep 19 2025 Friday\n"))
+	assert.Len(t, mockAI.Calls, calls)
+
+	// Test case 6: A title set while it was being generated is kept
+	assert.NoError(t, os.WriteFile(noTitlePath, []byte("# Sep 20 2025 Saturday\n"), 0644))
+	assert.NoError(t, updateFileTitle(noTitlePath, "Manual title", true))
+	assert.NoError(t, updateFileTitle(noTitlePath, "Generated title", false))
+	content, err = os.ReadFile(noTitlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Manual title – Sep 20 2025 Saturday\n", string(content))
+}
+
+func TestPartialWrites(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalPath := filepath.Join(tmpDir, "2025-09-18.md")
+	tmpPath := originalPath + ".tmp"
+	err := os.WriteFile(originalPath, []byte("# Sep 18 2025 Thursday\n\n# LOG\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(tmpPath, []byte("# Sep 18 2025 Thursday\n\n# LOG\n09:00 Entry\n10:00 Interrupted entry\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "notes.txt.tmp"), []byte("not a journal file"), 0644)
+	assert.NoError(t, err)
+
+	// Test case 1: Only the temporary journal files are found
+	partialWrites, err := FindPartialWrites(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tmpPath}, partialWrites)
+
+	// Test case 2: Recovering replaces the original file with the temporary one
+	err = RecoverPartialWrite(tmpPath)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(originalPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 18 2025 Thursday\n\n# LOG\n09:00 Entry\n10:00 Interrupted entry\n", string(content))
+	assert.NoFileExists(t, tmpPath)
+
+	partialWrites, err = FindPartialWrites(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, partialWrites)
+
+	// Test case 3: Discarding keeps the original file
+	err = os.WriteFile(tmpPath, []byte("garbage"), 0644)
+	assert.NoError(t, err)
+	err = DiscardPartialWrite(tmpPath)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, tmpPath)
+	content, err = os.ReadFile(originalPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 18 2025 Thursday\n\n# LOG\n09:00 Entry\n10:00 Interrupted entry\n", string(content))
+
+	// Test case 4: Files without the temporary suffix are refused
+	assert.ErrorContains(t, RecoverPartialWrite(originalPath), "not a partial write file")
+	assert.ErrorContains(t, DiscardPartialWrite(originalPath), "not a partial write file")
+
+	// Test case 5: The file of a write still running is not a partial write
+	os.WriteFile(tmpPath, []byte("# Sep 18 2025 Thursday\n"), 0644)
+	lock, err := filelock.Lock(originalPath)
+	assert.NoError(t, err)
+	partialWrites, err = FindPartialWrites(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, partialWrites)
+
+	// Test case 6: A write completed while waiting for the lock is left alone
+	done := make(chan error)
+	go func() { done <- RecoverPartialWrite(tmpPath) }()
+	time.Sleep(20 * time.Millisecond)
+	os.Rename(tmpPath, originalPath)
+	lock.Unlock()
+	assert.ErrorIs(t, <-done, ErrWriteCompleted)
+	assert.ErrorIs(t, DiscardPartialWrite(tmpPath), ErrWriteCompleted)
+	content, _ = os.ReadFile(originalPath)
+	assert.Equal(t, "# Sep 18 2025 Thursday\n", string(content))
+}
+
+func TestExtractSummaryFull(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Test case 1: Two paragraphs
+	filePath := filepath.Join(tmpDir, "two.md")
+	content := "# Sep 18 2025 Thursday\n<!-- add today summary below this line -->\nFirst paragraph,\non two lines.\n\nSecond paragraph.\n\n# One-line note\n\n# LOG\n09:00 Entry\n"
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	result, err := ExtractSummaryFull(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "First paragraph, on two lines.", result.Short)
+	assert.Equal(t, "First paragraph, on two lines.\n\nSecond paragraph.", result.Full)
+	assert.Equal(t, 2, result.ParagraphCount)
+
+	summary, err := ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Short, summary)
+
+	// Test case 2: One paragraph, the summary ends at the next chapter
+	filePath = filepath.Join(tmpDir, "one.md")
+	content = "# Sep 19 2025 Friday\n\nOnly paragraph.\n## Notes\nNot part of the summary.\n"
+	err = os.WriteFile(filePath, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	result, err = ExtractSummaryFull(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Only paragraph.", result.Short)
+	assert.Equal(t, "Only paragraph.", result.Full)
+	assert.Equal(t, 1, result.ParagraphCount)
+
+	// Test case 3: No summary
+	filePath = filepath.Join(tmpDir, "none.md")
+	err = os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\n# LOG\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+
+	result, err = ExtractSummaryFull(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, ExtractSummaryResult{}, result)
+
+	// Test case 4: Missing file
+	result, err = ExtractSummaryFull(filepath.Join(tmpDir, "missing.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ParagraphCount)
+}
+
+func TestAppendToLogPrependDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: Without a configured prefix the entry is unchanged
+	err = AppendToLogWithOptions(cfg, filePath, "No prefix", date.Add(9*time.Hour), AppendOptions{PrependDate: true})
+	assert.NoError(t, err)
+
+	// Test case 2: The prefix is rendered with EntryDatePrefix as the date layout
+	cfg.EntryDatePrefix = "Mon "
+	err = AppendToLogWithOptions(cfg, filePath, "Weekday prefix", date.Add(14*time.Hour+30*time.Minute), AppendOptions{PrependDate: true})
+	assert.NoError(t, err)
+
+	// Test case 3: The prefix is only added when requested
+	err = AppendToLog(cfg, filePath, "Not requested", date.Add(16*time.Hour))
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n\n# LOG\n\n09:00 No prefix\nThu 14:30 Weekday prefix\n16:00 Not requested\n", string(content))
+
+	// Test case 4: ExtractLogEntries parses the timestamp of prefixed and plain entries
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "No prefix", entries[0].Text)
+	assert.Equal(t, "Weekday prefix", entries[1].Text)
+	assert.Equal(t, time.Date(2025, time.September, 18, 14, 30, 0, 0, time.UTC), entries[1].On(date))
+	assert.Equal(t, "Not requested", entries[2].Text)
+	assert.Equal(t, 16, entries[2].Time.Hour())
+
+	// Test case 5: Any date layout can be used as prefix
+	cfg.EntryDatePrefix = "Jan 02 "
+	date = time.Date(2025, time.September, 19, 0, 0, 0, 0, time.UTC)
+	filePath, _, err = CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+	err = AppendToLogWithOptions(cfg, filePath, "Day prefix", date.Add(15*time.Hour), AppendOptions{PrependDate: true})
+	assert.NoError(t, err)
+
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-19\n\n# LOG\n\nSep 19 15:00 Day prefix\n", string(content))
+
+	entries, err = ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Day prefix", entries[0].Text)
+	assert.Equal(t, 15, entries[0].Time.Hour())
+}
+
+func TestDateFromFilePath(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	// Test case 1: Default daily file name
+	date, err := DateFromFilePath(cfg, filepath.Join(cfg.JournalDir, "2025-09-18.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC), date)
+
+	// Test case 2: Custom daily file name
+	cfg.DailyFileName = "{{.Date | formatDate \"02-01-2006\"}}.log"
+	date, err = DateFromFilePath(cfg, "/journal/25-12-2025.log")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC), date)
+
+	// Test case 3: File not matching the daily file name
+	_, err = DateFromFilePath(cfg, "/journal/review_week_2025_38.md")
+	assert.ErrorContains(t, err, "does not match the daily file name")
+}
+
+func TestCRLFJournalFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	content := "# Sep 18 2025 Thursday\r\nFirst line of the summary\r\nsecond line.\r\n\r\n# One-line note\r\n\r\n# LOG\r\n09:00 First entry\r\n"
+	err := os.WriteFile(filePath, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	// Test case 1: ExtractSummary
+	summary, err := ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "First line of the summary second line.", summary)
+
+	// Test case 2: ExtractLogEntries
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "First entry", entries[0].Text)
+	assert.Equal(t, 9, entries[0].Time.Hour())
+
+	// Test case 3: AppendToLog places the entry after the existing one
+	err = AppendToLog(cfg, filePath, "Second entry", time.Date(2025, time.September, 18, 10, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	written, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 18 2025 Thursday\nFirst line of the summary\nsecond line.\n\n# One-line note\n\n# LOG\n09:00 First entry\n10:00 Second entry\n", string(written))
+
+	// Test case 4: NormaliseCRLF
+	assert.Equal(t, "a\nb\nc", NormaliseCRLF("a\r\nb\rc"))
+}
+
+func TestTrimEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: The entry is stored unchanged by default
+	err = AppendToLog(cfg, filePath, "Pasted entry \t ", date.Add(9*time.Hour))
+	assert.NoError(t, err)
+
+	// Test case 2: Trailing whitespace is removed with TrimEntries
+	cfg.TrimEntries = true
+	err = AppendToLog(cfg, filePath, "  Another pasted entry \t ", date.Add(10*time.Hour))
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n\n# LOG\n\n09:00 Pasted entry \t \n10:00   Another pasted entry\n", string(content))
+
+	// Test case 3: TrimAllLines with mixed line endings
+	assert.Equal(t, "# Title\n\nline one\nline two\n\nlast", TrimAllLines("# Title  \r\n\t\nline one\t\r\nline two \n \r\nlast "))
+
+	// Test case 4: RepairFile cleans up the whole file, once
+	repaired, err := RepairFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, repaired)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n\n# LOG\n\n09:00 Pasted entry\n10:00   Another pasted entry\n", string(content))
+	repaired, err = RepairFile(filePath)
+	assert.NoError(t, err)
+	assert.False(t, repaired)
+
+	// Test case 5: Missing file
+	_, err = RepairFile(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
+
+func TestDayBoundaryHour(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DayBoundaryHour = 4
+
+	// Test case 1: Before the boundary hour the previous day file is created
+	filePath, _, err := CreateDailyJournalFile(cfg, time.Date(2025, time.September, 18, 1, 0, 0, 0, time.UTC), nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-17.md"), filePath)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Sep 17 2025 Wednesday\n"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "2025-09-18.md"))
+
+	// Test case 2: At the boundary hour exactly the current day file is created
+	filePath, _, err = CreateDailyJournalFile(cfg, time.Date(2025, time.September, 18, 4, 0, 0, 0, time.UTC), nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-18.md"), filePath)
+
+	// Test case 3: Without boundary hour the passed date is always used
+	cfg.DayBoundaryHour = 0
+	filePath, _, err = CreateDailyJournalFile(cfg, time.Date(2025, time.September, 20, 0, 30, 0, 0, time.UTC), nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-20.md"), filePath)
+
+	// Test case 4: EffectiveDate
+	assert.Equal(t, time.Date(2025, time.September, 30, 3, 59, 0, 0, time.UTC), EffectiveDate(time.Date(2025, time.October, 1, 3, 59, 0, 0, time.UTC), 4))
+	assert.Equal(t, time.Date(2025, time.October, 1, 4, 0, 0, 0, time.UTC), EffectiveDate(time.Date(2025, time.October, 1, 4, 0, 0, 0, time.UTC), 4))
+	assert.Equal(t, time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), EffectiveDate(time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), 0))
+}
+
+func TestTimezone(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.Timezone = "America/New_York"
+	// 23:30 of September 15 in New York, already September 16 in UTC
+	now := time.Date(2025, time.September, 16, 3, 30, 0, 0, time.UTC)
+
+	// Test case 1: The file is named with the date of the configured timezone
+	filePath, _, err := CreateDailyJournalFile(cfg, now, nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-15.md"), filePath)
+	content, _ := os.ReadFile(filePath)
+	assert.True(t, strings.HasPrefix(string(content), "# Sep 15 2025 Monday\n"))
+
+	// Test case 2: The entry is written with the time of the configured timezone
+	assert.NoError(t, AppendToLog(cfg, filePath, "Late night fix", now))
+	content, _ = os.ReadFile(filePath)
+	assert.True(t, strings.HasSuffix(string(content), "\n23:30 Late night fix\n"))
+
+	// Test case 3: The periods are calendar days, whatever the location of their dates
+	files, err := ListJournalFilesByPeriod(cfg, time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filePath}, files)
+	cfg.Timezone = "Asia/Tokyo"
+	files, err = ListJournalFilesByPeriod(cfg, time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filePath}, files)
+
+	// Test case 4: Without timezone the location of the passed time is used
+	cfg.Timezone = ""
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	filePath, _, err = CreateDailyJournalFile(cfg, time.Date(2025, time.September, 17, 23, 45, 0, 0, newYork), nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "2025-09-17.md"), filePath)
+	assert.NoError(t, AppendToLog(cfg, filePath, "Still the 17th", time.Date(2025, time.September, 17, 23, 45, 0, 0, newYork)))
+	content, _ = os.ReadFile(filePath)
+	assert.True(t, strings.HasSuffix(string(content), "23:45 Still the 17th\n"))
+}
+
+func TestEntryPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	monday := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, monday, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: An empty prefix adds nothing
+	err = AppendToLog(cfg, filePath, "Fixed the build", monday.Add(9*time.Hour))
+	assert.NoError(t, err)
+
+	// Test case 2: The prefix is rendered before the entry text
+	cfg.EntryPrefix = "[{{.Date | formatDate \"Mon\"}}]"
+	err = AppendToLog(cfg, filePath, "Reviewed the release notes", monday.Add(14*time.Hour+30*time.Minute))
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-15\n\n# LOG\n\n09:00 Fixed the build\n14:30 [Mon] Reviewed the release notes\n", string(content))
+
+	// Test case 3: The prefixed entries are still parsed
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "[Mon] Reviewed the release notes", entries[1].Text)
+
+	// Test case 4: Invalid prefix template
+	cfg.EntryPrefix = "[{{.Date | formatDate \"Mon\"]"
+	assert.ErrorContains(t, AppendToLog(cfg, filePath, "Lost entry", monday.Add(15*time.Hour)), "failed to render entry prefix")
+}
+
+func TestLogEntrySeparator(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	cfg.LogEntrySeparator = "---"
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: The separator goes before every entry but the first one
+	for i, entry := range []string{"First entry", "Second entry", "Third entry"} {
+		err = AppendToLog(cfg, filePath, entry, date.Add(time.Duration(9+i)*time.Hour))
+		assert.NoError(t, err)
+	}
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n\n# LOG\n\n09:00 First entry\n---\n10:00 Second entry\n---\n11:00 Third entry\n", string(content))
+
+	// Test case 2: Separators are not part of the entries
+	entries, err := ExtractLogEntries(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "First entry", entries[0].Text)
+	assert.Equal(t, "Second entry", entries[1].Text)
+	assert.Equal(t, "Third entry", entries[2].Text)
+
+	// Test case 3: Separators are not counted as words
+	count, err := CountWords(cfg, filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, count)
+
+	// Test case 4: Prepended entries are separated from the previous first entry
+	prependPath := filepath.Join(tmpDir, "prepend.md")
+	os.WriteFile(prependPath, []byte("# 2025-09-19\n\n# LOG\n"), 0644)
+	for i, entry := range []string{"Older entry", "Newer entry"} {
+		err = AppendToLogWithOptions(cfg, prependPath, entry, date.Add(time.Duration(9+i)*time.Hour), AppendOptions{Prepend: true})
+		assert.NoError(t, err)
+	}
+	content, err = os.ReadFile(prependPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-19\n\n# LOG\n\n10:00 Newer entry\n---\n09:00 Older entry\n", string(content))
+}
+
+func TestAutoReSummarize(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n<!-- summary below -->\n\n# One-line note\n\n# LOG\n"
+	cfg.AutoReSummarizeAfterEntries = 2
+	summarizer := &ai.RecordingMockSummarizer{Summary: "First summary."}
+	cfg.AISummarizer = summarizer
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, GenerateSummaryIfMissing(context.Background(), filePath, cfg, summarizer, cfg.AIPrompt, nil))
+	assert.Len(t, summarizer.Calls, 1)
+
+	// Test case 1: The summary is regenerated when the number of entries reaches a multiple of N
+	summarizer.Summary = "Second summary."
+	for i, entry := range []string{"First entry", "Second entry", "Third entry"} {
+		assert.NoError(t, AppendToLog(cfg, filePath, entry, date.Add(time.Duration(9+i)*time.Hour)))
+	}
+	assert.Len(t, summarizer.Calls, 2)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n<!-- summary below -->\nSecond summary.\n\n# One-line note\n\n# LOG\n\n09:00 First entry\n10:00 Second entry\n11:00 Third entry\n", string(content))
+
+	// Test case 2: Disabled by default
+	cfg.AutoReSummarizeAfterEntries = 0
+	assert.NoError(t, AppendToLog(cfg, filePath, "Fourth entry", date.Add(12*time.Hour)))
+	assert.Len(t, summarizer.Calls, 2)
+
+	// Test case 3: RegenerateSummary without a summarizer
+	cfg.AISummarizer = nil
+	assert.ErrorContains(t, RegenerateSummary(context.Background(), cfg, filePath, cfg.AIPrompt, nil, nil, true), "AI summarizer is not configured")
+}
+
+func TestAppendToLogConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+
+	// Test case 1: Two concurrent appends to the same file keep both entries
+	for run := 0; run < 100; run++ {
+		os.WriteFile(filePath, []byte("# Sep 15 2025\n\n# LOG\n\n"), 0644)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				assert.NoError(t, AppendToLog(cfg, filePath, fmt.Sprintf("Entry %d", i), time.Date(2025, time.September, 15, 9, i, 0, 0, time.UTC)))
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		entries, err := ExtractLogEntries(cfg, filePath)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	}
+}
+
+// loggingSummarizer logs an entry to a journal file while generating its summary, as another logbook process would.
+type loggingSummarizer struct {
+	cfg      *config.Config
+	filePath string
+	entry    string
+	summary  string
+}
+
+func (l *loggingSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	if l.entry != "" {
+		if err := AppendToLog(l.cfg, l.filePath, l.entry, time.Date(2025, time.September, 15, 10, 0, 0, 0, time.UTC)); err != nil {
+			return "", err
+		}
+	}
+	return l.summary, nil
+}
+
+func TestGenerateSummaryIfMissingConcurrentLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+
+	// Test case 1: An entry logged while the summary is generated is kept
+	os.WriteFile(filePath, []byte("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n"), 0644)
+	summarizer := &loggingSummarizer{cfg: cfg, filePath: filePath, entry: "Code review", summary: "Standup and review."}
+	assert.NoError(t, GenerateSummaryIfMissing(context.Background(), filePath, cfg, summarizer, "Summarize", strings.NewReader("")))
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, "# Sep 15 2025\nStandup and review.\n\n# LOG\n\n09:00 Standup\n10:00 Code review\n", string(content))
+
+	// Test case 2: A summary written while the summary is generated is not replaced
+	os.WriteFile(filePath, []byte("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n"), 0644)
+	writer := ai.AISummarizer(&writingSummarizer{filePath: filePath, content: "# Sep 15 2025\nManual summary.\n\n# LOG\n\n09:00 Standup\n"})
+	assert.NoError(t, GenerateSummaryIfMissing(context.Background(), filePath, cfg, writer, "Summarize", strings.NewReader("")))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "# Sep 15 2025\nManual summary.\n\n# LOG\n\n09:00 Standup\n", string(content))
+}
+
+// writingSummarizer replaces a journal file while generating its summary.
+type writingSummarizer struct {
+	filePath string
+	content  string
+}
+
+func (w *writingSummarizer) GenerateSummary(ctx context.Context, text string, prompt string) (string, error) {
+	return "Generated summary.", os.WriteFile(w.filePath, []byte(w.content), 0644)
+}
+
+func TestAppendToLogAtClock(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	os.WriteFile(filePath, []byte("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n"), 0644)
+	now := time.Date(2025, time.September, 15, 17, 42, 31, 0, time.UTC)
+
+	// Test case 1: Only the time of day is replaced
+	timestamp, err := AtClock(now, "08:15")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.September, 15, 8, 15, 0, 0, time.UTC), timestamp)
+
+	// Test case 2: The entry is written with the given time
+	assert.NoError(t, AppendToLog(cfg, filePath, "Forgot to log the coffee", timestamp))
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n08:15 Forgot to log the coffee\n", string(content))
+
+	// Test case 3: Invalid times
+	for _, clock := range []string{"25:99", "24:00", "8.15", "08:15:00", ""} {
+		_, err = AtClock(now, clock)
+		assert.EqualError(t, err, fmt.Sprintf("invalid time %q, expected HH:MM", clock))
+	}
+}
+
+func TestAppendToLogTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\n# LOG\n"
+	date := time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC)
+
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+
+	// Test case 1: Tags are appended as hashtags, without the typed "#" and in lower case
+	err = AppendToLogWithOptions(cfg, filePath, "Fixed the parser", date.Add(9*time.Hour), AppendOptions{Tags: []string{"work", "#GoLang"}})
+	assert.NoError(t, err)
+
+	// Test case 2: Tags with spaces are rejected and nothing is written
+	err = AppendToLogWithOptions(cfg, filePath, "Not written", date.Add(10*time.Hour), AppendOptions{Tags: []string{"side project"}})
+	assert.EqualError(t, err, `invalid tag "side project": tags cannot contain spaces`)
+
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 2025-09-18\n\n# LOG\n\n09:00 Fixed the parser #work #golang\n", string(content))
+
+	tags, err := ExtractTags(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"golang", "work"}, tags)
+}
+
+func TestListJournalFilesByGlob(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	createFile := func(name string) string {
+		filePath := filepath.Join(tmpDir, name)
+		assert.NoError(t, os.WriteFile(filePath, []byte("dummy content"), 0644))
+		return filePath
+	}
+
+	// Test case 1: Same files as the day-by-day lookup, sorted by date, ignoring the other files
+	cfg.DailyFileName = "{{.Date | formatDate \"Jan-02-2006\"}}.md"
+	dec31 := createFile("Dec-31-2024.md")
+	jan02 := createFile("Jan-02-2025.md")
+	feb01 := createFile("Feb-01-2025.md")
+	createFile("Mar-01-2025.md")
+	createFile("2025-W01.md")
+	createFile("jan-03-2025.md")
+
+	startDate := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	files, ok, err := listJournalFilesByGlob(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{dec31, jan02, feb01}, files)
+
+	slowFiles, err := listJournalFilesByDay(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Equal(t, slowFiles, files)
+
+	// Test case 2: The time of day of the dates gives the same days as the day-by-day lookup
+	startDate = time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC)
+	endDate = time.Date(2025, time.February, 1, 11, 0, 0, 0, time.UTC)
+	files, ok, err = listJournalFilesByGlob(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{dec31, jan02}, files)
+
+	slowFiles, err = listJournalFilesByDay(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Equal(t, slowFiles, files)
+
+	// Test case 3: File names without the full date fall back to the day-by-day lookup
+	cfg.DailyFileName = "{{.Date | formatDate \"Monday\"}}.md"
+	monday := createFile("Monday.md")
+	_, ok, err = listJournalFilesByGlob(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	files, err = ListJournalFilesByPeriod(cfg, time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{monday}, files)
+
+	// Test case 4: File names in subdirectories fall back to the day-by-day lookup
+	cfg.DailyFileName = "{{.Date | formatDate \"2006/01-02\"}}.md"
+	_, ok, err = listJournalFilesByGlob(cfg, startDate, endDate)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func BenchmarkListJournalFilesByPeriod(b *testing.B) {
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = b.TempDir()
+	cfg.DailyFileName = "{{.Date | formatDate \"2006-01-02\"}}.md"
+
+	// A journal with an entry every third day over 3 years
+	startDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 3) {
+		filePath := filepath.Join(cfg.JournalDir, d.Format("2006-01-02")+".md")
+		if err := os.WriteFile(filePath, []byte("dummy content"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("glob", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := listJournalFilesByGlob(cfg, startDate, endDate); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("day by day", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := listJournalFilesByDay(cfg, startDate, endDate); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestGenerateSummaryIfMissingCancelled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = t.TempDir()
+	cfg.DailyTemplate = "# Daily Log\n\n## LOG\n09:00 Fixed the parser\n"
+	filePath, _, err := CreateDailyJournalFile(cfg, time.Date(2025, time.November, 17, 0, 0, 0, 0, time.UTC), nil, nil)
+	assert.NoError(t, err)
+	before, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+
+	// Test case 1: An already cancelled context stops the generation and leaves the file unchanged
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = GenerateSummaryIfMissing(ctx, filePath, cfg, &ai.MockAISummarizer{Summary: "A summary"}, "Summarize", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	after, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+
+	// Test case 2: A slow summarizer is stopped when the deadline expires
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = GenerateSummaryIfMissing(ctx, filePath, cfg, &ai.MockAISummarizer{Summary: "A summary", Delay: time.Minute}, "Summarize", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestExtractLogSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(content string) string {
+		filePath := filepath.Join(tmpDir, "2025-09-15.md")
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+		return filePath
+	}
+
+	// Test case 1: The lines between the LOG header and the next header, as written
+	filePath := writeFile("# 2025-09-15\n\nSummary.\n\n# LOG\n\n09:00 Fixed the parser\n  with a test\n\n10:30 Reviewed a PR\n\n# One-line note\n- Old note\n")
+	log, err := ExtractLogSection(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00 Fixed the parser\n  with a test\n\n10:30 Reviewed a PR", log)
+
+	// Test case 2: The LOG goes to the end of the file, with Windows line endings
+	filePath = writeFile("# 2025-09-15\r\n\r\n# LOG\r\n09:00 Fixed the parser\r\n")
+	log, err = ExtractLogSection(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00 Fixed the parser", log)
+
+	// Test case 3: No LOG chapter
+	filePath = writeFile("# 2025-09-15\n\nSummary.\n")
+	log, err = ExtractLogSection(filePath)
+	assert.NoError(t, err)
+	assert.Empty(t, log)
+
+	// Test case 4: Missing file
+	_, err = ExtractLogSection(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
+
+func TestFormatLogEntry(t *testing.T) {
+	cfg := config.DefaultConfig()
+	timestamp := time.Date(2025, time.October, 27, 9, 5, 0, 0, time.UTC)
+
+	// Test case 1: The entry rendered with the log entry template
+	line, err := FormatLogEntry(cfg, "Fixed the parser", timestamp, AppendOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "09:05 Fixed the parser", line)
+
+	// Test case 2: Trailing spaces removed with TrimEntries
+	cfg.TrimEntries = true
+	line, err = FormatLogEntry(cfg, "Fixed the parser \t", timestamp, AppendOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "09:05 Fixed the parser", line)
+
+	// Test case 3: Tags, entry prefix and date prefix
+	cfg.EntryPrefix = "[{{.Time | formatDate \"Mon\"}}]"
+	cfg.EntryDatePrefix = "2006-01-02 "
+	line, err = FormatLogEntry(cfg, "Fixed the parser", timestamp, AppendOptions{PrependDate: true, Tags: []string{"#Work", "golang"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-10-27 09:05 [Mon] Fixed the parser #work #golang", line)
+
+	// Test case 4: The timestamp is shown in the configured timezone
+	cfg = config.DefaultConfig()
+	cfg.Timezone = "Europe/Rome"
+	line, err = FormatLogEntry(cfg, "Fixed the parser", timestamp, AppendOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "10:05 Fixed the parser", line)
+
+	// Test case 5: Invalid tags and templates
+	_, err = FormatLogEntry(config.DefaultConfig(), "Fixed the parser", timestamp, AppendOptions{Tags: []string{"two words"}})
+	assert.ErrorContains(t, err, "tags cannot contain spaces")
+	cfg = config.DefaultConfig()
+	cfg.LogEntryTemplate = "{{.Time | formatDate"
+	_, err = FormatLogEntry(cfg, "Fixed the parser", timestamp, AppendOptions{})
+	assert.ErrorContains(t, err, "failed to render log entry template")
+}
+
+func TestParseDay(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	// Test case 1: The start of the day
+	day, err := ParseDay(cfg, "2025-09-18")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.September, 18, 0, 0, 0, 0, cfg.Location()), day)
+
+	// Test case 2: The day starts at the boundary hour, so that the file of that date is used
+	cfg.DayBoundaryHour = 4
+	day, err = ParseDay(cfg, "2025-09-18")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.September, 18, 4, 0, 0, 0, cfg.Location()), day)
+	assert.Equal(t, "2025-09-18", EffectiveDate(day, cfg.DayBoundaryHour).Format("2006-01-02"))
+
+	// Test case 3: In the configured timezone
+	cfg.DayBoundaryHour = 0
+	cfg.Timezone = "Asia/Tokyo"
+	day, err = ParseDay(cfg, "2025-09-18")
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-09-18T00:00:00+09:00", day.Format(time.RFC3339))
+
+	// Test case 4: Invalid dates
+	_, err = ParseDay(cfg, "18/09/2025")
+	assert.EqualError(t, err, `invalid date "18/09/2025", expected YYYY-MM-DD`)
+	_, err = ParseDay(cfg, "2025-02-30")
+	assert.Error(t, err)
+}
+
+func TestLogToPastDay(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	now := time.Now().In(cfg.Location())
+	yesterday := EffectiveDate(now, cfg.DayBoundaryHour).AddDate(0, 0, -1)
+
+	// Test case 1: Yesterday's file is created if missing, and the entry keeps the current time
+	filePath, _, err := CreateDailyJournalFile(cfg, now.AddDate(0, 0, -1), nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, yesterday.Format("2006-01-02")+".md"), filePath)
+	assert.NoError(t, AppendToLog(cfg, filePath, "Forgot to log the deploy", now))
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), now.Format("15:04")+" Forgot to log the deploy")
+	assert.NoFileExists(t, filepath.Join(tmpDir, EffectiveDate(now, cfg.DayBoundaryHour).Format("2006-01-02")+".md"))
+
+	// Test case 2: An existing file of a given date is reused
+	day, err := ParseDay(cfg, yesterday.Format("2006-01-02"))
+	assert.NoError(t, err)
+	samePath, _, err := CreateDailyJournalFile(cfg, day, nil, strings.NewReader("\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, filePath, samePath)
+	assert.NoError(t, AppendToLog(cfg, samePath, "Second entry", now))
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Forgot to log the deploy")
+	assert.Contains(t, string(content), "Second entry")
+}
+
+func TestCountLogWords(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(name, content string) string {
+		filePath := filepath.Join(tmpDir, name)
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+		return filePath
+	}
+
+	// Test case 1: Only the words of the LOG chapter are counted, timestamps included
+	count, err := CountLogWords(writeFile("words.md", "# Sep 18 2025\n\nThe summary is not counted.\n\n# LOG\n09:00 Fixed the parser\n10:30  Reviewed\ttwo PRs\n\n## Notes\nNot counted either\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, count)
+
+	// Test case 2: Empty LOG chapter
+	count, err = CountLogWords(writeFile("empty.md", "# Sep 19 2025\n\nA summary.\n\n# LOG\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// Test case 3: LOG chapter with only whitespace
+	count, err = CountLogWords(writeFile("blank.md", "# Sep 20 2025\n\n# LOG\n  \n\t\n   \n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// Test case 4: No LOG chapter
+	count, err = CountLogWords(writeFile("nolog.md", "# Sep 21 2025\n\nJust a summary.\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// Test case 5: Missing file
+	_, err = CountLogWords(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
+
+func TestEncryptedJournal(t *testing.T) {
+	defer func() { fileutil.EncryptionKey = nil }()
+	fileutil.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.DailyTemplate = "# {{.Date | formatDate \"2006-01-02\"}}\n\nA private day.\n\n# LOG\n"
+	date := time.Date(2025, time.September, 18, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1: The daily file and its entries are written encrypted
+	filePath, _, err := CreateDailyJournalFile(cfg, date, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, AppendToLog(cfg, filePath, "Talked with HR", date))
+	raw, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "private")
+	assert.NotContains(t, string(raw), "HR")
+
+	// Test case 2: The summary and the LOG are decrypted when read
+	summary, err := ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "A private day.", summary)
+	log, err := ExtractLogSection(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00 Talked with HR", log)
+
+	// Test case 3: The files are listed by name, as the plain text ones
+	files, err := ListJournalFilesByPeriod(cfg, date, date)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filePath}, files)
+
+	// Test case 4: Without the key the file cannot be read
+	fileutil.EncryptionKey = nil
+	_, err = ExtractLogSection(filePath)
+	assert.ErrorContains(t, err, "the file is encrypted")
+}
+
+func TestRegenerateSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	withSummary := "# 2025-09-18\n<!-- summary below -->\nOld summary.\n\n# LOG\n09:00 Fixed the parser\n17:00 Released v2\n"
+	summarizer := &ai.RecordingMockSummarizer{Summary: "New summary."}
+
+	// Test case 1: Without force an existing summary is kept
+	os.WriteFile(filePath, []byte(withSummary), 0644)
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", summarizer, nil, false))
+	assert.Empty(t, summarizer.Calls)
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, withSummary, string(content))
+
+	// Test case 2: Without force a missing summary is generated
+	os.WriteFile(filePath, []byte("# 2025-09-18\n<!-- summary below -->\n\n# LOG\n09:00 Fixed the parser\n"), 0644)
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", summarizer, nil, false))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "# 2025-09-18\n<!-- summary below -->\nNew summary.\n\n# LOG\n09:00 Fixed the parser\n", string(content))
+
+	// Test case 3: With force the existing summary is replaced, with the given prompt
+	summarizer.Calls = nil
+	os.WriteFile(filePath, []byte(withSummary), 0644)
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", summarizer, nil, true))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "# 2025-09-18\n<!-- summary below -->\nNew summary.\n\n# LOG\n09:00 Fixed the parser\n17:00 Released v2\n", string(content))
+	assert.Len(t, summarizer.Calls, 1)
+	assert.Equal(t, "Summarize", summarizer.Calls[0].Prompt)
+	assert.NotContains(t, summarizer.Calls[0].Text, "Old summary.")
+
+	// Test case 4: With force the previous summary is kept when the AI fails or the manual summary is skipped
+	os.WriteFile(filePath, []byte(withSummary), 0644)
+	err := RegenerateSummary(context.Background(), cfg, filePath, "Summarize", &ai.MockAISummarizer{Err: errors.New("quota exceeded")}, nil, true)
+	assert.ErrorContains(t, err, "quota exceeded")
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, withSummary, string(content))
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", nil, strings.NewReader("\n"), true))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, withSummary, string(content))
+
+	// Test case 5: With force and a manual summary
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", nil, strings.NewReader("Typed summary.\n"), true))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "# 2025-09-18\n<!-- summary below -->\nTyped summary.\n\n# LOG\n09:00 Fixed the parser\n17:00 Released v2\n", string(content))
+
+	// Test case 6: With force, an entry logged while the summary is generated is kept
+	os.WriteFile(filePath, []byte(withSummary), 0644)
+	logger := &loggingSummarizer{cfg: cfg, filePath: filePath, entry: "Code review", summary: "New summary."}
+	assert.NoError(t, RegenerateSummary(context.Background(), cfg, filePath, "Summarize", logger, nil, true))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, "# 2025-09-18\n<!-- summary below -->\nNew summary.\n\n# LOG\n09:00 Fixed the parser\n17:00 Released v2\n10:00 Code review\n", string(content))
+
+	// Test case 7: The generation is stopped with the context, the previous summary is kept
+	os.WriteFile(filePath, []byte(withSummary), 0644)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = RegenerateSummary(ctx, cfg, filePath, "Summarize", &ai.ExternalAISummarizer{CommandTemplate: "sleep 5"}, nil, true)
+	assert.ErrorIs(t, err, context.Canceled)
+	content, _ = os.ReadFile(filePath)
+	assert.Equal(t, withSummary, string(content))
+}
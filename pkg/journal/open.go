@@ -0,0 +1,55 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/encrypt"
+)
+
+// newOpenCommand is replaced in tests.
+var newOpenCommand = exec.Command
+
+// openCommandFor returns the command opening a file with its default application on the given operating system.
+func openCommandFor(goos string, filePath string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{filePath}
+	case "windows":
+		// start is a builtin of cmd, its first quoted argument is the window title
+		return "cmd", []string{"/c", "start", "", filePath}
+	default:
+		return "xdg-open", []string{filePath}
+	}
+}
+
+// OpenFile opens the daily journal file of the given date with the default application of the operating system,
+// using open on macOS, start on Windows and xdg-open elsewhere. The file must exist and not be encrypted.
+func OpenFile(cfg *config.Config, date time.Time) error {
+	filePath, err := DailyFilePath(cfg, date)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no journal file for %s: %s does not exist", date.Format("2006-01-02"), filePath)
+		}
+		return fmt.Errorf("failed to check journal file %s: %w", filePath, err)
+	}
+	if encrypt.IsEncrypted(raw) {
+		// The default application would show the ciphertext
+		return fmt.Errorf("%s is encrypted, use logbook edit to read it", filePath)
+	}
+
+	name, args := openCommandFor(runtime.GOOS, filePath)
+	if output, err := newOpenCommand(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w: %s", filePath, name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
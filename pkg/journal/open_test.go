@@ -0,0 +1,82 @@
+package journal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenCommandFor(t *testing.T) {
+	name, args := openCommandFor("darwin", "/journal/2025-09-15.md")
+	assert.Equal(t, "open", name)
+	assert.Equal(t, []string{"/journal/2025-09-15.md"}, args)
+
+	name, args = openCommandFor("windows", `C:\journal\2025-09-15.md`)
+	assert.Equal(t, "cmd", name)
+	assert.Equal(t, []string{"/c", "start", "", `C:\journal\2025-09-15.md`}, args)
+
+	name, args = openCommandFor("linux", "/journal/2025-09-15.md")
+	assert.Equal(t, "xdg-open", name)
+	assert.Equal(t, []string{"/journal/2025-09-15.md"}, args)
+}
+
+func TestOpenFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the open command is a shell script in this test")
+	}
+
+	origNewOpenCommand := newOpenCommand
+	defer func() { newOpenCommand = origNewOpenCommand }()
+
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	date := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	os.WriteFile(filePath, []byte("# Sep 15 2025\n\n# LOG\n"), 0644)
+
+	// Test case 1: The command of the operating system is run with the file path
+	var gotName string
+	var gotArgs []string
+	newOpenCommand = func(name string, args ...string) *exec.Cmd {
+		gotName, gotArgs = name, args
+		return exec.Command("true")
+	}
+	assert.NoError(t, OpenFile(cfg, date))
+	expectedName, expectedArgs := openCommandFor(runtime.GOOS, filePath)
+	assert.Equal(t, expectedName, gotName)
+	assert.Equal(t, expectedArgs, gotArgs)
+
+	// Test case 2: The open command fails
+	newOpenCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo 'no application for text/markdown' >&2; exit 4")
+	}
+	err := OpenFile(cfg, date)
+	assert.ErrorContains(t, err, "failed to open "+filePath)
+	assert.ErrorContains(t, err, "no application for text/markdown")
+
+	// Test case 3: Missing file, the command is not run
+	gotName = ""
+	newOpenCommand = func(name string, args ...string) *exec.Cmd {
+		gotName = name
+		return exec.Command("true")
+	}
+	err = OpenFile(cfg, date.AddDate(0, 0, 1))
+	assert.EqualError(t, err, "no journal file for 2025-09-16: "+filepath.Join(tmpDir, "2025-09-16.md")+" does not exist")
+	assert.Empty(t, gotName)
+
+	// Test case 4: An encrypted file is not opened, the application would show the ciphertext
+	defer func() { fileutil.EncryptionKey = nil }()
+	fileutil.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	assert.NoError(t, fileutil.AtomicWrite(filePath, []byte("# Sep 15 2025\n\n# LOG\n"), 0644))
+	err = OpenFile(cfg, date)
+	assert.EqualError(t, err, filePath+" is encrypted, use logbook edit to read it")
+	assert.Empty(t, gotName)
+}
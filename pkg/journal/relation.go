@@ -0,0 +1,168 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// relationDateLayout is the layout of the dates of relation comments.
+const relationDateLayout = "2006-01-02"
+
+// relationPattern matches a relation comment, e.g. <!-- related: [[2025-09-15]] -->
+var relationPattern = regexp.MustCompile(`<!--\s*related:\s*\[\[([^\]]+)\]\]\s*-->`)
+
+// relationComment returns the HTML comment linking to the daily note of the given date.
+func relationComment(date string) string {
+	return fmt.Sprintf("<!-- related: [[%s]] -->", date)
+}
+
+// AddRelation links the newest entry of srcFile to the daily note of dstDate (YYYY-MM-DD), in both directions:
+// a relation comment to dstDate is added after the entry and one to the date of srcFile at the end of the LOG of dstDate.
+// The newest entry is the last one, or the first one with LogEntryOrder "prepend".
+// Both files are locked with filelock while they are rewritten.
+func AddRelation(cfg *config.Config, srcFile, dstDate string) error {
+	date, err := time.Parse(relationDateLayout, dstDate)
+	if err != nil {
+		return fmt.Errorf("invalid related date %q, expected YYYY-MM-DD: %w", dstDate, err)
+	}
+	srcDate, err := DateFromFilePath(cfg, srcFile)
+	if err != nil {
+		return err
+	}
+	dstFile, err := DailyFilePath(cfg, date)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dstFile); err != nil {
+		return fmt.Errorf("no journal file for %s: %w", dstDate, err)
+	}
+
+	unlock, err := lockFiles(srcFile, dstFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	afterFirstEntry := cfg.LogEntryOrder == config.LogEntryOrderPrepend
+	if err := insertRelation(srcFile, dstDate, afterFirstEntry); err != nil {
+		return err
+	}
+
+	// A day is listed once in the related file, even if several entries refer to it
+	relations, err := ExtractRelations(dstFile)
+	if err != nil {
+		return err
+	}
+	srcDateKey := srcDate.Format(relationDateLayout)
+	for _, related := range relations {
+		if related == srcDateKey {
+			return nil
+		}
+	}
+	return insertRelation(dstFile, srcDateKey, false)
+}
+
+// lockFiles takes the filelock of each of the given files and returns the function releasing them.
+// The files are locked in path order, so that two processes locking the same files cannot deadlock.
+func lockFiles(paths ...string) (func(), error) {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	var locks []*filelock.FileLock
+	unlock := func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+	for i, path := range sorted {
+		if i > 0 && path == sorted[i-1] {
+			continue // A file linked to itself is locked once
+		}
+		lock, err := filelock.Lock(path)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	return unlock, nil
+}
+
+// insertRelation adds a relation comment to the given date in the LOG chapter of a journal file,
+// after the first entry if afterFirstEntry is set, after the last one otherwise.
+func insertRelation(filePath, date string, afterFirstEntry bool) error {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	lines := strings.Split(NormaliseCRLF(string(content)), "\n")
+	logChapterIndex := -1
+	for i, line := range lines {
+		if isLogHeader(line) {
+			logChapterIndex = i
+			break
+		}
+	}
+	if logChapterIndex == -1 {
+		return fmt.Errorf("LOG chapter not found in file: %s", filePath)
+	}
+
+	// Skip the empty lines after the header, then move past the first entry and its relations or past all the entries
+	insertIndex := logChapterIndex + 1
+	for insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) == "" {
+		insertIndex++
+	}
+	if afterFirstEntry {
+		insertIndex++
+		for insertIndex < len(lines) && relationPattern.MatchString(lines[insertIndex]) {
+			insertIndex++
+		}
+		insertIndex = min(insertIndex, len(lines))
+	} else {
+		for insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) != "" {
+			insertIndex++
+		}
+	}
+
+	newLines := make([]string, 0, len(lines)+1)
+	newLines = append(newLines, lines[:insertIndex]...)
+	newLines = append(newLines, relationComment(date))
+	newLines = append(newLines, lines[insertIndex:]...)
+
+	modifiedContent := strings.Join(newLines, "\n")
+	if !strings.HasSuffix(modifiedContent, "\n") {
+		modifiedContent += "\n"
+	}
+
+	if err := fileutil.AtomicWrite(filePath, []byte(modifiedContent), 0644); err != nil {
+		return fmt.Errorf("failed to write to journal file: %w", err)
+	}
+	return nil
+}
+
+// ExtractRelations returns the dates referenced by the relation comments of a journal file, in file order and without duplicates.
+func ExtractRelations(filePath string) ([]string, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	var dates []string
+	seen := make(map[string]bool)
+	for _, match := range relationPattern.FindAllStringSubmatch(string(content), -1) {
+		date := strings.TrimSpace(match[1])
+		if !seen[date] {
+			seen[date] = true
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
@@ -0,0 +1,104 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRelation(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	pastFile := filepath.Join(tmpDir, "2025-09-15.md")
+	todayFile := filepath.Join(tmpDir, "2025-09-18.md")
+	os.WriteFile(pastFile, []byte("# Sep 15 2025\n\n# LOG\n09:00 Started the migration\n\n# Notes\n"), 0644)
+	os.WriteFile(todayFile, []byte("# Sep 18 2025\n\n# LOG\n10:00 Reviewed the plan\n11:00 Finished the migration\n"), 0644)
+
+	// Test case 1: Both files contain the cross-reference
+	err := AddRelation(cfg, todayFile, "2025-09-15")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(todayFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 18 2025\n\n# LOG\n10:00 Reviewed the plan\n11:00 Finished the migration\n<!-- related: [[2025-09-15]] -->\n", string(content))
+	content, err = os.ReadFile(pastFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n09:00 Started the migration\n<!-- related: [[2025-09-18]] -->\n\n# Notes\n", string(content))
+
+	// Test case 2: ExtractRelations returns the relation from both sides
+	relations, err := ExtractRelations(todayFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2025-09-15"}, relations)
+	relations, err = ExtractRelations(pastFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2025-09-18"}, relations)
+
+	// Test case 3: Relating another entry to the same day is not repeated in the related file
+	AppendToLog(cfg, todayFile, "Fixed the migration", time.Date(2025, time.September, 18, 12, 0, 0, 0, time.UTC))
+	err = AddRelation(cfg, todayFile, "2025-09-15")
+	assert.NoError(t, err)
+	content, err = os.ReadFile(todayFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "<!-- related: [[2025-09-15]] -->"))
+	content, err = os.ReadFile(pastFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "<!-- related: [[2025-09-18]] -->"))
+
+	// Test case 4: The relation goes after the newest entry when entries are prepended
+	cfg.LogEntryOrder = config.LogEntryOrderPrepend
+	prependFile := filepath.Join(tmpDir, "2025-09-19.md")
+	os.WriteFile(prependFile, []byte("# Sep 19 2025\n\n# LOG\n11:00 Newest entry\n10:00 Oldest entry\n"), 0644)
+	err = AddRelation(cfg, prependFile, "2025-09-15")
+	assert.NoError(t, err)
+	content, err = os.ReadFile(prependFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 19 2025\n\n# LOG\n11:00 Newest entry\n<!-- related: [[2025-09-15]] -->\n10:00 Oldest entry\n", string(content))
+	cfg.LogEntryOrder = config.LogEntryOrderAppend
+
+	// Test case 5: Relation comments are not part of the entries
+	entries, err := ExtractLogEntries(cfg, prependFile)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Newest entry", entries[0].Text)
+
+	// Test case 6: Invalid or missing related day
+	assert.ErrorContains(t, AddRelation(cfg, todayFile, "15/09/2025"), "invalid related date")
+	assert.ErrorContains(t, AddRelation(cfg, todayFile, "2025-09-01"), "no journal file for 2025-09-01")
+
+	// Test case 7: A file without relations
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-20.md"), []byte("# Sep 20 2025\n\n# LOG\n"), 0644)
+	relations, err = ExtractRelations(filepath.Join(tmpDir, "2025-09-20.md"))
+	assert.NoError(t, err)
+	assert.Empty(t, relations)
+
+	// Test case 8: A day related to itself is locked once
+	selfFile := filepath.Join(tmpDir, "2025-09-20.md")
+	os.WriteFile(selfFile, []byte("# Sep 20 2025\n\n# LOG\n09:00 Reread the notes\n"), 0644)
+	assert.NoError(t, AddRelation(cfg, selfFile, "2025-09-20"))
+	content, err = os.ReadFile(selfFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "<!-- related: [[2025-09-20]] -->"))
+
+	// Test case 9: An entry logged in the related file while it is locked is not lost
+	lock, err := filelock.Lock(pastFile)
+	assert.NoError(t, err)
+	done := make(chan error)
+	laterFile := filepath.Join(tmpDir, "2025-09-21.md")
+	os.WriteFile(laterFile, []byte("# Sep 21 2025\n\n# LOG\n09:00 Looked back at the migration\n"), 0644)
+	go func() { done <- AddRelation(cfg, laterFile, "2025-09-15") }()
+	time.Sleep(20 * time.Millisecond)
+	os.WriteFile(pastFile, []byte("# Sep 15 2025\n\n# LOG\n09:00 Started the migration\n18:00 Rolled back the migration\n"), 0644)
+	lock.Unlock()
+	assert.NoError(t, <-done)
+	content, err = os.ReadFile(pastFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n09:00 Started the migration\n18:00 Rolled back the migration\n<!-- related: [[2025-09-21]] -->\n", string(content))
+}
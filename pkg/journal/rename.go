@@ -0,0 +1,190 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// ErrTimestampConflict is returned by RenameEntry when another entry already has the new timestamp.
+var ErrTimestampConflict = errors.New("another entry already has the new timestamp")
+
+// logEntryBlock holds the lines of a log entry: the timestamped line and its continuation lines.
+type logEntryBlock struct {
+	time    time.Time
+	timed   bool
+	layout  string
+	text    string
+	lines   []string
+	trailer bool // The entry was followed by an empty line
+}
+
+// RenameEntry changes the timestamp of the entry of a daily journal file written at oldTimestamp,
+// moving it to its chronological position according to cfg.LogEntryOrder.
+// Only the time of day of the timestamps is used.
+// Returns ErrTimestampConflict if another entry, with a different text, is already at newTimestamp.
+// The file is locked with filelock while it is read and rewritten.
+func RenameEntry(cfg *config.Config, filePath string, oldTimestamp, newTimestamp time.Time) error {
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	entries, err := ExtractLogEntries(cfg, filePath)
+	if err != nil {
+		return err
+	}
+	found := -1
+	for i, entry := range entries {
+		if !entry.Time.IsZero() && sameClock(entry.Time, oldTimestamp) {
+			found = i
+			break
+		}
+	}
+	if found == -1 {
+		return fmt.Errorf("no entry at %s in %s", oldTimestamp.Format("15:04"), filePath)
+	}
+	for i, entry := range entries {
+		if i != found && !entry.Time.IsZero() && sameClock(entry.Time, newTimestamp) && entry.Text != entries[found].Text {
+			return fmt.Errorf("failed to move the entry to %s: %w", newTimestamp.Format("15:04"), ErrTimestampConflict)
+		}
+	}
+
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	lines := strings.Split(NormaliseCRLF(string(content)), "\n")
+
+	layouts, err := logEntryTimeLayouts(cfg)
+	if err != nil {
+		return err
+	}
+
+	// The entries go from the first non-empty line after the LOG header to the last one before the next chapter
+	logChapterIndex := -1
+	for i, line := range lines {
+		if isLogHeader(line) {
+			logChapterIndex = i
+			break
+		}
+	}
+	if logChapterIndex == -1 {
+		return fmt.Errorf("LOG chapter not found in file: %s", filePath)
+	}
+	start := logChapterIndex + 1
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	end := start
+	for end < len(lines) && !IsSectionHeader(strings.TrimSpace(lines[end])) {
+		end++
+	}
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	var blocks []*logEntryBlock
+	for _, line := range lines[start:end] {
+		trimmed := strings.TrimSpace(line)
+		if isLogEntrySeparator(cfg, trimmed) {
+			continue
+		}
+		if timestamp, text, layout, ok := parseLogEntryLineLayout(trimmed, layouts); ok {
+			blocks = append(blocks, &logEntryBlock{time: timestamp, timed: true, layout: layout, text: text, lines: []string{line}})
+			continue
+		}
+		if len(blocks) == 0 {
+			blocks = append(blocks, &logEntryBlock{})
+		}
+		blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+	}
+
+	// Take the entry out, with its new timestamp on the first line
+	var moved *logEntryBlock
+	for i, block := range blocks {
+		if block.timed && sameClock(block.time, oldTimestamp) {
+			moved = block
+			blocks = append(blocks[:i], blocks[i+1:]...)
+			break
+		}
+	}
+	if moved == nil {
+		return fmt.Errorf("no entry at %s in %s", oldTimestamp.Format("15:04"), filePath)
+	}
+	moved.time = time.Date(moved.time.Year(), moved.time.Month(), moved.time.Day(),
+		newTimestamp.Hour(), newTimestamp.Minute(), newTimestamp.Second(), 0, moved.time.Location())
+	moved.lines[0] = strings.TrimSpace(moved.time.Format(moved.layout) + " " + moved.text)
+
+	// Put it back before the first entry that comes after it
+	prepend := cfg.LogEntryOrder == config.LogEntryOrderPrepend
+	insertIndex := len(blocks)
+	for i, block := range blocks {
+		if !block.timed {
+			continue
+		}
+		if (!prepend && clockAfter(block.time, moved.time)) || (prepend && clockAfter(moved.time, block.time)) {
+			insertIndex = i
+			break
+		}
+	}
+	blocks = append(blocks[:insertIndex], append([]*logEntryBlock{moved}, blocks[insertIndex:]...)...)
+
+	// Entries separated by empty lines stay separated by empty lines
+	blankSeparated := false
+	for _, block := range blocks {
+		for len(block.lines) > 1 && strings.TrimSpace(block.lines[len(block.lines)-1]) == "" {
+			block.lines = block.lines[:len(block.lines)-1]
+			block.trailer = true
+		}
+		blankSeparated = blankSeparated || block.trailer
+	}
+
+	var entryLines []string
+	for i, block := range blocks {
+		if i > 0 {
+			if blankSeparated {
+				entryLines = append(entryLines, "")
+			}
+			if cfg.LogEntrySeparator != "" {
+				entryLines = append(entryLines, cfg.LogEntrySeparator)
+			}
+		}
+		entryLines = append(entryLines, block.lines...)
+	}
+
+	newLines := make([]string, 0, len(lines))
+	newLines = append(newLines, lines[:start]...)
+	newLines = append(newLines, entryLines...)
+	newLines = append(newLines, lines[end:]...)
+	if err := fileutil.AtomicWrite(filePath, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write to journal file: %w", err)
+	}
+	return nil
+}
+
+// parseLogEntryLineLayout is parseLogEntryLineWithLayouts that also returns the matching layout.
+func parseLogEntryLineLayout(line string, layouts []string) (time.Time, string, string, bool) {
+	for _, layout := range layouts {
+		if timestamp, text, ok := parseLogEntryLine(line, layout); ok {
+			return timestamp, text, layout, true
+		}
+	}
+	return time.Time{}, "", "", false
+}
+
+// sameClock reports whether two timestamps have the same time of day.
+func sameClock(a, b time.Time) bool {
+	return a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second()
+}
+
+// clockAfter reports whether the time of day of a is after the one of b.
+func clockAfter(a, b time.Time) bool {
+	return a.Hour()*3600+a.Minute()*60+a.Second() > b.Hour()*3600+b.Minute()*60+b.Second()
+}
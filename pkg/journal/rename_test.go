@@ -0,0 +1,83 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-15.md")
+	at := func(clock string) time.Time {
+		timestamp, _ := time.Parse("15:04", clock)
+		return timestamp
+	}
+	write := func(content string) {
+		os.WriteFile(filePath, []byte(content), 0644)
+	}
+	read := func() string {
+		content, _ := os.ReadFile(filePath)
+		return string(content)
+	}
+
+	// Test case 1: Moving an entry backward without changing the order
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n09:30 Code review\n11:00 Lunch\n")
+	assert.NoError(t, RenameEntry(cfg, filePath, at("09:30"), at("09:15")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n09:15 Code review\n11:00 Lunch\n", read())
+
+	// Test case 2: Moving an entry forward without changing the order
+	assert.NoError(t, RenameEntry(cfg, filePath, at("09:15"), at("10:45")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n10:45 Code review\n11:00 Lunch\n", read())
+
+	// Test case 3: Moving an entry across the others, with its continuation lines
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\nwith the whole team\n10:00 Code review\n11:00 Lunch\n\n# NOTES\nnothing\n")
+	assert.NoError(t, RenameEntry(cfg, filePath, at("09:00"), at("10:30")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n10:00 Code review\n10:30 Standup\nwith the whole team\n11:00 Lunch\n\n# NOTES\nnothing\n", read())
+	assert.NoError(t, RenameEntry(cfg, filePath, at("11:00"), at("08:00")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n08:00 Lunch\n10:00 Code review\n10:30 Standup\nwith the whole team\n\n# NOTES\nnothing\n", read())
+
+	// Test case 4: Newest first entries stay newest first
+	cfg.LogEntryOrder = config.LogEntryOrderPrepend
+	write("# Sep 15 2025\n\n# LOG\n\n11:00 Lunch\n10:00 Code review\n09:00 Standup\n")
+	assert.NoError(t, RenameEntry(cfg, filePath, at("09:00"), at("10:30")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n11:00 Lunch\n10:30 Standup\n10:00 Code review\n", read())
+	cfg.LogEntryOrder = config.LogEntryOrderAppend
+
+	// Test case 5: The entry separators are kept between the entries
+	cfg.LogEntrySeparator = "---"
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n---\n10:00 Code review\n---\n11:00 Lunch\n")
+	assert.NoError(t, RenameEntry(cfg, filePath, at("11:00"), at("09:30")))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n---\n09:30 Lunch\n---\n10:00 Code review\n", read())
+	cfg.LogEntrySeparator = ""
+
+	// Test case 6: Another entry is already at the new time
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n09:30 Code review\n")
+	err := RenameEntry(cfg, filePath, at("09:30"), at("09:00"))
+	assert.True(t, errors.Is(err, ErrTimestampConflict))
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n09:30 Code review\n", read())
+
+	// Test case 7: No entry at the old time
+	err = RenameEntry(cfg, filePath, at("12:00"), at("13:00"))
+	assert.ErrorContains(t, err, "no entry at 12:00")
+
+	// Test case 8: An entry logged while the file is locked is not lost
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n")
+	lock, err := filelock.Lock(filePath)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- RenameEntry(cfg, filePath, at("09:00"), at("09:05")) }()
+	time.Sleep(20 * time.Millisecond)
+	write("# Sep 15 2025\n\n# LOG\n\n09:00 Standup\n10:00 Deploy\n")
+	lock.Unlock()
+	assert.NoError(t, <-done)
+	assert.Equal(t, "# Sep 15 2025\n\n# LOG\n\n09:05 Standup\n10:00 Deploy\n", read())
+}
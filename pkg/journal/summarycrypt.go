@@ -0,0 +1,121 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/encrypt"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// EncryptedSummary is returned by ExtractSummary in place of an encrypted summary.
+const EncryptedSummary = "[encrypted]"
+
+// cipherFence opens the fenced code block holding an encrypted summary.
+const cipherFence = "```cipher"
+
+// encryptedSummaryBlock returns the fenced "cipher" code block of the summary encrypted with the passphrase.
+func encryptedSummaryBlock(summary, passphrase string) (string, error) {
+	encrypted, err := encrypt.EncryptWithPassphrase(summary, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt summary: %w", err)
+	}
+	return cipherFence + "\n" + encrypted + "\n```", nil
+}
+
+// summaryForFile returns the summary to write in a journal file, encrypted if cfg.EncryptSummary is set.
+func summaryForFile(cfg *config.Config, summary string) (string, error) {
+	if !cfg.EncryptSummary {
+		return summary, nil
+	}
+	if cfg.SummaryPassphrase == "" {
+		return "", fmt.Errorf("a passphrase is required to encrypt the summary")
+	}
+	return encryptedSummaryBlock(summary, cfg.SummaryPassphrase)
+}
+
+// summaryCipherText returns the encrypted text of the cipher block of the summary of a journal file.
+// ok is false if the summary is not encrypted.
+func summaryCipherText(lines []string) (text string, ok bool) {
+	for i := titleIndex(lines) + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "<!--") {
+			continue
+		}
+		if trimmed != cipherFence {
+			return "", false // A plaintext summary or the next chapter
+		}
+		var cipherLines []string
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "```" {
+				return strings.Join(cipherLines, ""), true
+			}
+			cipherLines = append(cipherLines, strings.TrimSpace(lines[j]))
+		}
+		return "", false // Block not closed
+	}
+	return "", false
+}
+
+// DecryptSummary returns the decrypted summary of a journal file whose summary was stored encrypted.
+func DecryptSummary(filePath string, passphrase string) (string, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	text, ok := summaryCipherText(strings.Split(NormaliseCRLF(string(content)), "\n"))
+	if !ok {
+		return "", fmt.Errorf("the summary of %s is not encrypted", filePath)
+	}
+	summary, err := encrypt.DecryptWithPassphrase(text, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the summary of %s: %w", filePath, err)
+	}
+	return summary, nil
+}
+
+// EncryptSummary replaces the plaintext summary of a journal file with its encrypted version.
+// Files without a summary or with an encrypted summary are left unchanged.
+// The file is locked with filelock while it is read and rewritten.
+func EncryptSummary(filePath string, passphrase string) error {
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+	lines := strings.Split(NormaliseCRLF(string(content)), "\n")
+	if _, ok := summaryCipherText(lines); ok {
+		return nil
+	}
+	summary, err := ExtractSummaryFull(filePath)
+	if err != nil {
+		return err
+	}
+	if summary.Full == "" {
+		return nil
+	}
+
+	start, end := summaryLineRange(lines)
+	if start == -1 {
+		return nil
+	}
+
+	block, err := encryptedSummaryBlock(summary.Full, passphrase)
+	if err != nil {
+		return err
+	}
+	newLines := append([]string{}, lines[:start]...)
+	newLines = append(newLines, block)
+	newLines = append(newLines, lines[end:]...)
+	if err := fileutil.AtomicWrite(filePath, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write journal file %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package journal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/encrypt"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	summarizer := &ai.MockAISummarizer{Summary: "Talked with HR about the new role."}
+
+	// Test case 1: A generated summary is stored encrypted, the LOG stays readable
+	filePath := filepath.Join(tmpDir, "2025-09-18.md")
+	os.WriteFile(filePath, []byte("# Sep 18 2025\n<!-- summary below -->\n\n# LOG\n10:00 Meeting with HR\n"), 0644)
+	cfg.EncryptSummary = true
+	cfg.SummaryPassphrase = "s3cret"
+	err := GenerateSummaryIfMissing(context.Background(), filePath, cfg, summarizer, "prompt", strings.NewReader(""))
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(filePath)
+	assert.NotContains(t, string(content), "new role")
+	assert.Contains(t, string(content), "<!-- summary below -->\n```cipher\n")
+	assert.Contains(t, string(content), "\n```\n\n# LOG\n10:00 Meeting with HR\n")
+
+	// Test case 2: The encrypted summary is not shown, nor generated again
+	summary, err := ExtractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, EncryptedSummary, summary)
+	full, err := ExtractSummaryFull(filePath)
+	assert.NoError(t, err)
+	assert.True(t, full.Encrypted)
+	err = GenerateSummaryIfMissing(context.Background(), filePath, cfg, &ai.MockAISummarizer{Summary: "Another summary"}, "prompt", strings.NewReader(""))
+	assert.NoError(t, err)
+	newContent, _ := os.ReadFile(filePath)
+	assert.Equal(t, string(content), string(newContent))
+
+	// Test case 3: Round-trip with the passphrase, failure with the wrong one
+	summary, err = DecryptSummary(filePath, "s3cret")
+	assert.NoError(t, err)
+	assert.Equal(t, "Talked with HR about the new role.", summary)
+	_, err = DecryptSummary(filePath, "wrong")
+	assert.ErrorIs(t, err, encrypt.ErrPassphrase)
+
+	// Test case 4: No passphrase to encrypt with
+	noSummaryPath := filepath.Join(tmpDir, "2025-09-19.md")
+	os.WriteFile(noSummaryPath, []byte("# Sep 19 2025\n\n# LOG\n10:00 Coding\n"), 0644)
+	cfg.SummaryPassphrase = ""
+	err = GenerateSummaryIfMissing(context.Background(), noSummaryPath, cfg, summarizer, "prompt", strings.NewReader(""))
+	assert.ErrorContains(t, err, "a passphrase is required")
+	_, err = DecryptSummary(noSummaryPath, "s3cret")
+	assert.ErrorContains(t, err, "is not encrypted")
+
+	// Test case 5: An existing plaintext summary is encrypted in place
+	plainPath := filepath.Join(tmpDir, "2025-09-20.md")
+	os.WriteFile(plainPath, []byte("# Sep 20 2025\nFirst paragraph\nof the summary.\n\nSecond paragraph.\n\n# One-line note\n\n# LOG\n10:00 Coding\n"), 0644)
+	assert.NoError(t, EncryptSummary(plainPath, "s3cret"))
+	content, _ = os.ReadFile(plainPath)
+	assert.True(t, strings.HasPrefix(string(content), "# Sep 20 2025\n```cipher\n"))
+	assert.True(t, strings.HasSuffix(string(content), "\n```\n\n# One-line note\n\n# LOG\n10:00 Coding\n"))
+	summary, err = DecryptSummary(plainPath, "s3cret")
+	assert.NoError(t, err)
+	assert.Equal(t, "First paragraph of the summary.\n\nSecond paragraph.", summary)
+
+	// Test case 6: Encrypting again or without a summary changes nothing
+	assert.NoError(t, EncryptSummary(plainPath, "other"))
+	newContent, _ = os.ReadFile(plainPath)
+	assert.Equal(t, string(content), string(newContent))
+	assert.NoError(t, EncryptSummary(noSummaryPath, "s3cret"))
+	newContent, _ = os.ReadFile(noSummaryPath)
+	assert.Equal(t, "# Sep 19 2025\n\n# LOG\n10:00 Coding\n", string(newContent))
+
+	// Test case 7: An entry logged while the file is locked is kept
+	lockedPath := filepath.Join(tmpDir, "2025-09-21.md")
+	os.WriteFile(lockedPath, []byte("# Sep 21 2025\nA quiet day.\n\n# LOG\n10:00 Coding\n"), 0644)
+	lock, err := filelock.Lock(lockedPath)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- EncryptSummary(lockedPath, "s3cret") }()
+	time.Sleep(20 * time.Millisecond)
+	os.WriteFile(lockedPath, []byte("# Sep 21 2025\nA quiet day.\n\n# LOG\n10:00 Coding\n11:00 Testing\n"), 0644)
+	lock.Unlock()
+	assert.NoError(t, <-done)
+	newContent, _ = os.ReadFile(lockedPath)
+	assert.True(t, strings.HasSuffix(string(newContent), "\n```\n\n# LOG\n10:00 Coding\n11:00 Testing\n"))
+}
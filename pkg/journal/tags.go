@@ -0,0 +1,136 @@
+package journal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+)
+
+// HashtagPattern matches a "#hashtag" at the start of a line or after a space. Tags start with a letter,
+// so that issue numbers like "#123" and Markdown headers are not tags.
+var HashtagPattern = regexp.MustCompile(`(?:^|\s)#(\p{L}[\p{L}\d_-]*)`)
+
+// TagEntry is a LOG line using a tag.
+type TagEntry struct {
+	Tag  string // The tag in lower case, without "#"
+	Date string // Date of the daily file, as YYYY-MM-DD
+	Line string
+}
+
+// lineTags returns the tags of a line, in lower case and without "#".
+func lineTags(line string) []string {
+	var tags []string
+	for _, match := range HashtagPattern.FindAllStringSubmatch(line, -1) {
+		tags = append(tags, strings.ToLower(match[1]))
+	}
+	return tags
+}
+
+// NormalizeTag returns a tag in lower case and without the leading "#" the user may have typed.
+// Tags with spaces are rejected, as they would not be recognized as a single "#hashtag".
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" {
+		return "", fmt.Errorf("tag cannot be empty")
+	}
+	if strings.ContainsFunc(tag, unicode.IsSpace) {
+		return "", fmt.Errorf("invalid tag %q: tags cannot contain spaces", tag)
+	}
+	return tag, nil
+}
+
+// AppendTags returns the entry followed by the normalized tags as hashtags, e.g. "Fixed the parser #work #golang".
+func AppendTags(entry string, tags []string) (string, error) {
+	for _, tag := range tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return "", err
+		}
+		entry += " #" + normalized
+	}
+	return entry, nil
+}
+
+// logLines returns the lines of the "LOG" chapter of a journal file, trimmed and without empty lines,
+// HTML comments and code blocks.
+func logLines(filePath string) ([]string, error) {
+	content, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+	}
+
+	var lines []string
+	inLogChapter, inCodeBlock := false, false
+	for _, line := range strings.Split(NormaliseCRLF(string(content)), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inLogChapter {
+			inLogChapter = isLogHeader(trimmed)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if IsSectionHeader(trimmed) {
+			break // Reached the next chapter
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "<!--") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return lines, nil
+}
+
+// ExtractTags returns the tags used in the "LOG" chapter of a journal file, in lower case, without "#",
+// deduplicated and sorted.
+func ExtractTags(filePath string) ([]string, error) {
+	lines, err := logLines(filePath)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, line := range lines {
+		for _, tag := range lineTags(line) {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// ListTagEntries returns a TagEntry for each tag of each LOG line of the daily files from startDate to endDate,
+// oldest first. A zero startDate or endDate leaves the range open on that side.
+func ListTagEntries(cfg *config.Config, startDate, endDate time.Time) ([]TagEntry, error) {
+	files, err := listDailyFilesInRange(cfg, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TagEntry
+	for _, file := range files {
+		lines, err := logLines(file.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			for _, tag := range lineTags(line) {
+				entries = append(entries, TagEntry{Tag: tag, Date: file.date.Format("2006-01-02"), Line: line})
+			}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,58 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	sep15 := filepath.Join(tmpDir, "2025-09-15.md")
+	os.WriteFile(sep15, []byte("# Sep 15 2025\n\nA #summary tag is not in the LOG.\n\n# LOG\n09:00 Deployed the #API, fixed #123\n"+
+		"10:00 #meeting with the #api team\n```sh\n# comment #notatag\n```\n<!-- #hidden -->\n\n# NOTES\n#notes\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n09:00 #Meeting again\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-20.md"), []byte("# Sep 20 2025\n\n# LOG\n09:00 #hiking\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_38.md"), []byte("# Review\n\n# LOG\n#review\n"), 0644)
+
+	// Test case 1: ExtractTags returns the tags of the LOG, deduplicated and sorted
+	tags, err := ExtractTags(sep15)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "meeting"}, tags)
+
+	// Test case 2: No tags
+	noTags := filepath.Join(tmpDir, "notes.md")
+	os.WriteFile(noTags, []byte("# Notes\n\n# LOG\n09:00 Nothing to tag\n"), 0644)
+	tags, err = ExtractTags(noTags)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+
+	// Test case 3: ListTagEntries lists each tag of each line of the daily files, oldest first
+	entries, err := ListTagEntries(cfg, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []TagEntry{
+		{Tag: "api", Date: "2025-09-15", Line: "09:00 Deployed the #API, fixed #123"},
+		{Tag: "meeting", Date: "2025-09-15", Line: "10:00 #meeting with the #api team"},
+		{Tag: "api", Date: "2025-09-15", Line: "10:00 #meeting with the #api team"},
+		{Tag: "meeting", Date: "2025-09-16", Line: "09:00 #Meeting again"},
+		{Tag: "hiking", Date: "2025-09-20", Line: "09:00 #hiking"},
+	}, entries)
+
+	// Test case 4: The date range is inclusive
+	entries, err = ListTagEntries(cfg, time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "2025-09-16", entries[0].Date)
+		assert.Equal(t, "2025-09-20", entries[1].Date)
+	}
+
+	// Test case 5: A missing file
+	_, err = ExtractTags(filepath.Join(tmpDir, "missing.md"))
+	assert.ErrorContains(t, err, "failed to read journal file")
+}
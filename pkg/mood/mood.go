@@ -0,0 +1,136 @@
+package mood
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// Mood is how the day felt, recorded in the LOG chapter with a "mood:" marker, e.g. "mood:happy".
+type Mood string
+
+// Supported moods, from the best to the worst.
+const (
+	Excited  Mood = "excited"
+	Happy    Mood = "happy"
+	Neutral  Mood = "neutral"
+	Tired    Mood = "tired"
+	Stressed Mood = "stressed"
+	Sad      Mood = "sad"
+	Angry    Mood = "angry"
+)
+
+// Moods are the supported moods, in the order of the reports.
+var Moods = []Mood{Excited, Happy, Neutral, Tired, Stressed, Sad, Angry}
+
+// maxBarWidth is the number of characters of the longest bar of FormatHistogram.
+const maxBarWidth = 40
+
+// MoodEntry is a mood recorded in a daily journal file.
+type MoodEntry struct {
+	Date time.Time // Date of the journal file, with the time of the entry
+	Mood Mood
+	Note string // The entry text without the marker, empty for a mood recorded alone
+}
+
+// ParseMood returns the Mood of a case-insensitive name, or an error if it is not one of Moods.
+func ParseMood(name string) (Mood, error) {
+	m := Mood(strings.ToLower(strings.TrimSpace(name)))
+	if !m.IsValid() {
+		names := make([]string, len(Moods))
+		for i, mood := range Moods {
+			names[i] = string(mood)
+		}
+		return "", fmt.Errorf("invalid mood %q, expected one of: %s", name, strings.Join(names, ", "))
+	}
+	return m, nil
+}
+
+// IsValid reports whether m is one of Moods.
+func (m Mood) IsValid() bool {
+	for _, mood := range Moods {
+		if m == mood {
+			return true
+		}
+	}
+	return false
+}
+
+// Marker returns the text recording m in a log entry, e.g. "mood:happy".
+func Marker(m Mood) string {
+	return "mood:" + string(m)
+}
+
+// RecordMood appends an entry with the marker of m to the LOG chapter of a daily journal file.
+func RecordMood(cfg *config.Config, filePath string, m Mood, timestamp time.Time) error {
+	if _, err := ParseMood(string(m)); err != nil {
+		return err
+	}
+	opts := journal.AppendOptions{Prepend: cfg.LogEntryOrder == config.LogEntryOrderPrepend}
+	if err := journal.AppendToLogWithOptions(cfg, filePath, Marker(m), timestamp, opts); err != nil {
+		return fmt.Errorf("failed to record mood: %w", err)
+	}
+	return nil
+}
+
+// ExtractMoods returns the moods recorded in the daily journal files from startDate to endDate included,
+// in chronological order. Markers of unsupported moods, e.g. emojis, are ignored.
+func ExtractMoods(cfg *config.Config, startDate, endDate time.Time) ([]MoodEntry, error) {
+	files, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files: %w", err)
+	}
+
+	var moods []MoodEntry
+	for _, filePath := range files {
+		emotions, err := journal.ExtractEmotions(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, emotion := range emotions {
+			m, err := ParseMood(emotion.Label)
+			if err != nil {
+				continue
+			}
+			moods = append(moods, MoodEntry{Date: emotion.Time, Mood: m, Note: emotion.Text})
+		}
+	}
+	return moods, nil
+}
+
+// Histogram returns the number of times each mood was recorded.
+func Histogram(entries []MoodEntry) map[Mood]int {
+	counts := make(map[Mood]int)
+	for _, entry := range entries {
+		counts[entry.Mood]++
+	}
+	return counts
+}
+
+// FormatHistogram renders the counts of a Histogram as one line per mood, in the order of Moods,
+// with a bar scaled to the most recorded mood, e.g. "happy     ######## 4".
+func FormatHistogram(counts map[Mood]int) string {
+	maxCount := 0
+	for _, count := range counts {
+		maxCount = max(maxCount, count)
+	}
+
+	var sb strings.Builder
+	for _, m := range Moods {
+		count := counts[m]
+		bar := 0
+		if maxCount > 0 {
+			// Rounded up so that any recorded mood stays visible
+			bar = (count*maxBarWidth + maxCount - 1) / maxCount
+		}
+		line := fmt.Sprintf("%-9s %s %d", m, strings.Repeat("#", bar), count)
+		if bar == 0 {
+			line = fmt.Sprintf("%-9s %d", m, count)
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
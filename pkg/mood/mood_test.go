@@ -0,0 +1,77 @@
+package mood
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMood(t *testing.T) {
+	// Test case 1: Supported moods, case-insensitive
+	m, err := ParseMood("happy")
+	assert.NoError(t, err)
+	assert.Equal(t, Happy, m)
+	m, err = ParseMood(" Stressed ")
+	assert.NoError(t, err)
+	assert.Equal(t, Stressed, m)
+
+	// Test case 2: Unsupported moods
+	_, err = ParseMood("hungry")
+	assert.EqualError(t, err, `invalid mood "hungry", expected one of: excited, happy, neutral, tired, stressed, sad, angry`)
+	_, err = ParseMood("")
+	assert.Error(t, err)
+	assert.False(t, Mood("😊").IsValid())
+}
+
+func TestRecordAndExtractMoods(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = t.TempDir()
+	writeDay := func(date, log string) string {
+		filePath := filepath.Join(cfg.JournalDir, date+".md")
+		assert.NoError(t, os.WriteFile(filePath, []byte("# "+date+"\n\n# LOG\n"+log), 0644))
+		return filePath
+	}
+	monday := writeDay("2025-09-15", "09:00 Demo went well [mood:happy]\n")
+	tuesday := writeDay("2025-09-16", "10:00 Fixed the parser\n11:00 Long meeting mood:Tired.\n12:00 mood:hungry\n")
+	writeDay("2025-10-01", "")
+
+	// Test case 1: RecordMood appends the marker as an entry
+	assert.NoError(t, RecordMood(cfg, tuesday, Sad, time.Date(2025, time.September, 16, 18, 30, 0, 0, time.UTC)))
+	content, err := os.ReadFile(tuesday)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "18:30 mood:sad")
+
+	// Test case 2: Unsupported moods are not recorded
+	assert.ErrorContains(t, RecordMood(cfg, monday, Mood("hungry"), time.Now()), `invalid mood "hungry"`)
+
+	// Test case 3: The moods of the period in chronological order, with their notes
+	moods, err := ExtractMoods(cfg, time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []MoodEntry{
+		{Date: time.Date(2025, time.September, 15, 9, 0, 0, 0, time.UTC), Mood: Happy, Note: "Demo went well"},
+		{Date: time.Date(2025, time.September, 16, 11, 0, 0, 0, time.UTC), Mood: Tired, Note: "Long meeting"},
+		{Date: time.Date(2025, time.September, 16, 18, 30, 0, 0, time.UTC), Mood: Sad, Note: ""},
+	}, moods)
+
+	// Test case 4: A period without moods
+	moods, err = ExtractMoods(cfg, time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Empty(t, moods)
+}
+
+func TestFormatHistogram(t *testing.T) {
+	// Test case 1: Bars scaled to the most recorded mood
+	counts := Histogram([]MoodEntry{{Mood: Happy}, {Mood: Happy}, {Mood: Happy}, {Mood: Happy}, {Mood: Sad}})
+	assert.Equal(t, map[Mood]int{Happy: 4, Sad: 1}, counts)
+	histogram := FormatHistogram(counts)
+	assert.Contains(t, histogram, "happy     ######################################## 4\n")
+	assert.Contains(t, histogram, "sad       ########## 1\n")
+	assert.Contains(t, histogram, "neutral   0\n")
+
+	// Test case 2: No moods
+	assert.Equal(t, "excited   0\nhappy     0\nneutral   0\ntired     0\nstressed  0\nsad       0\nangry     0\n", FormatHistogram(nil))
+}
@@ -1,74 +1,180 @@
 package oneline
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
+	"github.com/clobrano/LogBook/pkg/fileutil"
 	"github.com/clobrano/LogBook/pkg/template"
 )
 
 // GetPastSummaries retrieves summaries from past daily notes for specified periods.
 // This includes: 1 week ago, 1 month ago, 6 months ago, and all past years (as far back as entries exist).
 // If a file exists but has no summary and AI is enabled, it generates one.
-// Returns a map with date keys in YYYY-MM-DD format.
-func GetPastSummaries(cfg *config.Config, targetDate time.Time) (map[string]string, error) {
-	summaries := make(map[string]string)
-
+// Returns a map keyed by the past dates, at midnight UTC.
+func GetPastSummaries(cfg *config.Config, targetDate time.Time) (map[time.Time]string, error) {
 	// Add fixed periods: 1 week ago, 1 month ago, 6 months ago
 	fixedPeriods := []time.Time{
-		targetDate.AddDate(0, 0, -7),   // 1 week ago
-		targetDate.AddDate(0, -1, 0),   // 1 month ago
-		targetDate.AddDate(0, -6, 0),   // 6 months ago
+		targetDate.AddDate(0, 0, -7), // 1 week ago
+		targetDate.AddDate(0, -1, 0), // 1 month ago
+		targetDate.AddDate(0, -6, 0), // 6 months ago
 	}
 
-	for _, date := range fixedPeriods {
-		dateKey := date.Format("2006-01-02")
-		data := template.TemplateData{Date: date}
-		fileName, err := template.Render(cfg.DailyFileName, data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to render daily file name for %s: %w", dateKey, err)
-		}
-		filePath := filepath.Join(cfg.JournalDir, fileName)
-
-		summary := getSummaryWithAIFallback(filePath, cfg)
-		summaries[dateKey] = summary
+	summaries, err := BatchSummaryForDates(cfg, fixedPeriods)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add all past years dynamically (check up to 3 years back)
+	// Add all past years dynamically (check up to 3 years back), stopping at the first missing one
 	for yearsAgo := 1; yearsAgo <= 3; yearsAgo++ {
 		pastDate := targetDate.AddDate(-yearsAgo, 0, 0)
-		dateKey := pastDate.Format("2006-01-02")
-
-		data := template.TemplateData{Date: pastDate}
-		fileName, err := template.Render(cfg.DailyFileName, data)
+		summary, err := SummaryForDate(cfg, pastDate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to render daily file name for %s: %w", dateKey, err)
+			return nil, err
+		}
+		if summary == missingSummary {
+			break
 		}
-		filePath := filepath.Join(cfg.JournalDir, fileName)
-		summaries[dateKey] = getSummaryWithAIFallback(filePath, cfg)
+		summaries[dateKey(pastDate)] = summary
 	}
 
 	return summaries, nil
 }
 
+// dateKey returns the key of a date in the summaries maps: the date at midnight UTC.
+func dateKey(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// FormatPeriodLabel returns how long before target the past date is, e.g. "1 week ago", "6 months ago" or "2 years ago".
+// Only the dates are compared. Periods of a month or more are counted in whole months or years, shorter ones
+// in weeks when they are a whole number of weeks, else in days.
+func FormatPeriodLabel(target, past time.Time) string {
+	target, past = dateKey(target), dateKey(past)
+	if !past.Before(target) {
+		return "today"
+	}
+
+	months := (target.Year()-past.Year())*12 + int(target.Month()) - int(past.Month())
+	if target.Day() < past.Day() {
+		months--
+	}
+	days := int(target.Sub(past).Hours() / 24)
+	switch {
+	case months >= 12:
+		return pluralize(months/12, "year")
+	case months >= 1:
+		return pluralize(months, "month")
+	case days%7 == 0:
+		return pluralize(days/7, "week")
+	default:
+		return pluralize(days, "day")
+	}
+}
+
+// pluralize returns e.g. "1 week ago" or "3 weeks ago".
+func pluralize(count int, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", count, unit)
+}
+
+// dateKeyLayout is the layout of the dates in the one-line notes.
+const dateKeyLayout = "2006-01-02"
+
+// missingSummary is returned for dates without a journal file or a summary.
+const missingSummary = "missing"
+
+// SummaryForDate returns the summary of the daily note of the given date, or "missing" if there is none.
+// As GetPastSummaries, it generates and saves the summary with AI if the file has none and AI is enabled.
+func SummaryForDate(cfg *config.Config, date time.Time) (string, error) {
+	fileName, err := template.Render(cfg.DailyFileName, template.TemplateData{Date: date})
+	if err != nil {
+		return "", fmt.Errorf("failed to render daily file name for %s: %w", date.Format(dateKeyLayout), err)
+	}
+	filePath := filepath.Join(cfg.JournalDir, fileName)
+
+	// The AI fallback rewrites the file, so lookups of the same file are serialized
+	lock := fileLock(filePath)
+	lock.Lock()
+	defer lock.Unlock()
+
+	return getSummaryWithAIFallback(filePath, cfg), nil
+}
+
+// BatchSummaryForDates looks up the summaries of the given dates concurrently.
+// Returns a map keyed by the dates at midnight UTC, suitable for EmbedOneLineNotes.
+func BatchSummaryForDates(cfg *config.Config, dates []time.Time) (map[time.Time]string, error) {
+	summaries := make(map[time.Time]string, len(dates))
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+
+	for _, date := range dates {
+		wg.Add(1)
+		go func(date time.Time) {
+			defer wg.Done()
+			summary, err := SummaryForDate(cfg, date)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			summaries[dateKey(date)] = summary
+		}(date)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return summaries, nil
+}
+
+// fileLocks holds a mutex for each journal file looked up by SummaryForDate.
+var (
+	fileLocksMu sync.Mutex
+	fileLocks   = make(map[string]*sync.Mutex)
+)
+
+// fileLock returns the mutex of the given file path.
+func fileLock(filePath string) *sync.Mutex {
+	fileLocksMu.Lock()
+	defer fileLocksMu.Unlock()
+	lock, ok := fileLocks[filePath]
+	if !ok {
+		lock = &sync.Mutex{}
+		fileLocks[filePath] = lock
+	}
+	return lock
+}
+
 // getSummaryWithAIFallback gets summary from file, generates with AI if missing but file exists
 // If a summary is generated, it saves it back to the file for future use
 func getSummaryWithAIFallback(filePath string, cfg *config.Config) string {
 	summary, err := extractSummary(filePath)
 	if err != nil {
-		return "missing" // File doesn't exist or can't be read
+		return missingSummary // File doesn't exist or can't be read
 	}
 
 	if summary == "" {
 		// File exists but no summary - check if file actually has content
-		content, err := os.ReadFile(filePath)
+		content, err := fileutil.ReadFile(filePath)
 		if err != nil || len(content) == 0 {
-			return "missing"
+			return missingSummary
 		}
 
 		// File has content but no summary - generate with AI if available
@@ -80,7 +186,7 @@ func getSummaryWithAIFallback(filePath string, cfg *config.Config) string {
 			// Find the LOG section
 			logSectionStart := -1
 			for i, line := range lines {
-				if strings.HasPrefix(strings.TrimSpace(line), "# LOG") {
+				if isLogHeader(line) {
 					logSectionStart = i + 1
 					break
 				}
@@ -92,7 +198,7 @@ func getSummaryWithAIFallback(filePath string, cfg *config.Config) string {
 				contentToSummarize = strings.TrimSpace(contentToSummarize)
 
 				if len(contentToSummarize) > 0 {
-					generatedSummary, err := cfg.AISummarizer.GenerateSummary(contentToSummarize, cfg.AIPrompt)
+					generatedSummary, err := cfg.AISummarizer.GenerateSummary(context.Background(), contentToSummarize, cfg.AIPrompt)
 					if err == nil && generatedSummary != "" {
 						// Save the generated summary back to the file
 						err = saveSummaryToFile(filePath, generatedSummary)
@@ -105,35 +211,45 @@ func getSummaryWithAIFallback(filePath string, cfg *config.Config) string {
 				}
 			}
 		}
-		return "missing" // Couldn't generate summary
+		return missingSummary // Couldn't generate summary
 	}
 
 	return summary
 }
 
-// saveSummaryToFile inserts a summary into a journal file right after the title and HTML comment
+// saveSummaryToFile inserts a summary into a journal file right after the front matter, the title and HTML comment.
+// The file is locked with filelock while it is rewritten.
 func saveSummaryToFile(filePath string, summary string) error {
-	content, err := os.ReadFile(filePath)
+	lock, err := filelock.Lock(filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
 	lines := strings.Split(string(content), "\n")
-	if len(lines) == 0 {
-		return fmt.Errorf("file %s is empty", filePath)
+	_, title := fileutil.SplitFrontMatter(lines)
+	if title >= len(lines) {
+		return fmt.Errorf("file %s has no title", filePath)
 	}
 
-	// Build new content with summary inserted after title and optional HTML comment
+	// Build new content with summary inserted after front matter, title and optional HTML comment
 	var newContentBuilder strings.Builder
-	newContentBuilder.WriteString(lines[0]) // Title
-	newContentBuilder.WriteString("\n")
+	for _, line := range lines[:title+1] {
+		newContentBuilder.WriteString(line)
+		newContentBuilder.WriteString("\n")
+	}
 
-	// Check if line 1 is HTML comment, if so include it
-	startIdx := 1
-	if len(lines) > 1 && strings.HasPrefix(strings.TrimSpace(lines[1]), "<!--") {
-		newContentBuilder.WriteString(lines[1])
+	// Check if the line after the title is HTML comment, if so include it
+	startIdx := title + 1
+	if len(lines) > startIdx && strings.HasPrefix(strings.TrimSpace(lines[startIdx]), "<!--") {
+		newContentBuilder.WriteString(lines[startIdx])
 		newContentBuilder.WriteString("\n")
-		startIdx = 2
+		startIdx++
 	}
 
 	// Insert the summary
@@ -152,7 +268,7 @@ func saveSummaryToFile(filePath string, summary string) error {
 
 	modifiedContent := newContentBuilder.String()
 
-	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
+	err = fileutil.AtomicWrite(filePath, []byte(modifiedContent), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write summary to file %s: %w", filePath, err)
 	}
@@ -160,9 +276,10 @@ func saveSummaryToFile(filePath string, summary string) error {
 	return nil
 }
 
-// extractSummary reads a journal file and returns its first paragraph as the summary.
+// extractSummary reads a journal file and returns its first paragraph as the summary,
+// the same as the Short field of journal.ExtractSummaryFull (journal imports this package, so it cannot be used here).
 func extractSummary(filePath string) (string, error) {
-	content, err := os.ReadFile(filePath)
+	content, err := fileutil.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return "", nil // File does not exist, return empty summary and no error
@@ -170,16 +287,17 @@ func extractSummary(filePath string) (string, error) {
 		return "", fmt.Errorf("failed to read journal file %s: %w", filePath, err)
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(fileutil.NormaliseCRLF(string(content)), "\n")
 
-	// The first paragraph after the title and before the "LOG" chapter is considered the summary.
+	// The first paragraph after the front matter and the title, and before the "LOG" chapter, is considered the summary.
 	var summaryLines []string
 	readingSummary := false
 
-	for i := 1; i < len(lines); i++ {
+	_, title := fileutil.SplitFrontMatter(lines)
+	for i := title + 1; i < len(lines); i++ {
 		trimmedLine := strings.TrimSpace(lines[i])
 
-		if strings.HasPrefix(trimmedLine, "# LOG") || strings.HasPrefix(trimmedLine, "# One-line note") {
+		if isLogHeader(trimmedLine) || strings.HasPrefix(trimmedLine, "# One-line note") {
 			break // Reached the LOG or One-line note section, stop reading summary
 		}
 
@@ -199,6 +317,10 @@ func extractSummary(filePath string) (string, error) {
 			continue // Skip any sub-headings before the actual summary paragraph
 		}
 
+		if !readingSummary && trimmedLine == "```cipher" {
+			return "[encrypted]", nil // Same as journal.EncryptedSummary
+		}
+
 		readingSummary = true
 		summaryLines = append(summaryLines, trimmedLine)
 	}
@@ -210,71 +332,76 @@ func extractSummary(filePath string) (string, error) {
 	return "", nil // No summary found
 }
 
-// EmbedOneLineNotes embeds one-line summaries into the "One-line note" section of a daily note.
-// If one-line notes already exist, it skips embedding to avoid duplicates.
-func EmbedOneLineNotes(filePath string, summaries map[string]string) error {
-	contentBytes, err := os.ReadFile(filePath)
+// EmbedOneLineNotes embeds one-line summaries into the "One-line note" section of the daily note of targetDate,
+// replacing the notes already there. The summaries are keyed by date, as returned by GetPastSummaries and
+// BatchSummaryForDates, and each note is labeled with FormatPeriodLabel, e.g. "* [[2025-09-13]] (1 week ago): summary".
+// The file is locked with filelock while it is read and rewritten.
+func EmbedOneLineNotes(filePath string, targetDate time.Time, summaries map[time.Time]string) error {
+	lock, err := filelock.Lock(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", filePath, err)
+		return err
 	}
+	defer lock.Unlock()
 
-	content := string(contentBytes)
-	oneLineNoteSection := "# One-line note\n"
-
-	// Find the "One-line note" section
-	idx := strings.Index(content, oneLineNoteSection)
-	if idx == -1 {
-		return fmt.Errorf("\"One-line note\" section not found in file %s", filePath)
+	contentBytes, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	// Find where to insert/replace one-line notes
-	afterSection := idx + len(oneLineNoteSection)
-
-	// Find the end of the one-line note section (next # header or end of file)
-	endOfSection := afterSection
-	lines := strings.Split(content[afterSection:], "\n")
+	// The section goes from its header to the next header or the end of the file, e.g. "# LOG"
+	lines := strings.Split(fileutil.NormaliseCRLF(string(contentBytes)), "\n")
+	sectionStart := -1
 	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "#") {
-			// Found next section
-			endOfSection = afterSection + strings.Index(content[afterSection:], trimmed)
+		if isHeader(trimmed) && strings.TrimSpace(strings.TrimLeft(trimmed, "#")) == "One-line note" {
+			sectionStart = i
 			break
 		}
-		if i == len(lines)-1 {
-			// End of file
-			endOfSection = len(content)
-		}
 	}
-
-	// Build the one-line notes content
-	var oneLineNotesBuilder strings.Builder
-
-	// Extract and sort dates in reverse chronological order (most recent first)
-	var dates []string
-	for dateKey := range summaries {
-		dates = append(dates, dateKey)
+	if sectionStart == -1 {
+		return fmt.Errorf("\"One-line note\" section not found in file %s", filePath)
 	}
-	// Sort in reverse chronological order
-	sort.Strings(dates)
-	// Reverse the slice to get most recent first
-	for i, j := 0, len(dates)-1; i < j; i, j = i+1, j-1 {
-		dates[i], dates[j] = dates[j], dates[i]
+	sectionEnd := sectionStart + 1
+	for sectionEnd < len(lines) && !isHeader(strings.TrimSpace(lines[sectionEnd])) {
+		sectionEnd++
 	}
 
-	// Format each entry with wikilink
-	for _, dateKey := range dates {
-		summary := summaries[dateKey]
-		oneLineNotesBuilder.WriteString(fmt.Sprintf("* [[%s]]: %s\n", dateKey, summary))
+	// Sort the dates in reverse chronological order (most recent first)
+	var dates []time.Time
+	for date := range summaries {
+		dates = append(dates, date)
 	}
-	oneLineNotesBuilder.WriteString("\n")
+	sort.Slice(dates, func(i, j int) bool { return dates[i].After(dates[j]) })
 
-	// Replace the one-line notes section content
-	updatedContent := content[:afterSection] + oneLineNotesBuilder.String() + content[endOfSection:]
+	// Replace the whole content of the section, so that embedding again refreshes the notes
+	updatedLines := append([]string{}, lines[:sectionStart+1]...)
+	for _, date := range dates {
+		label := FormatPeriodLabel(targetDate, date)
+		updatedLines = append(updatedLines, fmt.Sprintf("* [[%s]] (%s): %s", date.Format(dateKeyLayout), label, summaries[date]))
+	}
+	updatedLines = append(updatedLines, "")
+	if sectionEnd == len(lines) {
+		updatedLines = append(updatedLines, "") // The notes end the file
+	}
+	updatedLines = append(updatedLines, lines[sectionEnd:]...)
+	updatedContent := strings.Join(updatedLines, "\n")
 
-	err = os.WriteFile(filePath, []byte(updatedContent), 0644)
+	err = fileutil.AtomicWrite(filePath, []byte(updatedContent), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write updated content to %s: %w", filePath, err)
 	}
 
 	return nil
 }
+
+// isLogHeader reports whether a line is the header of the "LOG" chapter, written "# LOG" or "## LOG".
+func isLogHeader(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# LOG") || strings.HasPrefix(trimmed, "## LOG")
+}
+
+// isHeader reports whether a trimmed line is a Markdown header, e.g. "# LOG", unlike a "#tag".
+func isHeader(trimmedLine string) bool {
+	header := strings.TrimLeft(trimmedLine, "#")
+	return header != trimmedLine && (header == "" || strings.HasPrefix(header, " "))
+}
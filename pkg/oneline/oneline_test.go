@@ -3,10 +3,13 @@ package oneline
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/clobrano/LogBook/pkg/ai"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/filelock"
 	"github.com/clobrano/LogBook/pkg/template"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,17 +43,192 @@ func TestGetPastSummaries(t *testing.T) {
 	createDummyJournalFile(targetDate.AddDate(-2, 0, 0), "Summary for 2 years ago.")  // 2 years ago
 	// Do not create file for 3 years ago to test breaking the loop
 
-	// Test case 1: Retrieve summaries for past periods
-	// Keys are now date strings in YYYY-MM-DD format
-	expectedSummaries := map[string]string{
-		"2025-09-13": "Summary for 1 week ago.",
-		"2025-08-20": "Summary for 1 month ago.",
-		"2025-03-20": "Summary for 6 months ago.",
-		"2024-09-20": "Summary for 1 year ago.",
-		"2023-09-20": "Summary for 2 years ago.",
+	// Test case 1: Retrieve summaries for past periods, keyed by date at midnight UTC
+	expectedSummaries := map[time.Time]string{
+		time.Date(2025, time.September, 13, 0, 0, 0, 0, time.UTC): "Summary for 1 week ago.",
+		time.Date(2025, time.August, 20, 0, 0, 0, 0, time.UTC):    "Summary for 1 month ago.",
+		time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC):     "Summary for 6 months ago.",
+		time.Date(2024, time.September, 20, 0, 0, 0, 0, time.UTC): "Summary for 1 year ago.",
+		time.Date(2023, time.September, 20, 0, 0, 0, 0, time.UTC): "Summary for 2 years ago.",
 	}
 
 	actualSummaries, err := GetPastSummaries(cfg, targetDate)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSummaries, actualSummaries)
-}
\ No newline at end of file
+
+	// Test case 2: The keys are at midnight UTC whatever the time and location of the target date
+	actualSummaries, err = GetPastSummaries(cfg, time.Date(2025, time.September, 20, 18, 45, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSummaries, actualSummaries)
+}
+
+func TestFormatPeriodLabel(t *testing.T) {
+	target := time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		past     time.Time
+		expected string
+	}{
+		{target, "today"},
+		{target.AddDate(0, 0, 1), "today"},
+		{target.AddDate(0, 0, -1), "1 day ago"},
+		{target.AddDate(0, 0, -3), "3 days ago"},
+		{target.AddDate(0, 0, -7), "1 week ago"},
+		{target.AddDate(0, 0, -10), "10 days ago"},
+		{target.AddDate(0, 0, -14), "2 weeks ago"},
+		{target.AddDate(0, -1, 0), "1 month ago"},
+		{target.AddDate(0, -1, 1), "30 days ago"},
+		{target.AddDate(0, -6, 0), "6 months ago"},
+		{target.AddDate(0, -13, 0), "1 year ago"},
+		{target.AddDate(-1, 0, 0), "1 year ago"},
+		{target.AddDate(-2, 0, 0), "2 years ago"},
+		{time.Date(2025, time.September, 13, 23, 30, 0, 0, time.UTC), "1 week ago"}, // Only the dates count
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FormatPeriodLabel(target, tt.past), "past date %s", tt.past)
+	}
+}
+
+func TestBatchSummaryForDates(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(date time.Time, content string) string {
+		filePath := filepath.Join(tmpDir, date.Format("2006-01-02")+".md")
+		os.WriteFile(filePath, []byte(content), 0644)
+		return filePath
+	}
+	jan3 := time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC)
+	feb29 := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+	aug1 := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(jan3, "# Jan 03 2021 Sunday\nA quiet Sunday.\n\n# LOG\n")
+	writeFile(feb29, "# Feb 29 2024 Thursday\nLeap day release.\n\n# LOG\n")
+
+	// Test case 1: A single date
+	summary, err := SummaryForDate(cfg, feb29)
+	assert.NoError(t, err)
+	assert.Equal(t, "Leap day release.", summary)
+
+	// Test case 2: Arbitrary dates, missing files are reported as "missing"
+	summaries, err := BatchSummaryForDates(cfg, []time.Time{jan3, feb29, aug1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[time.Time]string{
+		jan3:  "A quiet Sunday.",
+		feb29: "Leap day release.",
+		aug1:  "missing",
+	}, summaries)
+
+	// Test case 3: Concurrent lookups of the same file generate and save the summary once
+	filePath := writeFile(aug1, "# Aug 01 2025 Friday\n<!-- summary -->\n\n# LOG\n09:00 Entry\n")
+	mockAI := &ai.RecordingMockSummarizer{Summary: "Generated summary."}
+	cfg.AISummarizer = mockAI
+	dates := make([]time.Time, 20)
+	for i := range dates {
+		dates[i] = aug1
+	}
+	summaries, err = BatchSummaryForDates(cfg, dates)
+	assert.NoError(t, err)
+	assert.Equal(t, map[time.Time]string{aug1: "Generated summary."}, summaries)
+	assert.Len(t, mockAI.Calls, 1)
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "Generated summary."))
+
+	// Test case 4: Invalid daily file name
+	cfg.DailyFileName = "{{.Date | formatDate}}"
+	_, err = BatchSummaryForDates(cfg, []time.Time{jan3})
+	assert.ErrorContains(t, err, "failed to render daily file name for 2021-01-03")
+}
+
+func TestExtractSummaryCRLF(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "2025-09-18.md")
+	err := os.WriteFile(filePath, []byte("# Sep 18 2025 Thursday\r\nWindows summary.\r\n\r\n# LOG\r\n09:00 Entry\r\n"), 0644)
+	assert.NoError(t, err)
+
+	summary, err := extractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Windows summary.", summary)
+}
+
+func TestEmbedOneLineNotesIdempotent(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "2025-09-20.md")
+	date := time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC)
+	summaries := map[time.Time]string{
+		date.AddDate(0, 0, -7): "Summary from 1 week ago.",
+		date.AddDate(-1, 0, 0): "Summary from 1 year ago, #golang.",
+	}
+	expected := "# Sep 20 2025 Saturday\n\nSummary.\n\n" +
+		"# One-line note\n" +
+		"* [[2025-09-13]] (1 week ago): Summary from 1 week ago.\n" +
+		"* [[2024-09-20]] (1 year ago): Summary from 1 year ago, #golang.\n\n" +
+		"# LOG\n09:00 Entry\n"
+
+	// Test case 1: Stale notes are replaced, up to the next header
+	err := os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\nSummary.\n\n# One-line note\n\n* [[2025-09-19]] (1 day ago): Stale note.\n#notaheader\n\n# LOG\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+	assert.NoError(t, EmbedOneLineNotes(filePath, date, summaries))
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+
+	// Test case 2: Embedding again gives the same file
+	assert.NoError(t, EmbedOneLineNotes(filePath, date, summaries))
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+
+	// Test case 3: A section at the end of the file is refreshed too
+	err = os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\n# LOG\n09:00 Entry\n\n# One-line note\n"), 0644)
+	assert.NoError(t, err)
+	assert.NoError(t, EmbedOneLineNotes(filePath, date, summaries))
+	first, _ := os.ReadFile(filePath)
+	assert.NoError(t, EmbedOneLineNotes(filePath, date, summaries))
+	second, _ := os.ReadFile(filePath)
+	assert.Equal(t, string(first), string(second))
+	assert.True(t, strings.HasSuffix(string(second), "# One-line note\n* [[2025-09-13]] (1 week ago): Summary from 1 week ago.\n* [[2024-09-20]] (1 year ago): Summary from 1 year ago, #golang.\n\n"))
+
+	// Test case 4: Missing section
+	err = os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\n# LOG\n"), 0644)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, EmbedOneLineNotes(filePath, date, summaries), "section not found")
+
+	// Test case 5: An entry logged while the file is locked is kept
+	err = os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\n# One-line note\n\n# LOG\n09:00 Entry\n"), 0644)
+	assert.NoError(t, err)
+	lock, err := filelock.Lock(filePath)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() { done <- EmbedOneLineNotes(filePath, date, summaries) }()
+	time.Sleep(20 * time.Millisecond)
+	err = os.WriteFile(filePath, []byte("# Sep 20 2025 Saturday\n\n# One-line note\n\n# LOG\n09:00 Entry\n10:00 Another entry\n"), 0644)
+	assert.NoError(t, err)
+	lock.Unlock()
+	assert.NoError(t, <-done)
+	content, err = os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(content), "# LOG\n09:00 Entry\n10:00 Another entry\n"))
+}
+
+func TestSummaryFrontMatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	filePath := filepath.Join(tmpDir, "2025-09-11.md")
+	frontMatter := "---\ntitle: Day\ntags: [go]\n---\n"
+
+	// Test case 1: The front matter is not the summary
+	os.WriteFile(filePath, []byte(frontMatter+"# Sep 11 2025 Thursday\n\nFixed the parser.\n\n# LOG\n09:00 Fixed the parser #go\n"), 0644)
+	summary, err := extractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed the parser.", summary)
+
+	// Test case 2: A generated summary is saved after the title, the front matter is left as it is
+	os.WriteFile(filePath, []byte(frontMatter+"# Sep 11 2025 Thursday\n<!-- summary below -->\n\n# LOG\n09:00 Fixed the parser #go\n"), 0644)
+	cfg.AISummarizer = &ai.MockAISummarizer{Summary: "A parser day."}
+	assert.Equal(t, "A parser day.", getSummaryWithAIFallback(filePath, cfg))
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, frontMatter+"# Sep 11 2025 Thursday\n<!-- summary below -->\nA parser day.\n\n# LOG\n09:00 Fixed the parser #go\n", string(content))
+	summary, err = extractSummary(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "A parser day.", summary)
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the executables that provide logbook subcommands, e.g. "logbook-greet" for "logbook greet".
+const Prefix = "logbook-"
+
+// ErrNotFound is returned by Execute when no plugin provides the command.
+var ErrNotFound = errors.New("plugin not found")
+
+// PluginInfo describes a plugin found on $PATH.
+type PluginInfo struct {
+	Name string // The subcommand, without Prefix
+	Path string
+}
+
+// Discover scans $PATH for executables named Prefix + <command>.
+// When several directories provide the same command, the first one wins, as for any other executable.
+// Plugins are sorted by name.
+func Discover() []PluginInfo {
+	seen := make(map[string]bool)
+	var plugins []PluginInfo
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // Missing or unreadable directories are common in $PATH
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if !strings.HasPrefix(name, Prefix) || len(name) == len(Prefix) || entry.IsDir() {
+				continue
+			}
+			command := strings.TrimPrefix(name, Prefix)
+			if seen[command] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if _, err := exec.LookPath(path); err != nil {
+				continue // Not executable
+			}
+			seen[command] = true
+			plugins = append(plugins, PluginInfo{Name: command, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Execute runs the plugin providing the given command with args, connected to the terminal.
+// env is added to the current environment. If the plugin exits with an error, an *exec.ExitError is returned.
+func Execute(name string, args []string, env []string) error {
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s failed: %w", name, err)
+	}
+	return nil
+}
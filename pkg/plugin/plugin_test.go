@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts in this test")
+	}
+
+	pluginDir := t.TempDir()
+	otherDir := t.TempDir()
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+otherDir+string(os.PathListSeparator)+filepath.Join(pluginDir, "missing"))
+
+	outputFile := filepath.Join(t.TempDir(), "output.txt")
+	script := "#!/bin/sh\necho \"$@|$LOGBOOK_CONFIG_PATH|$LOGBOOK_JOURNAL_DIR\" > \"$OUTPUT_FILE\"\n"
+	os.WriteFile(filepath.Join(pluginDir, "logbook-greet"), []byte(script), 0755)
+	os.WriteFile(filepath.Join(pluginDir, "logbook-fail"), []byte("#!/bin/sh\nexit 3\n"), 0755)
+	os.WriteFile(filepath.Join(pluginDir, "logbook-notes.txt"), []byte("not executable"), 0644)
+	os.WriteFile(filepath.Join(otherDir, "logbook-greet"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	os.WriteFile(filepath.Join(otherDir, "logbook-sync"), []byte("#!/bin/sh\n"), 0755)
+
+	// Test case 1: Discover lists the executables once, sorted by name
+	plugins := Discover()
+	assert.Equal(t, []PluginInfo{
+		{Name: "fail", Path: filepath.Join(pluginDir, "logbook-fail")},
+		{Name: "greet", Path: filepath.Join(pluginDir, "logbook-greet")},
+		{Name: "sync", Path: filepath.Join(otherDir, "logbook-sync")},
+	}, plugins)
+
+	// Test case 2: The plugin receives the arguments and the environment
+	env := []string{"LOGBOOK_CONFIG_PATH=/home/user/.config/logbook/config.toml", "LOGBOOK_JOURNAL_DIR=/home/user/journal", "OUTPUT_FILE=" + outputFile}
+	err := Execute("greet", []string{"hello", "world"}, env)
+	assert.NoError(t, err)
+	output, err := os.ReadFile(outputFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world|/home/user/.config/logbook/config.toml|/home/user/journal\n", string(output))
+
+	// Test case 3: The exit code of a failing plugin is available
+	err = Execute("fail", nil, nil)
+	var exitErr *exec.ExitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+
+	// Test case 4: Unknown command
+	err = Execute("unknown", nil, nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,139 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// wikilinkPattern matches a link to a daily note, e.g. [[2025-09-15]].
+var wikilinkPattern = regexp.MustCompile(`\[\[(\d{4}-\d{2}-\d{2})\]\]`)
+
+// BestOfEntry is a daily note of the "Year's Best" section of the yearly review.
+type BestOfEntry struct {
+	Date       time.Time
+	Summary    string // The summary of the day or, if it has none, its first log entry
+	WordCount  int
+	References int // Number of other daily notes linking to the day
+}
+
+// BestOf holds the notable daily notes of a year. Each field is nil if the year has no such note.
+type BestOf struct {
+	Longest        *BestOfEntry // The note with the highest word count
+	MostReferenced *BestOfEntry // The note [[linked]] from the most other notes
+	First          *BestOfEntry // The first note of the year
+}
+
+// BestOfEntries returns the longest, the most referenced and the first daily notes of the year.
+// References are counted across all the daily notes of the journal, once per linking note. Ties go to the earliest day.
+func BestOfEntries(cfg *config.Config, year int) (*BestOf, error) {
+	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	journalFiles, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files for %d: %w", year, err)
+	}
+
+	references, err := countReferences(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bestOf := &BestOf{}
+	for _, filePath := range journalFiles {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			continue
+		}
+		words, err := journal.CountWords(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		entry := &BestOfEntry{Date: date, WordCount: words, References: references[date.Format("2006-01-02")]}
+
+		if bestOf.First == nil {
+			bestOf.First = entry
+		}
+		if bestOf.Longest == nil || entry.WordCount > bestOf.Longest.WordCount {
+			bestOf.Longest = entry
+		}
+		if entry.References > 0 && (bestOf.MostReferenced == nil || entry.References > bestOf.MostReferenced.References) {
+			bestOf.MostReferenced = entry
+		}
+	}
+
+	for _, entry := range []*BestOfEntry{bestOf.Longest, bestOf.MostReferenced, bestOf.First} {
+		if entry == nil || entry.Summary != "" {
+			continue // Shared entries are already summarized
+		}
+		entry.Summary, err = relatedSnippet(cfg, entry.Date.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bestOf, nil
+}
+
+// countReferences returns, for each YYYY-MM-DD date, the number of daily notes of the journal linking to it.
+func countReferences(cfg *config.Config) (map[string]int, error) {
+	files, err := filepath.Glob(filepath.Join(cfg.JournalDir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files in %s: %w", cfg.JournalDir, err)
+	}
+
+	references := make(map[string]int)
+	for _, filePath := range files {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			continue // Not a daily note, e.g. a review
+		}
+		content, err := fileutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+		}
+		linked := make(map[string]bool)
+		for _, match := range wikilinkPattern.FindAllStringSubmatch(string(content), -1) {
+			if match[1] != date.Format("2006-01-02") {
+				linked[match[1]] = true
+			}
+		}
+		for target := range linked {
+			references[target]++
+		}
+	}
+	return references, nil
+}
+
+// bestOfSection renders the notable daily notes of the year as a Markdown section.
+func bestOfSection(bestOf *BestOf) string {
+	var sb strings.Builder
+	sb.WriteString("## Year's Best\n\n")
+
+	sb.WriteString("### Longest Entry\n\n")
+	if bestOf.Longest != nil {
+		sb.WriteString(fmt.Sprintf("- [[%s]] (%d words): %s\n\n", bestOf.Longest.Date.Format("2006-01-02"), bestOf.Longest.WordCount, bestOf.Longest.Summary))
+	} else {
+		sb.WriteString("No entries this year.\n\n")
+	}
+
+	sb.WriteString("### Most Referenced\n\n")
+	if bestOf.MostReferenced != nil {
+		sb.WriteString(fmt.Sprintf("- [[%s]] (linked from %d days): %s\n\n", bestOf.MostReferenced.Date.Format("2006-01-02"), bestOf.MostReferenced.References, bestOf.MostReferenced.Summary))
+	} else {
+		sb.WriteString("No entries linked from other days.\n\n")
+	}
+
+	sb.WriteString("### First Entry\n\n")
+	if bestOf.First != nil {
+		sb.WriteString(fmt.Sprintf("- [[%s]]: %s\n\n", bestOf.First.Date.Format("2006-01-02"), bestOf.First.Summary))
+	} else {
+		sb.WriteString("No entries this year.\n\n")
+	}
+	return sb.String()
+}
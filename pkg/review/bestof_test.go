@@ -0,0 +1,77 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestOfEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(name, content string) {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+
+	// Test case 1: No journal files
+	bestOf, err := BestOfEntries(cfg, 2025)
+	assert.NoError(t, err)
+	assert.Nil(t, bestOf.Longest)
+	assert.Nil(t, bestOf.MostReferenced)
+	assert.Nil(t, bestOf.First)
+	assert.Contains(t, bestOfSection(bestOf), "### Most Referenced\n\nNo entries linked from other days.\n\n")
+
+	// A year of five files, three of which link to March 10
+	writeFile("2025-01-02.md", "# Jan 02 2025\nBack to work.\n\n# LOG\n09:00 Planned the year\n")
+	writeFile("2025-03-10.md", "# Mar 10 2025\nDesigned the new storage layer.\n\n# LOG\n10:00 Wrote the design doc\n")
+	writeFile("2025-03-11.md", "# Mar 11 2025\n\n# LOG\n10:00 Reviewed [[2025-03-10]] with the team and [[2025-01-02]]\n")
+	writeFile("2025-04-01.md", "# Apr 01 2025\n\n# LOG\n10:00 Implemented the design of [[2025-03-10]], see also [[2025-03-10]]\n")
+	writeFile("2025-06-20.md", "# Jun 20 2025\nShipped the storage layer.\n\n# LOG\n10:00 Released it, as designed on [[2025-03-10]] "+strings.Repeat("word ", 100)+"\n")
+	// Links from other years and reviews count only when they come from daily notes
+	writeFile("2026-01-05.md", "# Jan 05 2026\n\n# LOG\n10:00 One year since [[2025-01-02]]\n")
+	writeFile("review_year_2025.md", "# Yearly Review - 2025\n\n[[2025-01-02]] [[2025-01-02]]\n")
+
+	// Test case 2: The longest, most referenced and first entries
+	bestOf, err = BestOfEntries(cfg, 2025)
+	assert.NoError(t, err)
+	if assert.NotNil(t, bestOf.MostReferenced) {
+		assert.Equal(t, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), bestOf.MostReferenced.Date)
+		assert.Equal(t, 3, bestOf.MostReferenced.References)
+		assert.Equal(t, "Designed the new storage layer.", bestOf.MostReferenced.Summary)
+	}
+	if assert.NotNil(t, bestOf.Longest) {
+		assert.Equal(t, time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC), bestOf.Longest.Date)
+		assert.Equal(t, "Shipped the storage layer.", bestOf.Longest.Summary)
+	}
+	if assert.NotNil(t, bestOf.First) {
+		assert.Equal(t, time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), bestOf.First.Date)
+		assert.Equal(t, 2, bestOf.First.References)
+		assert.Equal(t, "Back to work.", bestOf.First.Summary)
+	}
+
+	// Test case 3: The yearly review has the section only when requested
+	summarizer := &ai.MockAISummarizer{Summary: "Yearly summary."}
+	reviewFile := filepath.Join(tmpDir, "review_year_2025.md")
+	os.Remove(reviewFile)
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "## Year's Best")
+
+	os.Remove(reviewFile)
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{BestOf: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "## Year's Best\n\n"+
+		"### Longest Entry\n\n- [[2025-06-20]] (")
+	assert.Contains(t, string(content), "### Most Referenced\n\n- [[2025-03-10]] (linked from 3 days): Designed the new storage layer.\n\n"+
+		"### First Entry\n\n- [[2025-01-02]]: Back to work.\n\n")
+}
@@ -0,0 +1,132 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// CrossRef is a phrase mentioned in the LOG entries of several days.
+type CrossRef struct {
+	Term     string
+	Dates    []time.Time
+	Contexts []string // The entry mentioning the term, one per date
+}
+
+// crossRefStopWords are ignored at the start and at the end of a phrase.
+var crossRefStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "be": true, "by": true, "for": true,
+	"from": true, "i": true, "in": true, "is": true, "it": true, "my": true, "of": true, "on": true,
+	"or": true, "the": true, "this": true, "that": true, "to": true, "was": true, "we": true, "with": true,
+}
+
+// CrossReferences returns the 2 and 3 word phrases of the LOG entries that appear in at least minMentions of the given files.
+// Phrases are compared case insensitively. A 2 word phrase is not reported when a longer phrase containing it
+// is mentioned on the same days. Results are sorted by number of days, then by term.
+func CrossReferences(cfg *config.Config, files []string, minMentions int) ([]CrossRef, error) {
+	byKey := make(map[string]*CrossRef)
+	for _, filePath := range files {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]bool) // Each phrase counts once per file
+		for _, entry := range entries {
+			for _, phrase := range phrases(entry.Text) {
+				key := strings.ToLower(phrase)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				ref, ok := byKey[key]
+				if !ok {
+					ref = &CrossRef{Term: phrase}
+					byKey[key] = ref
+				}
+				ref.Dates = append(ref.Dates, date)
+				ref.Contexts = append(ref.Contexts, entry.Text)
+			}
+		}
+	}
+
+	var refs []CrossRef
+	for key, ref := range byKey {
+		if len(ref.Dates) < minMentions || isCoveredByLongerPhrase(key, ref, byKey) {
+			continue
+		}
+		refs = append(refs, *ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if len(refs[i].Dates) != len(refs[j].Dates) {
+			return len(refs[i].Dates) > len(refs[j].Dates)
+		}
+		return strings.ToLower(refs[i].Term) < strings.ToLower(refs[j].Term)
+	})
+	return refs, nil
+}
+
+// phrases returns the 2 and 3 word phrases of a text that neither start nor end with a stop word.
+func phrases(text string) []string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		word := strings.Trim(field, ".,;:!?()[]{}\"'`")
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	var result []string
+	for size := 2; size <= 3; size++ {
+		for i := 0; i+size <= len(words); i++ {
+			first, last := strings.ToLower(words[i]), strings.ToLower(words[i+size-1])
+			if crossRefStopWords[first] || crossRefStopWords[last] {
+				continue
+			}
+			result = append(result, strings.Join(words[i:i+size], " "))
+		}
+	}
+	return result
+}
+
+// isCoveredByLongerPhrase reports whether a 2 word phrase is part of a 3 word phrase mentioned on the same days.
+func isCoveredByLongerPhrase(key string, ref *CrossRef, byKey map[string]*CrossRef) bool {
+	if len(strings.Fields(key)) != 2 {
+		return false
+	}
+	for otherKey, other := range byKey {
+		if len(strings.Fields(otherKey)) == 3 && len(other.Dates) == len(ref.Dates) &&
+			(strings.HasPrefix(otherKey, key+" ") || strings.HasSuffix(otherKey, " "+key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// crossReferenceSection renders the cross references as a Markdown section.
+func crossReferenceSection(refs []CrossRef) string {
+	var sb strings.Builder
+	sb.WriteString("## Cross References\n\n")
+	if len(refs) == 0 {
+		sb.WriteString("No topics mentioned across multiple days.\n\n")
+		return sb.String()
+	}
+	for _, ref := range refs {
+		dates := make([]string, 0, len(ref.Dates))
+		for _, date := range ref.Dates {
+			dates = append(dates, date.Format("2006-01-02"))
+		}
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", ref.Term, strings.Join(dates, ", ")))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrossReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(day int, entries ...string) string {
+		filePath := filepath.Join(tmpDir, fmt.Sprintf("2025-09-%02d.md", day))
+		content := fmt.Sprintf("# Sep %02d 2025\nSummary of the day.\n\n# LOG\n%s\n", day, strings.Join(entries, "\n"))
+		os.WriteFile(filePath, []byte(content), 0644)
+		return filePath
+	}
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	files := []string{
+		writeFile(15, "09:00 Opened PR #1234 for the login page", "11:00 Lunch with the team"),
+		writeFile(16, "10:00 Review comments on PR #1234"),
+		writeFile(17, "09:30 Rebased pr #1234 on main", "15:00 Debugged the flaky CI job"),
+		writeFile(18, "14:00 Merged PR #1234, finally"),
+	}
+
+	// Test case 1: A phrase mentioned on 4 days is found, phrases mentioned once are not
+	refs, err := CrossReferences(cfg, files, 3)
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, "PR #1234", refs[0].Term)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.September, 17, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.September, 18, 0, 0, 0, 0, time.UTC),
+	}, refs[0].Dates)
+	assert.Equal(t, "Review comments on PR #1234", refs[0].Contexts[1])
+
+	// Test case 2: Threshold above the number of mentions
+	refs, err = CrossReferences(cfg, files, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+
+	// Test case 3: The weekly review lists the cross references
+	summarizer := &ai.MockAISummarizer{Summary: "Weekly summary."}
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{CrossReference: true, MinMentions: 3})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "## Cross References\n\n- **PR #1234**: 2025-09-15, 2025-09-16, 2025-09-17, 2025-09-18\n")
+	assert.NotContains(t, string(content), "login page")
+	assert.NotContains(t, string(content), "flaky CI")
+
+	// Test case 4: Files not matching the daily file name
+	_, err = CrossReferences(cfg, []string{filepath.Join(tmpDir, "notes.md")}, 2)
+	assert.ErrorContains(t, err, "does not match the daily file name")
+}
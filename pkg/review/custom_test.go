@@ -0,0 +1,70 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewCustomRange(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-05.md"), []byte("# Jan 05 2025\n\nBefore the sprint.\n\n# LOG\n09:00 Rest\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-06.md"), []byte("# Jan 06 2025\n\nSprint planning.\n\n# LOG\n09:00 Planning\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-13.md"), []byte("# Jan 13 2025\n\nSecond week.\n\n# LOG\n09:00 Coding\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-17.md"), []byte("# Jan 17 2025\n\nSprint demo.\n\n# LOG\n09:00 Demo\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-18.md"), []byte("# Jan 18 2025\n\nAfter the sprint.\n\n# LOG\n09:00 Rest\n"), 0644)
+	reviewFile := filepath.Join(tmpDir, "review_custom_2025-01-06_2025-01-17.md")
+	start := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)
+
+	// Test case 1: The daily summaries of the days of the range only, both ends included
+	summarizer := &ai.RecordingMockSummarizer{Summary: "A two-week sprint."}
+	result, err := ReviewCustomRange(cfg, start, end, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, result, reviewFile)
+	content, _ := os.ReadFile(reviewFile)
+	assert.Equal(t, "# Review - 2025-01-06 to 2025-01-17\nA two-week sprint.\n\n## Daily Summaries\n\n"+
+		"### 2025-01-06\nSprint planning.\n\n### 2025-01-13\nSecond week.\n\n### 2025-01-17\nSprint demo.\n\n", string(content))
+
+	// Test case 2: The sidecar and ListReviews know the range
+	meta, err := ReadSidecar(reviewFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "custom", meta.Type)
+		assert.Equal(t, "2025-01-06", meta.Start)
+		assert.Equal(t, "2025-01-17", meta.End)
+		assert.Equal(t, 3, meta.EntryCount)
+	}
+	reviews, err := ListReviews(cfg)
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 1) {
+		assert.Equal(t, "custom", reviews[0].Type)
+		assert.Equal(t, start, reviews[0].StartDate)
+		assert.Equal(t, end, reviews[0].EndDate)
+	}
+	os.Remove(filepath.Join(tmpDir, "review_custom_2025-01-06_2025-01-17.meta.json"))
+	reviews, err = ListReviews(cfg)
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 1) {
+		assert.Equal(t, start, reviews[0].StartDate)
+		assert.Equal(t, end, reviews[0].EndDate)
+	}
+
+	// Test case 3: A single day range without journal files
+	day := time.Date(2025, time.February, 1, 15, 30, 0, 0, time.Local)
+	_, err = ReviewCustomRange(cfg, day, day, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(filepath.Join(tmpDir, "review_custom_2025-02-01_2025-02-01.md"))
+	assert.Contains(t, string(content), "No journal entries found for this period.")
+
+	// Test case 4: The end date is before the start date
+	_, err = ReviewCustomRange(cfg, end, start, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.ErrorContains(t, err, "end date 2025-01-06 is before start date 2025-01-17")
+}
@@ -0,0 +1,43 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// footerMarker precedes the footer of a review, so that it is replaced instead of duplicated on regeneration.
+const footerMarker = "<!-- logbook-footer -->"
+
+// BuildWeekFooter returns the running total line of the weekly review of the given journal files:
+// the number of LOG entries, the number of words they contain and the number of days with at least one entry.
+func BuildWeekFooter(cfg *config.Config, files []string) (string, error) {
+	entries, words, days := 0, 0, 0
+	for _, filePath := range files {
+		logEntries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to count entries for weekly review footer: %w", err)
+		}
+		if len(logEntries) == 0 {
+			continue
+		}
+		days++
+		entries += len(logEntries)
+		for _, entry := range logEntries {
+			words += len(strings.Fields(entry.Text))
+		}
+	}
+	return fmt.Sprintf("*Week total: %d entries, %d words, %d days active*", entries, words, days), nil
+}
+
+// replaceFooter appends the footer to the content of a review, after its marker.
+// An existing footer is removed first.
+func replaceFooter(content, footer string) string {
+	if index := strings.Index(content, footerMarker); index != -1 {
+		content = content[:index]
+	}
+	content = strings.TrimRight(content, "\n")
+	return content + "\n\n" + footerMarker + "\n" + footer + "\n"
+}
@@ -0,0 +1,54 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekFooter(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\n# LOG\n09:00 Released v2\n10:00 Wrote the release notes\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-17.md"), []byte("# Sep 17 2025\n\n# LOG\n11:00 Fixed the hotfix\n"), 0644)
+	reviewFile := filepath.Join(tmpDir, "review_week_2025_38.md")
+	summarizer := &ai.RecordingMockSummarizer{Summary: "A release week."}
+
+	// Test case 1: BuildWeekFooter counts entries, words and the days with entries
+	files := []string{filepath.Join(tmpDir, "2025-09-15.md"), filepath.Join(tmpDir, "2025-09-16.md"), filepath.Join(tmpDir, "2025-09-17.md")}
+	footer, err := BuildWeekFooter(cfg, files)
+	assert.NoError(t, err)
+	assert.Equal(t, "*Week total: 3 entries, 9 words, 2 days active*", footer)
+
+	// Test case 2: The footer is at the end of the weekly review by default
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), DefaultReviewOptions())
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.True(t, strings.HasSuffix(string(content), "\n\n<!-- logbook-footer -->\n*Week total: 3 entries, 9 words, 2 days active*\n"), string(content))
+
+	// Test case 3: Regenerating the review updates the footer without duplicating it
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n14:00 Planned the next sprint\n"), 0644)
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), DefaultReviewOptions())
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Equal(t, 1, strings.Count(string(content), "<!-- logbook-footer -->"))
+	assert.Equal(t, 1, strings.Count(string(content), "*Week total:"))
+	assert.Contains(t, string(content), "*Week total: 4 entries, 13 words, 3 days active*")
+
+	// Test case 4: An existing footer is replaced
+	assert.Equal(t, "# Title\n\nBody\n\n<!-- logbook-footer -->\nnew\n", replaceFooter("# Title\n\nBody\n\n<!-- logbook-footer -->\nold\n", "new"))
+
+	// Test case 5: No footer without IncludeFooter
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "Week total")
+}
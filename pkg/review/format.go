@@ -0,0 +1,101 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Supported values of ReviewOptions.OutputFormat.
+const (
+	OutputFormatMarkdown = "md"
+	OutputFormatHTML     = "html"
+	OutputFormatJSON     = "json"
+)
+
+// ReviewData is the content of a review, independent of its output format.
+type ReviewData struct {
+	Title          string         `json:"title"`
+	Period         string         `json:"period"`     // e.g. "Week 38, 2025"
+	StartDate      string         `json:"start_date"` // As YYYY-MM-DD
+	EndDate        string         `json:"end_date"`   // As YYYY-MM-DD, included
+	Summary        string         `json:"summary"`
+	DailySummaries []DailySummary `json:"daily_summaries"`
+}
+
+// DailySummary is the summary of a daily journal file in a review.
+type DailySummary struct {
+	Date    string `json:"date"` // As YYYY-MM-DD
+	Summary string `json:"summary"`
+	Log     string `json:"log,omitempty"` // LOG chapter of the daily file, with ReviewOptions.IncludeFullLog
+	Related string `json:"-"`             // RelatedEntriesSection of the daily file, with ReviewOptions.IncludeRelated
+}
+
+// WeeklyReviewToMarkdown returns the weekly review as Markdown, in the format of the review files.
+func WeeklyReviewToMarkdown(data *ReviewData) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n", data.Title))
+	if data.Summary != "" {
+		sb.WriteString(data.Summary + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(dailySummariesMarkdown(data.DailySummaries))
+	return sb.String()
+}
+
+// dailySummariesMarkdown returns the "Daily Summaries" section of the weekly review.
+func dailySummariesMarkdown(days []DailySummary) string {
+	if len(days) == 0 {
+		return "No journal entries found for this week.\n\n"
+	}
+	var sb strings.Builder
+	sb.WriteString("## Daily Summaries\n\n")
+	for _, day := range days {
+		sb.WriteString(fmt.Sprintf("### %s\n%s\n\n", day.Date, day.Summary))
+		sb.WriteString(fullLogMarkdown(day.Log, ""))
+		sb.WriteString(day.Related)
+	}
+	return sb.String()
+}
+
+// WeeklyReviewToJSON returns the weekly review as an indented JSON object.
+func WeeklyReviewToJSON(data *ReviewData) ([]byte, error) {
+	out := *data
+	if out.DailySummaries == nil {
+		out.DailySummaries = []DailySummary{} // An empty array rather than null
+	}
+	content, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode review to JSON: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// weeklyReviewHTMLTemplate is the page of WeeklyReviewToHTML.
+var weeklyReviewHTMLTemplate = template.Must(template.New("review").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Summary}}<p class="summary">{{.Summary}}</p>
+{{end}}{{if .DailySummaries}}<h2>Daily Summaries</h2>
+{{range .DailySummaries}}<h3>{{.Date}}</h3>
+<p>{{.Summary}}</p>
+{{if .Log}}<pre class="log">{{.Log}}</pre>
+{{end}}{{end}}{{else}}<p>No journal entries found for this week.</p>
+{{end}}</body>
+</html>
+`))
+
+// WeeklyReviewToHTML returns the weekly review as a standalone HTML page.
+func WeeklyReviewToHTML(data *ReviewData) (string, error) {
+	var sb strings.Builder
+	if err := weeklyReviewHTMLTemplate.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render review to HTML: %w", err)
+	}
+	return sb.String(), nil
+}
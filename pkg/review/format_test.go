@@ -0,0 +1,110 @@
+package review
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyReviewFormats(t *testing.T) {
+	data := &ReviewData{
+		Title:     "Weekly Review - Week 38, 2025",
+		Period:    "Week 38, 2025",
+		StartDate: "2025-09-15",
+		EndDate:   "2025-09-21",
+		Summary:   "A release week.",
+		DailySummaries: []DailySummary{
+			{Date: "2025-09-15", Summary: "Released v2."},
+			{Date: "2025-09-17", Summary: "Fixed the <hotfix> & more."},
+		},
+	}
+
+	// Test case 1: Markdown, in the format of the review files
+	assert.Equal(t, "# Weekly Review - Week 38, 2025\nA release week.\n\n## Daily Summaries\n\n"+
+		"### 2025-09-15\nReleased v2.\n\n### 2025-09-17\nFixed the <hotfix> & more.\n\n", WeeklyReviewToMarkdown(data))
+
+	// Test case 2: JSON object with the daily summaries as an array
+	content, err := WeeklyReviewToJSON(data)
+	assert.NoError(t, err)
+	var decoded map[string]any
+	assert.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, "Weekly Review - Week 38, 2025", decoded["title"])
+	assert.Equal(t, "Week 38, 2025", decoded["period"])
+	assert.Equal(t, "2025-09-15", decoded["start_date"])
+	assert.Equal(t, "2025-09-21", decoded["end_date"])
+	assert.Equal(t, "A release week.", decoded["summary"])
+	assert.Equal(t, []any{
+		map[string]any{"date": "2025-09-15", "summary": "Released v2."},
+		map[string]any{"date": "2025-09-17", "summary": "Fixed the <hotfix> & more."},
+	}, decoded["daily_summaries"])
+
+	// Test case 3: HTML page with escaped text
+	page, err := WeeklyReviewToHTML(data)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>\n"))
+	assert.Contains(t, page, "<title>Weekly Review - Week 38, 2025</title>")
+	assert.Contains(t, page, "<h1>Weekly Review - Week 38, 2025</h1>\n<p class=\"summary\">A release week.</p>\n<h2>Daily Summaries</h2>\n")
+	assert.Contains(t, page, "<h3>2025-09-15</h3>\n<p>Released v2.</p>\n")
+	assert.Contains(t, page, "<h3>2025-09-17</h3>\n<p>Fixed the &lt;hotfix&gt; &amp; more.</p>\n")
+
+	// Test case 4: Week without entries
+	empty := &ReviewData{Title: "Weekly Review - Week 1, 2025"}
+	assert.Equal(t, "# Weekly Review - Week 1, 2025\n\nNo journal entries found for this week.\n\n", WeeklyReviewToMarkdown(empty))
+	content, err = WeeklyReviewToJSON(empty)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"daily_summaries": []`)
+	page, err = WeeklyReviewToHTML(empty)
+	assert.NoError(t, err)
+	assert.Contains(t, page, "<p>No journal entries found for this week.</p>")
+	assert.NotContains(t, page, "Daily Summaries")
+}
+
+func TestReviewWeekOutputFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\nReleased v2.\n\n# LOG\n09:00 Released v2\n"), 0644)
+	summarizer := &ai.MockAISummarizer{Summary: "A release week."}
+
+	// Test case 1: The JSON review is written next to the Markdown one
+	result, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{OutputFormat: OutputFormatJSON})
+	assert.NoError(t, err)
+	assert.Contains(t, result, filepath.Join(tmpDir, "review_week_2025_38.json"))
+	assert.FileExists(t, filepath.Join(tmpDir, "review_week_2025_38.md"))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.json"))
+	assert.NoError(t, err)
+	var data ReviewData
+	assert.NoError(t, json.Unmarshal(content, &data))
+	assert.Equal(t, ReviewData{
+		Title:          "Weekly Review - Week 38, 2025",
+		Period:         "Week 38, 2025",
+		StartDate:      "2025-09-15",
+		EndDate:        "2025-09-21",
+		Summary:        "A release week.",
+		DailySummaries: []DailySummary{{Date: "2025-09-15", Summary: "Released v2."}},
+	}, data)
+	meta, err := ReadSidecar(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err, "the JSON review does not replace the sidecar file")
+	assert.Equal(t, "week", meta.Type)
+	assert.Equal(t, 38, meta.Week)
+	assert.Equal(t, "A release week.", meta.Summary)
+
+	// Test case 2: HTML review
+	result, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{OutputFormat: OutputFormatHTML})
+	assert.NoError(t, err)
+	assert.Contains(t, result, filepath.Join(tmpDir, "review_week_2025_38.html"))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "<h3>2025-09-15</h3>\n<p>Released v2.</p>")
+
+	// Test case 3: Unknown format
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{OutputFormat: "pdf"})
+	assert.ErrorContains(t, err, "unknown output format: pdf")
+}
@@ -0,0 +1,26 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/stats"
+)
+
+// habitsSection renders the completion of the habits, in the given order, as a Markdown table.
+func habitsSection(habits []string, completion map[string]stats.HabitStat) string {
+	var sb strings.Builder
+	sb.WriteString("## Habit Tracker\n\n")
+	if len(habits) == 0 {
+		sb.WriteString("No habits configured.\n\n")
+		return sb.String()
+	}
+	sb.WriteString("| Habit | Days | Completion |\n")
+	sb.WriteString("|-------|------|------------|\n")
+	for _, habit := range habits {
+		stat := completion[habit]
+		sb.WriteString(fmt.Sprintf("| %s | %d/%d | %.0f%% |\n", habit, stat.DaysCompleted, stat.TotalDays, stat.Percentage))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewMonthHabits(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.Habits = []string{"exercise", "meditation"}
+
+	// Exercise on 15 of the 30 days of September 2025
+	for d := 1; d <= 30; d++ {
+		entry := "09:00 Worked on the release"
+		if d%2 == 0 {
+			entry = "07:00 Exercise before work"
+		}
+		content := fmt.Sprintf("# Sep %02d 2025\nA day.\n\n# LOG\n%s\n", d, entry)
+		os.WriteFile(filepath.Join(tmpDir, fmt.Sprintf("2025-09-%02d.md", d)), []byte(content), 0644)
+	}
+	summarizer := &ai.MockAISummarizer{Summary: "Monthly summary."}
+	reviewFile := filepath.Join(tmpDir, "review_month_September_2025.md")
+
+	// Test case 1: No habit tracker by default
+	_, err := ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "## Habit Tracker")
+
+	// Test case 2: The completion of each habit, the ones never mentioned at 0%
+	os.Remove(reviewFile)
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeHabits: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "## Habit Tracker\n\n"+
+		"| Habit | Days | Completion |\n"+
+		"|-------|------|------------|\n"+
+		"| exercise | 15/30 | 50% |\n"+
+		"| meditation | 0/30 | 0% |\n\n")
+
+	// Test case 3: No habits configured
+	assert.Equal(t, "## Habit Tracker\n\nNo habits configured.\n\n", habitsSection(nil, nil))
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+	"github.com/clobrano/LogBook/pkg/theme"
+)
+
+// weeklyInsight asks the AI for the single most important thing of the LOG entries of the given files,
+// with cfg.WeeklyInsightPrompt, and returns the first sentence of the answer.
+// Returns an empty string without a summarizer or entries.
+func weeklyInsight(cfg *config.Config, journalFiles []string, summarizer ai.AISummarizer) (string, error) {
+	if summarizer == nil {
+		fmt.Println(theme.Warning("No AI agent configured, skipping the key insight."))
+		return "", nil
+	}
+
+	var entries []string
+	for _, filePath := range journalFiles {
+		logEntries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range logEntries {
+			entries = append(entries, entry.Text)
+		}
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	answer, err := summarizer.GenerateSummary(context.Background(), strings.Join(limitToTokens(entries, cfg.AIMaxContextTokens), "\n"), cfg.WeeklyInsightPrompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key insight with AI: %w", err)
+	}
+	return ai.FirstSentence(answer), nil
+}
+
+// insertInsight inserts the insight as a block-quote right after the title line of the review.
+func insertInsight(content, insight string) string {
+	if insight == "" {
+		return content
+	}
+	title, rest, _ := strings.Cut(content, "\n")
+	return title + "\n> 💡 " + insight + "\n\n" + strings.TrimLeft(rest, "\n")
+}
@@ -0,0 +1,50 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyInsight(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\n# LOG\n10:00 Released v2\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\n# LOG\n10:00 Fixed the hotfix\n"), 0644)
+	reviewFile := filepath.Join(tmpDir, "review_week_2025_38.md")
+
+	// Test case 1: No insight by default
+	summarizer := &ai.RecordingMockSummarizer{Summary: "We shipped v2. It took two sprints."}
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "> 💡")
+	assert.Len(t, summarizer.Calls, 1)
+
+	// Test case 2: The insight is the first sentence of the answer, right after the title
+	os.Remove(reviewFile)
+	summarizer = &ai.RecordingMockSummarizer{Summary: "We shipped v2. It took two sprints."}
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeInsight: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.True(t, strings.HasPrefix(string(content), "# Weekly Review - Week 38, 2025\n> 💡 We shipped v2.\n\nWe shipped v2. It took two sprints.\n\n"), string(content))
+	if assert.Len(t, summarizer.Calls, 2) {
+		assert.Equal(t, cfg.WeeklyInsightPrompt, summarizer.Calls[1].Prompt)
+		assert.Equal(t, "Released v2\nFixed the hotfix", summarizer.Calls[1].Text)
+	}
+
+	// Test case 3: The insight goes above the navigation links
+	os.Remove(reviewFile)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_37.md"), []byte("# Weekly Review - Week 37, 2025\n"), 0644)
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeInsight: true, LinkedNavigation: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.True(t, strings.HasPrefix(string(content), "# Weekly Review - Week 38, 2025\n> 💡 We shipped v2.\n\n← [Week 37, 2025](review_week_2025_37.md)\n\n"), string(content))
+}
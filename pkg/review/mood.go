@@ -0,0 +1,59 @@
+package review
+
+import (
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// noMoodPlaceholder is shown in the mood timeline for the days without moods.
+const noMoodPlaceholder = "  ─  "
+
+// moodTimelineDays are the days of the mood timeline, in ISO week order.
+var moodTimelineDays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// MoodTimeline renders the moods of the given journal files of a week as a single line, e.g. "Mon 😊 Tue 😐 Wed 😔".
+// Each day shows its last mood. The timeline covers Config.WorkingDaysPerWeek days, plus the weekend days with a mood.
+func MoodTimeline(cfg *config.Config, files []string) (string, error) {
+	var emotions []journal.Emotion
+	for _, filePath := range files {
+		fileEmotions, err := journal.ExtractEmotions(cfg, filePath)
+		if err != nil {
+			return "", err
+		}
+		emotions = append(emotions, fileEmotions...)
+	}
+
+	// Emotions are chronological within a file, the last one of each day wins
+	lastMood := make(map[time.Weekday]string)
+	lastTime := make(map[time.Weekday]time.Time)
+	for _, emotion := range emotions {
+		day := emotion.Time.Weekday()
+		if t, ok := lastTime[day]; !ok || !emotion.Time.Before(t) {
+			lastMood[day] = emotion.Mood
+			lastTime[day] = emotion.Time
+		}
+	}
+
+	var parts []string
+	for i, day := range moodTimelineDays {
+		mood, ok := lastMood[day]
+		if i >= cfg.WorkingDaysPerWeek && !ok {
+			continue
+		}
+		if !ok {
+			mood = noMoodPlaceholder
+		}
+		parts = append(parts, day.String()[:3]+" "+mood)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// moodTimelineSection renders the mood timeline as a Markdown section.
+func moodTimelineSection(timeline string) string {
+	return "## Mood Timeline\n\n" + timeline + "\n\n"
+}
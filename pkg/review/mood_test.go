@@ -0,0 +1,71 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoodTimeline(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(name, log string) string {
+		filePath := filepath.Join(tmpDir, name)
+		os.WriteFile(filePath, []byte("# "+name+"\nSummary.\n\n# LOG\n"+log), 0644)
+		return filePath
+	}
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	files := []string{
+		writeFile("2025-09-15.md", "09:00 Kickoff mood:happy\n"),
+		writeFile("2025-09-16.md", "09:00 Standup mood:happy\n12:00 Outage mood:sad\n16:00 Fixed it mood:neutral\n"),
+		writeFile("2025-09-17.md", "10:00 Deep work without mood\n"),
+		writeFile("2025-09-19.md", "17:00 Release mood:great\n"),
+	}
+
+	// Test case 1: Every working day is listed, the last mood of each day is shown and days without moods have a placeholder
+	timeline, err := MoodTimeline(cfg, files)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mon 😊 Tue 😐 Wed   ─   Thu   ─   Fri 🎉", timeline)
+	for _, label := range []string{"Mon", "Tue", "Wed", "Thu", "Fri"} {
+		assert.Contains(t, timeline, label)
+	}
+	assert.NotContains(t, timeline, "😔")
+
+	// Test case 2: Entries prepended newest first still show the most recent mood
+	files[1] = writeFile("2025-09-16.md", "16:00 Fixed it mood:neutral\n12:00 Outage mood:sad\n")
+	timeline, err = MoodTimeline(cfg, files)
+	assert.NoError(t, err)
+	assert.Contains(t, timeline, "Tue 😐")
+
+	// Test case 3: Weekend days are shown when they have a mood or when the week has 7 working days
+	files = append(files, writeFile("2025-09-21.md", "11:00 Hiking mood:😎\n"))
+	timeline, err = MoodTimeline(cfg, files)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mon 😊 Tue 😐 Wed   ─   Thu   ─   Fri 🎉 Sun 😎", timeline)
+	cfg.WorkingDaysPerWeek = 7
+	timeline, err = MoodTimeline(cfg, files)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mon 😊 Tue 😐 Wed   ─   Thu   ─   Fri 🎉 Sat   ─   Sun 😎", timeline)
+	cfg.WorkingDaysPerWeek = 5
+
+	// Test case 4: The weekly review has the section only when moods are enabled and the timeline is requested
+	summarizer := &ai.MockAISummarizer{Summary: "Weekly summary."}
+	reviewFile := filepath.Join(tmpDir, "review_week_2025_38.md")
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeMoodTimeline: true})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "## Mood Timeline")
+
+	cfg.Sections = map[string]bool{config.SectionMood: true}
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeMoodTimeline: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "## Mood Timeline\n\nMon 😊 Tue 😐 Wed   ─   Thu   ─   Fri 🎉 Sun 😎\n\n")
+}
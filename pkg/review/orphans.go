@@ -0,0 +1,141 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// ReviewFile is a review file found in the review directory, with the period it covers.
+type ReviewFile struct {
+	Path      string
+	Type      string // One of "week", "month", "quarter", "year" or "custom"
+	StartDate time.Time
+	EndDate   time.Time
+	Meta      *ReviewMeta // Metadata of the sidecar file, nil without one
+}
+
+// Patterns of the names of the review files, see weekReviewFileName, monthReviewFileName, quarterReviewFileName,
+// yearReviewFileName and customReviewFileName.
+var (
+	weekReviewPattern    = regexp.MustCompile(`^review_week_(\d+)_(\d+)\.md$`)
+	monthReviewPattern   = regexp.MustCompile(`^review_month_([A-Za-z]+)_(\d+)\.md$`)
+	quarterReviewPattern = regexp.MustCompile(`^review_quarter_Q([1-4])_(\d+)\.md$`)
+	yearReviewPattern    = regexp.MustCompile(`^review_year_(\d+)\.md$`)
+	customReviewPattern  = regexp.MustCompile(`^review_custom_(\d{4}-\d{2}-\d{2})_(\d{4}-\d{2}-\d{2})\.md$`)
+)
+
+// ListReviews returns the weekly, monthly, quarterly, yearly and custom review files of the review directory, sorted by file name.
+// The period of a review comes from its sidecar file, see ReadSidecar, or else from its file name.
+// Files whose name looks like a review but does not identify a valid period are skipped.
+func ListReviews(cfg *config.Config) ([]ReviewFile, error) {
+	paths, err := filepath.Glob(filepath.Join(reviewDir(cfg), "review_*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review files: %w", err)
+	}
+
+	var reviews []ReviewFile
+	for _, path := range paths {
+		if meta, err := ReadSidecar(path); err == nil {
+			if review, ok := reviewFileFromMeta(path, meta); ok {
+				reviews = append(reviews, review)
+				continue
+			}
+		}
+		if review, ok := parseReviewFileName(path); ok {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+// parseReviewFileName returns the review identified by the name of a review file.
+func parseReviewFileName(path string) (ReviewFile, bool) {
+	name := filepath.Base(path)
+	if m := weekReviewPattern.FindStringSubmatch(name); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		if week < 1 || week > 53 {
+			return ReviewFile{}, false
+		}
+		start := isoWeekStart(week, year)
+		return ReviewFile{Path: path, Type: "week", StartDate: start, EndDate: start.AddDate(0, 0, 6)}, true
+	}
+	if m := monthReviewPattern.FindStringSubmatch(name); m != nil {
+		month, err := time.Parse("January", m[1])
+		if err != nil {
+			return ReviewFile{}, false
+		}
+		year, _ := strconv.Atoi(m[2])
+		start := time.Date(year, month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "month", StartDate: start, EndDate: start.AddDate(0, 1, -1)}, true
+	}
+	if m := quarterReviewPattern.FindStringSubmatch(name); m != nil {
+		quarter, _ := strconv.Atoi(m[1])
+		year, _ := strconv.Atoi(m[2])
+		start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "quarter", StartDate: start, EndDate: start.AddDate(0, 3, -1)}, true
+	}
+	if m := yearReviewPattern.FindStringSubmatch(name); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "year", StartDate: start, EndDate: start.AddDate(1, 0, -1)}, true
+	}
+	if m := customReviewPattern.FindStringSubmatch(name); m != nil {
+		start, startErr := time.Parse("2006-01-02", m[1])
+		end, endErr := time.Parse("2006-01-02", m[2])
+		if startErr != nil || endErr != nil || end.Before(start) {
+			return ReviewFile{}, false
+		}
+		return ReviewFile{Path: path, Type: "custom", StartDate: start, EndDate: end}, true
+	}
+	return ReviewFile{}, false
+}
+
+// ListOrphanedReviewFiles returns the review files whose period has no journal files left, e.g. after deleting old journal files.
+func ListOrphanedReviewFiles(cfg *config.Config) ([]string, error) {
+	reviews, err := ListReviews(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []string
+	for _, review := range reviews {
+		files, err := journal.ListJournalFilesByPeriod(cfg, review.StartDate, review.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list journal files for %s: %w", review.Path, err)
+		}
+		if len(files) == 0 {
+			orphaned = append(orphaned, review.Path)
+		}
+	}
+	return orphaned, nil
+}
+
+// DeleteOrphanedReviews deletes the review files returned by ListOrphanedReviewFiles and returns their paths.
+// Their sidecar files are deleted too. With dryRun the files are only listed.
+func DeleteOrphanedReviews(cfg *config.Config, dryRun bool) ([]string, error) {
+	orphaned, err := ListOrphanedReviewFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return orphaned, nil
+	}
+
+	for _, path := range orphaned {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned review %s: %w", path, err)
+		}
+		if err := os.Remove(sidecarPath(path)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to delete the metadata of orphaned review %s: %w", path, err)
+		}
+	}
+	return orphaned, nil
+}
@@ -0,0 +1,75 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOrphanedReviewFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(name string) string {
+		path := filepath.Join(tmpDir, name)
+		os.WriteFile(path, []byte("# "+name+"\n"), 0644)
+		return path
+	}
+	// Journal entries only in week 38 (September 2025)
+	writeFile("2025-09-16.md")
+
+	week38 := writeFile("review_week_2025_38.md")
+	week37 := writeFile("review_week_2025_37.md")
+	september := writeFile("review_month_September_2025.md")
+	august := writeFile("review_month_August_2025.md")
+	year2025 := writeFile("review_year_2025.md")
+	year2024 := writeFile("review_year_2024.md")
+	writeFile("review_notes.md") // Not a review of a period
+
+	// Test case 1: ListReviews parses the period of each review
+	reviews, err := ListReviews(cfg)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 6)
+	for _, review := range reviews {
+		if review.Path == week38 {
+			assert.Equal(t, "week", review.Type)
+			assert.Equal(t, time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC), review.StartDate)
+			assert.Equal(t, time.Date(2025, time.September, 21, 0, 0, 0, 0, time.UTC), review.EndDate)
+		}
+		if review.Path == august {
+			assert.Equal(t, "month", review.Type)
+			assert.Equal(t, time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC), review.EndDate)
+		}
+	}
+
+	// Test case 2: Only the reviews of periods without journal files are orphaned
+	orphaned, err := ListOrphanedReviewFiles(cfg)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{week37, august, year2024}, orphaned)
+
+	// Test case 3: A dry run lists the orphaned reviews without deleting them
+	deleted, err := DeleteOrphanedReviews(cfg, true)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{week37, august, year2024}, deleted)
+	assert.FileExists(t, week37)
+
+	// Test case 4: The orphaned reviews are deleted, the others are kept
+	deleted, err = DeleteOrphanedReviews(cfg, false)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{week37, august, year2024}, deleted)
+	assert.NoFileExists(t, week37)
+	assert.NoFileExists(t, august)
+	assert.NoFileExists(t, year2024)
+	assert.FileExists(t, week38)
+	assert.FileExists(t, september)
+	assert.FileExists(t, year2025)
+
+	orphaned, err = ListOrphanedReviewFiles(cfg)
+	assert.NoError(t, err)
+	assert.Empty(t, orphaned)
+}
@@ -0,0 +1,101 @@
+package review
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// Weights of the components of the productivity score.
+const (
+	productivityDaysWeight   = 0.4
+	productivityWordsWeight  = 0.4
+	productivityStreakWeight = 0.2
+)
+
+// maxStreakWeeks is the number of consecutive weeks with entries giving the full streak component.
+const maxStreakWeeks = 5
+
+// productivityBarWidth is the number of characters of the bar rendered by productivitySection.
+const productivityBarWidth = 10
+
+// ProductivityScore rates the given ISO week from 0.0 to 1.0, rounded to two decimal places.
+// 40% of the score is the number of days with entries out of Config.WorkingDaysPerWeek,
+// 40% is the number of words written out of Config.DefaultWordGoal
+// and 20% is the streak of consecutive weeks with entries, up to this week, where each week adds a fifth.
+func ProductivityScore(cfg *config.Config, week, year int) (float64, error) {
+	days, words, err := weekActivity(cfg, week, year)
+	if err != nil {
+		return 0, err
+	}
+	if days == 0 {
+		return 0, nil
+	}
+
+	daysComponent := math.Min(float64(days)/float64(cfg.WorkingDaysPerWeek), 1)
+
+	wordsComponent := 1.0
+	if cfg.DefaultWordGoal > 0 {
+		wordsComponent = math.Min(float64(words)/float64(cfg.DefaultWordGoal), 1)
+	}
+
+	streak, err := weekStreak(cfg, week, year)
+	if err != nil {
+		return 0, err
+	}
+	streakComponent := float64(streak) / maxStreakWeeks
+
+	score := productivityDaysWeight*daysComponent + productivityWordsWeight*wordsComponent + productivityStreakWeight*streakComponent
+	return math.Round(score*100) / 100, nil
+}
+
+// weekActivity returns the number of days with entries of the given ISO week and the number of words written.
+func weekActivity(cfg *config.Config, week, year int) (int, int, error) {
+	startDate := isoWeekStart(week, year)
+	files, err := journal.ListJournalFilesByPeriod(cfg, startDate, startDate.AddDate(0, 0, 6))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list journal files of week %d, %d: %w", week, year, err)
+	}
+
+	days, words := 0, 0
+	for _, file := range files {
+		count, err := journal.CountWords(cfg, file)
+		if err != nil {
+			return 0, 0, err
+		}
+		if count > 0 {
+			days++
+			words += count
+		}
+	}
+	return days, words, nil
+}
+
+// weekStreak returns the number of consecutive weeks with entries ending with the given ISO week, up to maxStreakWeeks.
+func weekStreak(cfg *config.Config, week, year int) (int, error) {
+	date := isoWeekStart(week, year)
+	streak := 0
+	for streak < maxStreakWeeks {
+		year, week := date.ISOWeek()
+		days, _, err := weekActivity(cfg, week, year)
+		if err != nil {
+			return 0, err
+		}
+		if days == 0 {
+			break
+		}
+		streak++
+		date = date.AddDate(0, 0, -7)
+	}
+	return streak, nil
+}
+
+// productivitySection renders the productivity score as a percentage followed by a bar, e.g. "Productivity: 78% ████████░░".
+func productivitySection(score float64) string {
+	filled := int(math.Round(score * productivityBarWidth))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", productivityBarWidth-filled)
+	return fmt.Sprintf("Productivity: %d%% %s\n\n", int(math.Round(score*100)), bar)
+}
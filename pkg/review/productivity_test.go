@@ -0,0 +1,111 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductivityScore(t *testing.T) {
+	newConfig := func() *config.Config {
+		cfg := config.DefaultConfig()
+		cfg.JournalDir = t.TempDir()
+		cfg.WorkingDaysPerWeek = 5
+		cfg.DefaultWordGoal = 500
+		return cfg
+	}
+	writeWords := func(cfg *config.Config, date time.Time, words int) {
+		content := "# " + date.Format("Jan 02 2006") + "\n\n# LOG\n" + strings.Repeat("word ", words) + "\n"
+		os.WriteFile(filepath.Join(cfg.JournalDir, date.Format("2006-01-02")+".md"), []byte(content), 0644)
+	}
+	// writeWorkingWeek writes 100 words on each working day of the week starting on monday
+	writeWorkingWeek := func(cfg *config.Config, monday time.Time) {
+		for i := 0; i < 5; i++ {
+			writeWords(cfg, monday.AddDate(0, 0, i), 100)
+		}
+	}
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	monday := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+
+	// Test case 1: A perfect week, with all the working days, the word goal met and the longest streak
+	cfg := newConfig()
+	writeWorkingWeek(cfg, monday)
+	for weeksAgo := 1; weeksAgo < maxStreakWeeks; weeksAgo++ {
+		writeWords(cfg, monday.AddDate(0, 0, -7*weeksAgo), 10)
+	}
+	score, err := ProductivityScore(cfg, 38, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, score)
+
+	// Test case 2: A week without entries
+	cfg = newConfig()
+	writeWorkingWeek(cfg, monday.AddDate(0, 0, -7))
+	score, err = ProductivityScore(cfg, 38, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, score)
+
+	// Test case 3: Each additional consecutive week adds 0.2 to the streak component, that is 0.04 to the score
+	cfg = newConfig()
+	writeWorkingWeek(cfg, monday)
+	expected := []float64{0.84, 0.88, 0.92}
+	for weeksAgo, want := range expected {
+		if weeksAgo > 0 {
+			writeWords(cfg, monday.AddDate(0, 0, -7*weeksAgo), 10)
+		}
+		streak, err := weekStreak(cfg, 38, 2025)
+		assert.NoError(t, err)
+		assert.Equal(t, weeksAgo+1, streak)
+		score, err = ProductivityScore(cfg, 38, 2025)
+		assert.NoError(t, err)
+		assert.Equal(t, want, score)
+	}
+
+	// Test case 4: A gap breaks the streak
+	writeWords(cfg, monday.AddDate(0, 0, -28), 10)
+	streak, err := weekStreak(cfg, 38, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, streak)
+
+	// Test case 5: Fractional scores are rounded to two decimal places
+	// 0.4*1/5 + 0.4*37/500 + 0.2*1/5 = 0.1496
+	cfg = newConfig()
+	writeWords(cfg, monday.AddDate(0, 0, 2), 37)
+	score, err = ProductivityScore(cfg, 38, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.15, score)
+
+	// Test case 6: Days are counted out of 7 when the weekend is a working day
+	cfg.WorkingDaysPerWeek = 7
+	cfg.DefaultWordGoal = 0 // The word goal is ignored
+	// 0.4*1/7 + 0.4 + 0.2*1/5 = 0.497
+	score, err = ProductivityScore(cfg, 38, 2025)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, score)
+
+	// Test case 7: The weekly review shows the score when requested
+	cfg = newConfig()
+	writeWorkingWeek(cfg, monday)
+	_, err = ReviewWeek(cfg, 38, 2025, &ai.MockAISummarizer{Summary: "Weekly summary."}, strings.NewReader(""), ReviewOptions{ShowProductivityScore: true})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(cfg.JournalDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Productivity: 84% ████████░░\n")
+
+	_, err = ReviewWeek(cfg, 38, 2025, &ai.MockAISummarizer{Summary: "Weekly summary."}, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(cfg.JournalDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "Productivity:")
+}
+
+func TestProductivitySection(t *testing.T) {
+	assert.Equal(t, "Productivity: 78% ████████░░\n\n", productivitySection(0.78))
+	assert.Equal(t, "Productivity: 0% ░░░░░░░░░░\n\n", productivitySection(0))
+	assert.Equal(t, "Productivity: 100% ██████████\n\n", productivitySection(1))
+}
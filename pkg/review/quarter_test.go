@@ -0,0 +1,66 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewQuarter(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-06-30.md"), []byte("# Jun 30 2025\n\nPlanning.\n\n# LOG\n09:00 Planned Q3\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-07-01.md"), []byte("# Jul 1 2025\n\nKick-off.\n\n# LOG\n09:00 Kick-off\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-07-15.md"), []byte("# Jul 15 2025\n\nDemo.\n\n# LOG\n09:00 Demo\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-30.md"), []byte("# Sep 30 2025\n\nRelease.\n\n# LOG\n09:00 Release\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-10-01.md"), []byte("# Oct 1 2025\n\nNext quarter.\n\n# LOG\n09:00 Q4\n"), 0644)
+	reviewFile := filepath.Join(tmpDir, "review_quarter_Q3_2025.md")
+
+	// Test case 1: The daily summaries of the quarter only, grouped by month
+	summarizer := &ai.RecordingMockSummarizer{Summary: "A quarter of releases."}
+	result, err := ReviewQuarter(cfg, 3, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, result, reviewFile)
+	content, _ := os.ReadFile(reviewFile)
+	assert.Equal(t, "# Quarterly Review - Q3 2025\nA quarter of releases.\n\n## Daily Summaries\n\n"+
+		"### July\n\n#### 2025-07-01\nKick-off.\n\n#### 2025-07-15\nDemo.\n\n"+
+		"### September\n\n#### 2025-09-30\nRelease.\n\n", string(content))
+	if assert.Len(t, summarizer.Calls, 1) {
+		assert.Contains(t, summarizer.Calls[0].Prompt, "quarterly review")
+	}
+
+	// Test case 2: The sidecar and ListReviews know the quarter
+	meta, err := ReadSidecar(reviewFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "quarter", meta.Type)
+		assert.Equal(t, 3, meta.Quarter)
+		assert.Equal(t, 3, meta.EntryCount)
+	}
+	os.Remove(filepath.Join(tmpDir, "review_quarter_Q3_2025.meta.json"))
+	reviews, err := ListReviews(cfg)
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 1) {
+		assert.Equal(t, "quarter", reviews[0].Type)
+		assert.Equal(t, time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), reviews[0].StartDate)
+		assert.Equal(t, time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC), reviews[0].EndDate)
+	}
+
+	// Test case 3: The first quarter goes from January to March
+	_, err = ReviewQuarter(cfg, 1, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(filepath.Join(tmpDir, "review_quarter_Q1_2025.md"))
+	assert.Contains(t, string(content), "No journal entries found for this quarter.")
+
+	// Test case 4: Invalid quarters
+	for _, quarter := range []int{0, 5, -1} {
+		_, err = ReviewQuarter(cfg, quarter, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+		assert.ErrorContains(t, err, "expected a number from 1 to 4")
+	}
+}
@@ -0,0 +1,68 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// RelatedEntriesSection renders the days related to a journal file with journal.AddRelation as a Markdown section,
+// nested below the summary of the day in the weekly review with ReviewOptions.IncludeRelated.
+// Each day is listed with its summary or, if it has none, its first log entry.
+// Returns an empty string if the file has no relations.
+func RelatedEntriesSection(cfg *config.Config, filePath string) (string, error) {
+	relations, err := journal.ExtractRelations(filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(relations) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#### Related Entries\n\n")
+	for _, related := range relations {
+		snippet, err := relatedSnippet(cfg, related)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("- [[%s]]: %s\n", related, snippet))
+	}
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// relatedSnippet returns a short description of the daily note of the given YYYY-MM-DD date.
+func relatedSnippet(cfg *config.Config, related string) (string, error) {
+	date, err := time.Parse("2006-01-02", related)
+	if err != nil {
+		return "", fmt.Errorf("invalid related date %q: %w", related, err)
+	}
+	filePath, err := journal.DailyFilePath(cfg, date)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return "missing", nil
+	}
+
+	summary, err := journal.ExtractSummaryFull(filePath)
+	if err != nil {
+		return "", err
+	}
+	if summary.Short != "" {
+		return summary.Short, nil
+	}
+	entries, err := journal.ExtractLogEntries(cfg, filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "no entries", nil
+	}
+	return strings.ReplaceAll(entries[0].Text, "\n", " "), nil
+}
@@ -0,0 +1,61 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelatedEntriesSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	summaryFile := filepath.Join(tmpDir, "2025-09-15.md")
+	noSummaryFile := filepath.Join(tmpDir, "2025-09-16.md")
+	todayFile := filepath.Join(tmpDir, "2025-09-18.md")
+	os.WriteFile(summaryFile, []byte("# Sep 15 2025\nStarted the database migration.\n\n# LOG\n09:00 Wrote the migration script\n"), 0644)
+	os.WriteFile(noSummaryFile, []byte("# Sep 16 2025\n\n# LOG\n10:00 Tested the migration on staging\n"), 0644)
+	os.WriteFile(todayFile, []byte("# Sep 18 2025\n\n# LOG\n11:00 Migrated production\n"), 0644)
+
+	// Test case 1: No relations
+	section, err := RelatedEntriesSection(cfg, todayFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "", section)
+
+	// Test case 2: Related days are listed with their summary, or their first entry
+	assert.NoError(t, journal.AddRelation(cfg, todayFile, "2025-09-15"))
+	assert.NoError(t, journal.AddRelation(cfg, todayFile, "2025-09-16"))
+	section, err = RelatedEntriesSection(cfg, todayFile)
+	assert.NoError(t, err)
+	expected := "#### Related Entries\n\n" +
+		"- [[2025-09-15]]: Started the database migration.\n" +
+		"- [[2025-09-16]]: Tested the migration on staging\n\n"
+	assert.Equal(t, expected, section)
+
+	// Test case 3: The relation is listed from the other side too
+	section, err = RelatedEntriesSection(cfg, summaryFile)
+	assert.NoError(t, err)
+	assert.Contains(t, section, "- [[2025-09-18]]: Migrated production\n")
+
+	// Test case 4: A related day whose file was removed
+	os.Remove(noSummaryFile)
+	section, err = RelatedEntriesSection(cfg, todayFile)
+	assert.NoError(t, err)
+	assert.Contains(t, section, "- [[2025-09-16]]: missing\n")
+
+	// Test case 5: The weekly review lists the related days below the summary of each day
+	opts := DefaultReviewOptions()
+	opts.IncludeRelated = true
+	_, err = ReviewWeek(cfg, 38, 2025, &ai.MockAISummarizer{Summary: "Migration week."}, strings.NewReader(""), opts)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-15\nStarted the database migration.\n\n#### Related Entries\n\n- [[2025-09-18]]: Migrated production\n\n")
+}
@@ -0,0 +1,89 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+)
+
+// retroPrompt is the prompt of the monthly review summary with ReviewOptions.RetroFormat.
+const retroPrompt = "Write a personal retrospective of the monthly review. Start with a summary of 200 characters or less, " +
+	"then write three sections titled \"## Start Doing\", \"## Stop Doing\" and \"## Continue Doing\", each with 2 to 4 bullet items. " +
+	"Use 1st person and a simple language."
+
+// Titles of the retrospective sections.
+const (
+	retroStartTitle    = "Start Doing"
+	retroStopTitle     = "Stop Doing"
+	retroContinueTitle = "Continue Doing"
+)
+
+// ParseRetroSections returns the bullet items of the "Start Doing", "Stop Doing" and "Continue Doing" sections of
+// an AI response. Headers of any level are recognized, case insensitively. Text outside the sections is ignored.
+// An error is returned if a section is missing.
+func ParseRetroSections(aiResponse string) (start, stop, continue_ []string, err error) {
+	sections := make(map[string][]string)
+	current := ""
+	for _, line := range strings.Split(aiResponse, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			title := strings.TrimSuffix(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), ":")
+			current = ""
+			for _, known := range []string{retroStartTitle, retroStopTitle, retroContinueTitle} {
+				if strings.EqualFold(title, known) {
+					current = known
+					sections[current] = []string{}
+				}
+			}
+			continue
+		}
+		if current == "" || !listMarkerPattern.MatchString(trimmed) {
+			continue
+		}
+		if item := strings.TrimSpace(listMarkerPattern.ReplaceAllString(trimmed, "")); item != "" {
+			sections[current] = append(sections[current], item)
+		}
+	}
+
+	for _, title := range []string{retroStartTitle, retroStopTitle, retroContinueTitle} {
+		if _, ok := sections[title]; !ok {
+			return nil, nil, nil, fmt.Errorf("missing \"## %s\" section in the retrospective", title)
+		}
+	}
+	return sections[retroStartTitle], sections[retroStopTitle], sections[retroContinueTitle], nil
+}
+
+// BuildMonthSections appends the retrospective sections to the beginning of a monthly review,
+// that is its title and overall summary, so that they come before "## Daily Summaries".
+func BuildMonthSections(head string, start, stop, continue_ []string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(head, "\n") + "\n\n")
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{retroStartTitle, start},
+		{retroStopTitle, stop},
+		{retroContinueTitle, continue_},
+	} {
+		sb.WriteString("## " + section.title + "\n\n")
+		for _, item := range section.items {
+			sb.WriteString("- " + item + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// splitRetroResponse splits a monthly review written with the retrospective prompt into its title and summary,
+// and the retrospective, that starts at the first header after the title.
+func splitRetroResponse(content string) (head, retro string) {
+	lines := strings.SplitAfter(content, "\n")
+	offset := 0
+	for i, line := range lines {
+		if i > 0 && strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return content[:offset], content[offset:]
+		}
+		offset += len(line)
+	}
+	return content, ""
+}
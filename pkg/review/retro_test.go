@@ -0,0 +1,85 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const retroResponse = `I shipped the importer and learned a lot about parsing.
+
+### Start Doing:
+- Write tests before the code
+* Plan the week on Monday
+
+## Stop Doing
+1. Checking email during focus time
+2. Skipping lunch
+
+## continue doing
+- Daily logging
+- Pairing on hard problems
+- Weekly reviews
+`
+
+func TestParseRetroSections(t *testing.T) {
+	// Test case 1: All the sections, whatever the header level, case and list markers
+	start, stop, continue_, err := ParseRetroSections(retroResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Write tests before the code", "Plan the week on Monday"}, start)
+	assert.Equal(t, []string{"Checking email during focus time", "Skipping lunch"}, stop)
+	assert.Equal(t, []string{"Daily logging", "Pairing on hard problems", "Weekly reviews"}, continue_)
+
+	// Test case 2: A missing section
+	_, _, _, err = ParseRetroSections("## Start Doing\n- Tests\n\n## Continue Doing\n- Logging\n")
+	assert.ErrorContains(t, err, "missing \"## Stop Doing\" section")
+
+	// Test case 3: Other sections and text are ignored
+	start, _, _, err = ParseRetroSections("## Start Doing\nSome text\n- Tests\n## Notes\n- Not an item\n## Stop Doing\n## Continue Doing\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tests"}, start)
+}
+
+func TestReviewMonthRetroFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\nWorked on the importer.\n\n# LOG\n09:00 Importer\n"), 0644)
+	reviewFile := filepath.Join(tmpDir, "review_month_September_2025.md")
+
+	// Test case 1: The retrospective sections follow the summary and precede the daily summaries
+	summarizer := &ai.RecordingMockSummarizer{Summary: retroResponse}
+	_, err := ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{RetroFormat: true})
+	assert.NoError(t, err)
+	assert.Len(t, summarizer.Calls, 1)
+	assert.Contains(t, summarizer.Calls[0].Prompt, "\"## Start Doing\", \"## Stop Doing\" and \"## Continue Doing\"")
+
+	content, err := os.ReadFile(reviewFile)
+	assert.NoError(t, err)
+	expected := "# Monthly Review - September 2025\n" +
+		"I shipped the importer and learned a lot about parsing.\n\n" +
+		"## Start Doing\n\n- Write tests before the code\n- Plan the week on Monday\n\n" +
+		"## Stop Doing\n\n- Checking email during focus time\n- Skipping lunch\n\n" +
+		"## Continue Doing\n\n- Daily logging\n- Pairing on hard problems\n- Weekly reviews\n\n" +
+		"## Daily Summaries\n\n### 2025-09-15\nWorked on the importer.\n\n"
+	assert.Equal(t, expected, string(content))
+
+	// Test case 2: An AI response without the sections is an error
+	summarizer = &ai.RecordingMockSummarizer{Summary: "Just a summary."}
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{RetroFormat: true})
+	assert.ErrorContains(t, err, "failed to parse retrospective for monthly review")
+
+	// Test case 3: Without the option the usual prompt is used
+	summarizer = &ai.RecordingMockSummarizer{Summary: "Just a summary."}
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, summarizer.Calls[0].Prompt, "Start Doing")
+	content, err = os.ReadFile(reviewFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "## Start Doing")
+}
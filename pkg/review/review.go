@@ -1,49 +1,133 @@
 package review
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/chart"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
 	"github.com/clobrano/LogBook/pkg/journal"
-
-	"github.com/fatih/color"
+	"github.com/clobrano/LogBook/pkg/stats"
+	"github.com/clobrano/LogBook/pkg/template"
+	"github.com/clobrano/LogBook/pkg/theme"
 )
 
-// ReviewWeek generates a weekly review file.
-func ReviewWeek(cfg *config.Config, week int, year int, summarizer ai.AISummarizer, reader io.Reader) (string, error) {
-	// Calculate start and end dates for the week using ISO week definition.
-	// Go's time.ISOWeek() returns the ISO year and ISO week number.
-	// To get the start date of a given ISO week, we can find the Thursday of that week.
-	// The Thursday of the first week of the year is always in the first week.
+// ReviewOptions holds optional settings for review generation.
+type ReviewOptions struct {
+	// LinkedNavigation adds links to the previous and next existing reviews below the title.
+	LinkedNavigation bool
+	// AILanguage is the language of the AI generated summary. Defaults to Config.DefaultAILanguage.
+	AILanguage string
+	// SortDailySummariesBy is the order of the daily summaries of the weekly review, one of the SortBy constants.
+	// Defaults to SortByDate.
+	SortDailySummariesBy string
+	// IncludeEntryGraph adds a chart of the number of entries per month to the yearly review.
+	IncludeEntryGraph bool
+	// CrossReference adds to the weekly review the phrases mentioned in the LOG of at least MinMentions days.
+	CrossReference bool
+	// MinMentions is the minimum number of days a phrase must appear in to be cross referenced. Defaults to 2.
+	MinMentions int
+	// ExtractLearnings adds to the yearly review the skills and tools mentioned in the entries, as listed by the AI.
+	ExtractLearnings bool
+	// RetroFormat writes the summary of the monthly review as a personal retrospective,
+	// with "Start Doing", "Stop Doing" and "Continue Doing" sections generated by the AI.
+	RetroFormat bool
+	// IncludeMoodTimeline adds the MoodTimeline of the week to the weekly review, if the "mood" section is enabled.
+	IncludeMoodTimeline bool
+	// ShowProductivityScore adds the ProductivityScore of the week to the weekly review.
+	ShowProductivityScore bool
+	// BestOf adds to the yearly review the longest, the most referenced and the first entries of the year.
+	BestOf bool
+	// IncludeTopTags adds to the yearly review the TopTagsCount most used hashtags.
+	IncludeTopTags bool
+	// TopTagsCount is the number of tags listed by IncludeTopTags. Defaults to 10.
+	TopTagsCount int
+	// PassPhrase decrypts the encrypted daily summaries. Without it, the files with an encrypted summary are skipped.
+	PassPhrase string
+	// IncludeInsight adds the AI generated key insight of the week below the title of the weekly review.
+	IncludeInsight bool
+	// IncludeHabits adds to the monthly review the completion of the Config.Habits.
+	// The "habit_tracker" section of the configuration adds it to every monthly review.
+	IncludeHabits bool
+	// AIProfile is the name of the Config.AIProfiles entry used instead of the given summarizer, if not empty.
+	AIProfile string
+	// IncludeFooter appends to the weekly review the total of entries, words and active days of the week.
+	// It is set by DefaultReviewOptions.
+	IncludeFooter bool
+	// OutputFormat is the format of the weekly review, one of the OutputFormat constants. Defaults to OutputFormatMarkdown.
+	// The JSON and HTML reviews are written next to the Markdown one, with the title, summary and daily summaries only.
+	OutputFormat string
+	// IncludeFullLog adds the LOG chapter of each daily file below its summary, in the weekly, monthly and yearly reviews.
+	IncludeFullLog bool
+	// IncludeWeeklyReviews adds to the monthly review the summaries of the existing weekly reviews of its weeks.
+	IncludeWeeklyReviews bool
+	// IncludeRelated lists below each daily summary of the weekly review the days linked to it with log --relate-to.
+	IncludeRelated bool
+}
 
-	// Start by finding a date in the middle of the target week to ensure we get the correct ISO week.
-	// We can pick the 4th day of the year, as ISO week 1 always contains Jan 4.
+// DefaultReviewOptions returns the ReviewOptions of logbook review without flags, that include the footer.
+// Unlike DefaultReviewOptions, the zero ReviewOptions enables none of the optional sections.
+func DefaultReviewOptions() ReviewOptions {
+	return ReviewOptions{IncludeFooter: true}
+}
 
-	dateInTargetWeek := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+// Supported values of ReviewOptions.SortDailySummariesBy.
+const (
+	SortByDate          = "date"
+	SortByWordCountDesc = "wordcount-desc"
+	SortByWordCountAsc  = "wordcount-asc"
+)
 
-	// Adjust to the correct year's ISO week 1
-	isoYear, isoWeek := dateInTargetWeek.ISOWeek()
-	for isoYear < year || (isoYear == year && isoWeek < week) {
-		dateInTargetWeek = dateInTargetWeek.AddDate(0, 0, 7)
-		isoYear, isoWeek = dateInTargetWeek.ISOWeek()
+// summaryPrompt returns the prompt for the review summary, asking for the configured language if any.
+func summaryPrompt(cfg *config.Config, prompt string, opts ReviewOptions) string {
+	language := opts.AILanguage
+	if language == "" {
+		language = cfg.DefaultAILanguage
 	}
-	for isoYear > year || (isoYear == year && isoWeek > week) {
-		dateInTargetWeek = dateInTargetWeek.AddDate(0, 0, -7)
-		isoYear, isoWeek = dateInTargetWeek.ISOWeek()
+	if language == "" {
+		return prompt
 	}
+	return fmt.Sprintf("%s Respond in %s.", prompt, language)
+}
 
-	// Now dateInTargetWeek is a date within the target ISO week.
-	// Find the Monday of this week.
-	startDate := dateInTargetWeek
-	for startDate.Weekday() != time.Monday {
-		startDate = startDate.AddDate(0, 0, -1)
+// selectSummarizer returns the summarizer of the AI profile selected in opts, or the given summarizer if none is.
+func selectSummarizer(cfg *config.Config, summarizer ai.AISummarizer, opts ReviewOptions) (ai.AISummarizer, error) {
+	if opts.AIProfile == "" {
+		return summarizer, nil
+	}
+	return cfg.AISummarizerFor(opts.AIProfile)
+}
+
+// reviewDir returns the directory where review files are written.
+func reviewDir(cfg *config.Config) string {
+	return cfg.ReviewDirectory()
+}
+
+// ReviewWeek generates a weekly review file.
+func ReviewWeek(cfg *config.Config, week int, year int, summarizer ai.AISummarizer, reader io.Reader, opts ReviewOptions) (string, error) {
+	summarizer, err := selectSummarizer(cfg, summarizer, opts)
+	if err != nil {
+		return "", err
 	}
+
+	switch opts.OutputFormat {
+	case "", OutputFormatMarkdown, OutputFormatJSON, OutputFormatHTML:
+	default:
+		return "", fmt.Errorf("unknown output format: %s, expected %s, %s or %s", opts.OutputFormat, OutputFormatMarkdown, OutputFormatHTML, OutputFormatJSON)
+	}
+
+	startDate := isoWeekStart(week, year)
 	endDate := startDate.AddDate(0, 0, 6)
 
 	// List journal files for the period
@@ -52,60 +136,405 @@ func ReviewWeek(cfg *config.Config, week int, year int, summarizer ai.AISummariz
 		return "", fmt.Errorf("failed to list journal files for weekly review: %w", err)
 	}
 
+	chronologicalFiles := journalFiles
+
+	switch opts.SortDailySummariesBy {
+	case "", SortByDate:
+		// Files are already listed in chronological order
+	case SortByWordCountDesc, SortByWordCountAsc:
+		journalFiles, err = SortFilesByWordCount(cfg, journalFiles, opts.SortDailySummariesBy == SortByWordCountDesc)
+		if err != nil {
+			return "", fmt.Errorf("failed to sort daily summaries: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unknown daily summaries sort order: %s", opts.SortDailySummariesBy)
+	}
+
+	data := &ReviewData{
+		Title:     fmt.Sprintf("Weekly Review - Week %d, %d", week, year),
+		Period:    fmt.Sprintf("Week %d, %d", week, year),
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+	}
+
 	var reviewContentBuilder strings.Builder
-	reviewContentBuilder.WriteString(fmt.Sprintf("# Weekly Review - Week %d, %d\n\n", week, year))
+	reviewContentBuilder.WriteString(fmt.Sprintf("# %s\n\n", data.Title))
 
 	// Write to a temporary review file for now
-	reviewFilePath := filepath.Join(cfg.JournalDir, fmt.Sprintf("review_week_%d_%d.md", year, week))
+	reviewFilePath := filepath.Join(reviewDir(cfg), weekReviewFileName(week, year))
 	if err := os.MkdirAll(filepath.Dir(reviewFilePath), 0755); err != nil {
 		return "", fmt.Errorf("failed to create directory for weekly review file: %w", err)
 	}
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write weekly review file: %w", err)
 	}
 
 	// Generate summary for the review file if missing
-	reviewSummaryPrompt := "Write a summary of the weekly review using the same Language. Use 1st person and a simple language. Use 200 characters or less."
-	err = journal.GenerateSummaryIfMissing(reviewFilePath, cfg, summarizer, reviewSummaryPrompt, reader)
+	reviewSummaryPrompt := cfg.ReviewWeekPrompt
+	err = journal.GenerateSummaryIfMissing(context.Background(), reviewFilePath, cfg, summarizer, summaryPrompt(cfg, reviewSummaryPrompt, opts), reader)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate summary for weekly review: %w", err)
 	}
 
 	// Read the content again after summary generation
-	reviewContentBytes, err := os.ReadFile(reviewFilePath)
+	reviewContentBytes, err := fileutil.ReadFile(reviewFilePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read weekly review file after summary generation: %w", err)
 	}
+	reviewSummary, err := journal.ExtractSummaryFull(reviewFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read weekly review summary: %w", err)
+	}
+	data.Summary = reviewSummary.Full
+
+	for _, filePath := range journalFiles {
+		summary, skip, err := dailySummary(filePath, opts)
+		if err != nil {
+			return "", err
+		}
+		if skip {
+			continue
+		}
+		fileName := filepath.Base(filePath)
+		dateStr := strings.TrimSuffix(fileName, ".md") // Assuming .md extension
+		day := DailySummary{Date: dateStr, Summary: summary.Full}
+		if opts.IncludeFullLog {
+			if day.Log, err = journal.ExtractLogSection(filePath); err != nil {
+				return "", err
+			}
+		}
+		if opts.IncludeRelated {
+			if day.Related, err = RelatedEntriesSection(cfg, filePath); err != nil {
+				return "", fmt.Errorf("failed to list the related entries of %s: %w", dateStr, err)
+			}
+		}
+		data.DailySummaries = append(data.DailySummaries, day)
+	}
+
+	// The Markdown review keeps the summary as written in the file, e.g. on several lines or encrypted
+	markdownData := *data
+	_, rawSummary, _ := strings.Cut(string(reviewContentBytes), "\n")
+	markdownData.Summary = strings.TrimSpace(rawSummary)
 	reviewContentBuilder.Reset()
-	reviewContentBuilder.Write(reviewContentBytes)
+	reviewContentBuilder.WriteString(WeeklyReviewToMarkdown(&markdownData))
 
-	if len(journalFiles) == 0 {
-		reviewContentBuilder.WriteString("No journal entries found for this week.\n\n")
-	} else {
-		reviewContentBuilder.WriteString("## Daily Summaries\n\n")
-		for _, filePath := range journalFiles {
-			summary, err := journal.ExtractSummary(filePath)
+	if opts.LinkedNavigation {
+		prev, next, err := NavigationLinks(cfg, "week", map[string]int{"week": week, "year": year})
+		if err != nil {
+			return "", fmt.Errorf("failed to build navigation links for weekly review: %w", err)
+		}
+		content := reviewContentBuilder.String()
+		reviewContentBuilder.Reset()
+		reviewContentBuilder.WriteString(insertNavigationBar(content, prev, next))
+	}
+
+	if opts.IncludeInsight {
+		insight, err := weeklyInsight(cfg, chronologicalFiles, summarizer)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate key insight for weekly review: %w", err)
+		}
+		content := reviewContentBuilder.String()
+		reviewContentBuilder.Reset()
+		reviewContentBuilder.WriteString(insertInsight(content, insight))
+	}
+
+	if len(journalFiles) > 0 {
+		if opts.CrossReference {
+			minMentions := opts.MinMentions
+			if minMentions == 0 {
+				minMentions = 2
+			}
+			refs, err := CrossReferences(cfg, chronologicalFiles, minMentions)
 			if err != nil {
-				return "", fmt.Errorf("failed to extract summary from %s: %w", filePath, err)
+				return "", fmt.Errorf("failed to find cross references for weekly review: %w", err)
 			}
-			fileName := filepath.Base(filePath)
-			dateStr := strings.TrimSuffix(fileName, ".md") // Assuming .md extension
-			reviewContentBuilder.WriteString(fmt.Sprintf("### %s\n%s\n\n", dateStr, summary))
+			reviewContentBuilder.WriteString(crossReferenceSection(refs))
+		}
+
+		if config.SectionEnabled(cfg, config.SectionMood) && opts.IncludeMoodTimeline {
+			timeline, err := MoodTimeline(cfg, chronologicalFiles)
+			if err != nil {
+				return "", fmt.Errorf("failed to build mood timeline for weekly review: %w", err)
+			}
+			reviewContentBuilder.WriteString(moodTimelineSection(timeline))
+		}
+	}
+
+	if opts.ShowProductivityScore {
+		score, err := ProductivityScore(cfg, week, year)
+		if err != nil {
+			return "", fmt.Errorf("failed to compute productivity score for weekly review: %w", err)
+		}
+		reviewContentBuilder.WriteString(productivitySection(score))
+	}
+
+	reviewContent, err := BuildReviewWithCustomSections(cfg, reviewContentBuilder.String(), ReviewTemplateData{Period: fmt.Sprintf("Week %d, %d", week, year), StartDate: startDate, EndDate: endDate})
+	if err != nil {
+		return "", fmt.Errorf("failed to add custom sections to weekly review: %w", err)
+	}
+
+	if opts.IncludeFooter {
+		footer, err := BuildWeekFooter(cfg, chronologicalFiles)
+		if err != nil {
+			return "", err
 		}
+		reviewContent = replaceFooter(reviewContent, footer)
+	}
+
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContent), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write weekly review file: %w", err)
+	}
+	if err := writeReviewSidecar(cfg, reviewFilePath, chronologicalFiles, ReviewMeta{Type: "week", Week: week, Year: year}); err != nil {
+		return "", fmt.Errorf("failed to write weekly review metadata: %w", err)
 	}
 
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	// The Markdown review holds the summary, the other formats are written next to it
+	var formatted []byte
+	switch opts.OutputFormat {
+	case "", OutputFormatMarkdown:
+		return theme.Success("Weekly review generated at: %s", reviewFilePath), nil
+	case OutputFormatJSON:
+		formatted, err = WeeklyReviewToJSON(data)
+	case OutputFormatHTML:
+		var page string
+		page, err = WeeklyReviewToHTML(data)
+		formatted = []byte(page)
+	}
 	if err != nil {
+		return "", err
+	}
+	formattedFilePath := strings.TrimSuffix(reviewFilePath, ".md") + "." + opts.OutputFormat
+	if err := os.WriteFile(formattedFilePath, formatted, 0644); err != nil {
 		return "", fmt.Errorf("failed to write weekly review file: %w", err)
 	}
+	return theme.Success("Weekly review generated at: %s", formattedFilePath), nil
+}
 
-	return color.GreenString("Weekly review generated at: %s", reviewFilePath), nil
+// fullLogMarkdown returns the LOG chapter of a daily file for ReviewOptions.IncludeFullLog, each line prefixed
+// with indent, followed by an empty line. Returns an empty string for an empty LOG.
+func fullLogMarkdown(log, indent string) string {
+	if log == "" {
+		return ""
+	}
+	lines := strings.Split(log, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n\n"
 }
 
-// ReviewMonth generates a monthly review file.
-func ReviewMonth(cfg *config.Config, month string, year int, summarizer ai.AISummarizer, reader io.Reader) (string, error) {
-	// Calculate start and end dates for the month
+// SortFilesByWordCount returns the journal files sorted by the number of words they contain.
+// Files with the same word count keep their relative order, that is chronological for files listed by date.
+func SortFilesByWordCount(cfg *config.Config, files []string, descending bool) ([]string, error) {
+	counts := make(map[string]int, len(files))
+	for _, file := range files {
+		count, err := journal.CountWords(cfg, file)
+		if err != nil {
+			return nil, err
+		}
+		counts[file] = count
+	}
+
+	sorted := make([]string, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return counts[sorted[i]] > counts[sorted[j]]
+		}
+		return counts[sorted[i]] < counts[sorted[j]]
+	})
+	return sorted, nil
+}
+
+// isoWeekStart returns the Monday of the given ISO week.
+func isoWeekStart(week int, year int) time.Time {
+	// Calculate start and end dates for the week using ISO week definition.
+	// Go's time.ISOWeek() returns the ISO year and ISO week number.
+	// To get the start date of a given ISO week, we can find the Thursday of that week.
+	// The Thursday of the first week of the year is always in the first week.
+
+	// Start by finding a date in the middle of the target week to ensure we get the correct ISO week.
+	// We can pick the 4th day of the year, as ISO week 1 always contains Jan 4.
+
+	dateInTargetWeek := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+
+	// Adjust to the correct year's ISO week 1
+	isoYear, isoWeek := dateInTargetWeek.ISOWeek()
+	for isoYear < year || (isoYear == year && isoWeek < week) {
+		dateInTargetWeek = dateInTargetWeek.AddDate(0, 0, 7)
+		isoYear, isoWeek = dateInTargetWeek.ISOWeek()
+	}
+	for isoYear > year || (isoYear == year && isoWeek > week) {
+		dateInTargetWeek = dateInTargetWeek.AddDate(0, 0, -7)
+		isoYear, isoWeek = dateInTargetWeek.ISOWeek()
+	}
+
+	// Now dateInTargetWeek is a date within the target ISO week.
+	// Find the Monday of this week.
+	startDate := dateInTargetWeek
+	for startDate.Weekday() != time.Monday {
+		startDate = startDate.AddDate(0, 0, -1)
+	}
+	return startDate
+}
+
+// ErrNoWeeklyReview is returned by FindLastReviewedWeek when the review directory has no weekly review.
+var ErrNoWeeklyReview = errors.New("no weekly review found")
+
+// ISOWeek identifies an ISO 8601 week, as returned by time.Time.ISOWeek.
+type ISOWeek struct {
+	Year int
+	Week int
+}
+
+// FindLastReviewedWeek returns the ISO week and year of the most recent weekly review file of the review directory,
+// by the period in its file name, or ErrNoWeeklyReview if there is none.
+func FindLastReviewedWeek(cfg *config.Config) (week, year int, err error) {
+	paths, err := filepath.Glob(filepath.Join(reviewDir(cfg), "review_week_*.md"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list weekly review files: %w", err)
+	}
+	for _, path := range paths {
+		m := weekReviewPattern.FindStringSubmatch(filepath.Base(path))
+		if m == nil {
+			continue
+		}
+		fileYear, _ := strconv.Atoi(m[1])
+		fileWeek, _ := strconv.Atoi(m[2])
+		if fileWeek < 1 || fileWeek > 53 {
+			continue
+		}
+		if fileYear > year || (fileYear == year && fileWeek > week) {
+			week, year = fileWeek, fileYear
+		}
+	}
+	if year == 0 {
+		return 0, 0, fmt.Errorf("%w in %s", ErrNoWeeklyReview, reviewDir(cfg))
+	}
+	return week, year, nil
+}
+
+// WeeksAfter returns the ISO weeks following the given one up to the week of now included, in chronological order.
+// It is empty if the given week is the week of now or a later one.
+func WeeksAfter(week int, year int, now time.Time) []ISOWeek {
+	nowYear, nowWeek := now.ISOWeek()
+	var weeks []ISOWeek
+	for d := isoWeekStart(week, year).AddDate(0, 0, 7); ; d = d.AddDate(0, 0, 7) {
+		y, w := d.ISOWeek()
+		if y > nowYear || (y == nowYear && w > nowWeek) {
+			return weeks
+		}
+		weeks = append(weeks, ISOWeek{Year: y, Week: w})
+	}
+}
+
+// weekReviewFileName returns the file name of a weekly review.
+func weekReviewFileName(week int, year int) string {
+	return fmt.Sprintf("review_week_%d_%d.md", year, week)
+}
+
+// monthReviewFileName returns the file name of a monthly review.
+func monthReviewFileName(month time.Month, year int) string {
+	return fmt.Sprintf("review_month_%s_%d.md", month.String(), year)
+}
+
+// quarterReviewFileName returns the file name of a quarterly review.
+func quarterReviewFileName(quarter int, year int) string {
+	return fmt.Sprintf("review_quarter_Q%d_%d.md", quarter, year)
+}
+
+// customReviewFileName returns the file name of a review of the days from startDate to endDate.
+func customReviewFileName(startDate, endDate time.Time) string {
+	return fmt.Sprintf("review_custom_%s_%s.md", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+}
+
+// yearReviewFileName returns the file name of a yearly review.
+func yearReviewFileName(year int) string {
+	return fmt.Sprintf("review_year_%d.md", year)
+}
+
+// NavigationLinks returns Markdown links to the reviews preceding and following the given one.
+// reviewType is one of "week", "month" or "year"; params holds the "week", "month" (1-12) and "year" values identifying the review.
+// A link is empty when the adjacent review file does not exist in the review directory.
+func NavigationLinks(cfg *config.Config, reviewType string, params map[string]int) (prev, next string, err error) {
+	year, ok := params["year"]
+	if !ok {
+		return "", "", fmt.Errorf("missing year for %s review navigation", reviewType)
+	}
+
+	var prevLabel, nextLabel, prevFile, nextFile string
+	switch reviewType {
+	case "week":
+		week, ok := params["week"]
+		if !ok {
+			return "", "", fmt.Errorf("missing week for week review navigation")
+		}
+		// Moving by 7 days from the Monday handles the ISO year boundary (week 52/53 <-> week 1)
+		start := isoWeekStart(week, year)
+		prevYear, prevWeek := start.AddDate(0, 0, -7).ISOWeek()
+		nextYear, nextWeek := start.AddDate(0, 0, 7).ISOWeek()
+		prevLabel, prevFile = fmt.Sprintf("Week %d, %d", prevWeek, prevYear), weekReviewFileName(prevWeek, prevYear)
+		nextLabel, nextFile = fmt.Sprintf("Week %d, %d", nextWeek, nextYear), weekReviewFileName(nextWeek, nextYear)
+	case "month":
+		month, ok := params["month"]
+		if !ok || month < 1 || month > 12 {
+			return "", "", fmt.Errorf("missing or invalid month for month review navigation")
+		}
+		first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		prevMonth, nextMonth := first.AddDate(0, -1, 0), first.AddDate(0, 1, 0)
+		prevLabel, prevFile = fmt.Sprintf("%s %d", prevMonth.Month(), prevMonth.Year()), monthReviewFileName(prevMonth.Month(), prevMonth.Year())
+		nextLabel, nextFile = fmt.Sprintf("%s %d", nextMonth.Month(), nextMonth.Year()), monthReviewFileName(nextMonth.Month(), nextMonth.Year())
+	case "year":
+		prevLabel, prevFile = fmt.Sprintf("%d", year-1), yearReviewFileName(year-1)
+		nextLabel, nextFile = fmt.Sprintf("%d", year+1), yearReviewFileName(year+1)
+	default:
+		return "", "", fmt.Errorf("unsupported review type for navigation: %s", reviewType)
+	}
+
+	dir := reviewDir(cfg)
+	if _, err := os.Stat(filepath.Join(dir, prevFile)); err == nil {
+		prev = fmt.Sprintf("← [%s](%s)", prevLabel, prevFile)
+	} else if !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("failed to check review file %s: %w", prevFile, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, nextFile)); err == nil {
+		next = fmt.Sprintf("→ [%s](%s)", nextLabel, nextFile)
+	} else if !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("failed to check review file %s: %w", nextFile, err)
+	}
+
+	return prev, next, nil
+}
+
+// insertNavigationBar inserts the navigation links right below the title of the review content.
+func insertNavigationBar(content, prev, next string) string {
+	var links []string
+	for _, link := range []string{prev, next} {
+		if link != "" {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return content
+	}
+
+	title, rest, _ := strings.Cut(content, "\n")
+	return title + "\n" + strings.Join(links, " | ") + "\n\n" + strings.TrimLeft(rest, "\n")
+}
+
+// ParseMonth returns the month named by month, either by its English name (e.g. "September")
+// or by its number from 1 to 12 (e.g. "9").
+func ParseMonth(month string) (time.Month, error) {
+	if number, err := strconv.Atoi(month); err == nil {
+		if number < 1 || number > 12 {
+			return 0, fmt.Errorf("invalid month number: %d, expected 1 to 12", number)
+		}
+		return time.Month(number), nil
+	}
 	monthNum := map[string]time.Month{
 		"January": time.January, "February": time.February, "March": time.March,
 		"April": time.April, "May": time.May, "June": time.June,
@@ -113,8 +542,25 @@ func ReviewMonth(cfg *config.Config, month string, year int, summarizer ai.AISum
 		"October": time.October, "November": time.November, "December": time.December,
 	}[month]
 	if monthNum == 0 {
-		return "", fmt.Errorf("invalid month name: %s", month)
+		return 0, fmt.Errorf("invalid month name: %s", month)
+	}
+	return monthNum, nil
+}
+
+// ReviewMonth generates a monthly review file.
+// The month is a name or a number, see ParseMonth; the review always uses its name, e.g. review_month_September_2025.md.
+func ReviewMonth(cfg *config.Config, month string, year int, summarizer ai.AISummarizer, reader io.Reader, opts ReviewOptions) (string, error) {
+	summarizer, err := selectSummarizer(cfg, summarizer, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Calculate start and end dates for the month
+	monthNum, err := ParseMonth(month)
+	if err != nil {
+		return "", err
 	}
+	month = monthNum.String()
 
 	startDate := time.Date(year, monthNum, 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, -1) // Last day of the month
@@ -125,56 +571,304 @@ func ReviewMonth(cfg *config.Config, month string, year int, summarizer ai.AISum
 		return "", fmt.Errorf("failed to list journal files for monthly review: %w", err)
 	}
 
+	var issuePattern *regexp.Regexp
+	if cfg.IssueLinkPattern != "" {
+		issuePattern, err = regexp.Compile(cfg.IssueLinkPattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid issue link pattern: %w", err)
+		}
+	}
+
 	var reviewContentBuilder strings.Builder
 	reviewContentBuilder.WriteString(fmt.Sprintf("# Monthly Review - %s %d\n\n", month, year))
 
-	reviewFilePath := filepath.Join(cfg.JournalDir, fmt.Sprintf("review_month_%s_%d.md", month, year))
+	reviewFilePath := filepath.Join(reviewDir(cfg), monthReviewFileName(monthNum, year))
 	if err := os.MkdirAll(filepath.Dir(reviewFilePath), 0755); err != nil {
 		return "", fmt.Errorf("failed to create directory for monthly review file: %w", err)
 	}
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write monthly review file: %w", err)
 	}
 
-	reviewSummaryPrompt := "Write a summary of the monthly review. Use 1st person and a simple language. Use 200 characters or less."
-	err = journal.GenerateSummaryIfMissing(reviewFilePath, cfg, summarizer, reviewSummaryPrompt, reader)
+	reviewSummaryPrompt := cfg.ReviewMonthPrompt
+	// The retrospective is only asked to the AI, a manual summary stays a plain summary
+	retro := opts.RetroFormat && summarizer != nil
+	if retro {
+		reviewSummaryPrompt = retroPrompt
+	}
+	err = journal.GenerateSummaryIfMissing(context.Background(), reviewFilePath, cfg, summarizer, summaryPrompt(cfg, reviewSummaryPrompt, opts), reader)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate summary for monthly review: %w", err)
 	}
 
-	reviewContentBytes, err := os.ReadFile(reviewFilePath)
+	reviewContentBytes, err := fileutil.ReadFile(reviewFilePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read monthly review file after summary generation: %w", err)
 	}
 	reviewContentBuilder.Reset()
-	reviewContentBuilder.Write(reviewContentBytes)
+	if retro {
+		head, retroResponse := splitRetroResponse(string(reviewContentBytes))
+		start, stop, continue_, err := ParseRetroSections(retroResponse)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse retrospective for monthly review: %w", err)
+		}
+		reviewContentBuilder.WriteString(BuildMonthSections(head, start, stop, continue_))
+	} else {
+		reviewContentBuilder.Write(reviewContentBytes)
+	}
+
+	if opts.IncludeWeeklyReviews {
+		weeklyReviews, err := weeklyReviewsMarkdown(cfg, monthNum, year)
+		if err != nil {
+			return "", fmt.Errorf("failed to read weekly reviews for monthly review: %w", err)
+		}
+		reviewContentBuilder.WriteString(weeklyReviews)
+	}
 
 	if len(journalFiles) == 0 {
 		reviewContentBuilder.WriteString("No journal entries found for this month.\n\n")
 	} else {
 		reviewContentBuilder.WriteString("## Daily Summaries\n\n")
 		for _, filePath := range journalFiles {
-			summary, err := journal.ExtractSummary(filePath)
+			summary, skip, err := dailySummary(filePath, opts)
 			if err != nil {
-				return "", fmt.Errorf("failed to extract summary from %s: %w", filePath, err)
+				return "", err
+			}
+			if skip {
+				continue
 			}
 			fileName := filepath.Base(filePath)
 			dateStr := strings.TrimSuffix(fileName, ".md") // Assuming .md extension
-			reviewContentBuilder.WriteString(fmt.Sprintf("### %s\n%s\n\n", dateStr, summary))
+			linkedSummary, err := LinkifyIssueRefs(summary.Full, issuePattern, cfg.IssueLinkTemplate)
+			if err != nil {
+				return "", fmt.Errorf("failed to link issues in summary of %s: %w", filePath, err)
+			}
+			reviewContentBuilder.WriteString(fmt.Sprintf("### %s\n%s\n\n", dateStr, linkedSummary))
+			if opts.IncludeFullLog {
+				log, err := journal.ExtractLogSection(filePath)
+				if err != nil {
+					return "", err
+				}
+				reviewContentBuilder.WriteString(fullLogMarkdown(log, ""))
+			}
+		}
+	}
+
+	if opts.IncludeHabits || config.SectionEnabled(cfg, config.SectionHabitTracker) {
+		completion, err := stats.HabitCompletion(cfg, startDate, endDate)
+		if err != nil {
+			return "", fmt.Errorf("failed to track habits for monthly review: %w", err)
 		}
+		reviewContentBuilder.WriteString(habitsSection(cfg.Habits, completion))
+	}
+
+	reviewContent, err := BuildReviewWithCustomSections(cfg, reviewContentBuilder.String(), ReviewTemplateData{Period: fmt.Sprintf("%s %d", month, year), StartDate: startDate, EndDate: endDate})
+	if err != nil {
+		return "", fmt.Errorf("failed to add custom sections to monthly review: %w", err)
 	}
 
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContent), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write monthly review file: %w", err)
 	}
+	if err := writeReviewSidecar(cfg, reviewFilePath, journalFiles, ReviewMeta{Type: "month", Month: month, Year: year}); err != nil {
+		return "", fmt.Errorf("failed to write monthly review metadata: %w", err)
+	}
+
+	return theme.Success("Monthly review generated at: %s", reviewFilePath), nil
+}
+
+// ReviewQuarter generates a quarterly review file, with the daily summaries grouped by month.
+// Quarters go from 1 (January to March) to 4 (October to December).
+func ReviewQuarter(cfg *config.Config, quarter int, year int, summarizer ai.AISummarizer, reader io.Reader, opts ReviewOptions) (string, error) {
+	if quarter < 1 || quarter > 4 {
+		return "", fmt.Errorf("invalid quarter: %d, expected a number from 1 to 4", quarter)
+	}
+	summarizer, err := selectSummarizer(cfg, summarizer, opts)
+	if err != nil {
+		return "", err
+	}
+
+	startDate := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 3, -1) // Last day of the third month
+
+	journalFiles, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return "", fmt.Errorf("failed to list journal files for quarterly review: %w", err)
+	}
+
+	var issuePattern *regexp.Regexp
+	if cfg.IssueLinkPattern != "" {
+		issuePattern, err = regexp.Compile(cfg.IssueLinkPattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid issue link pattern: %w", err)
+		}
+	}
+
+	var reviewContentBuilder strings.Builder
+	reviewContentBuilder.WriteString(fmt.Sprintf("# Quarterly Review - Q%d %d\n\n", quarter, year))
+
+	reviewFilePath := filepath.Join(reviewDir(cfg), quarterReviewFileName(quarter, year))
+	if err := os.MkdirAll(filepath.Dir(reviewFilePath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for quarterly review file: %w", err)
+	}
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write quarterly review file: %w", err)
+	}
+
+	reviewSummaryPrompt := "Write a summary of the quarterly review, focusing on the progress over the three months. Use 1st person and a simple language. Use 300 characters or less."
+	err = journal.GenerateSummaryIfMissing(context.Background(), reviewFilePath, cfg, summarizer, summaryPrompt(cfg, reviewSummaryPrompt, opts), reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate summary for quarterly review: %w", err)
+	}
+
+	reviewContentBytes, err := fileutil.ReadFile(reviewFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read quarterly review file after summary generation: %w", err)
+	}
+	reviewContentBuilder.Reset()
+	reviewContentBuilder.Write(reviewContentBytes)
+
+	if len(journalFiles) == 0 {
+		reviewContentBuilder.WriteString("No journal entries found for this quarter.\n\n")
+	} else {
+		reviewContentBuilder.WriteString("## Daily Summaries\n\n")
+		var currentMonth time.Month
+		for _, filePath := range journalFiles {
+			date, err := journal.DateFromFilePath(cfg, filePath)
+			if err != nil {
+				return "", err
+			}
+			summary, skip, err := dailySummary(filePath, opts)
+			if err != nil {
+				return "", err
+			}
+			if skip {
+				continue
+			}
+			if date.Month() != currentMonth {
+				currentMonth = date.Month()
+				reviewContentBuilder.WriteString(fmt.Sprintf("### %s\n\n", currentMonth))
+			}
+			linkedSummary, err := LinkifyIssueRefs(summary.Full, issuePattern, cfg.IssueLinkTemplate)
+			if err != nil {
+				return "", fmt.Errorf("failed to link issues in summary of %s: %w", filePath, err)
+			}
+			reviewContentBuilder.WriteString(fmt.Sprintf("#### %s\n%s\n\n", date.Format("2006-01-02"), linkedSummary))
+		}
+	}
+
+	if opts.IncludeHabits || config.SectionEnabled(cfg, config.SectionHabitTracker) {
+		completion, err := stats.HabitCompletion(cfg, startDate, endDate)
+		if err != nil {
+			return "", fmt.Errorf("failed to track habits for quarterly review: %w", err)
+		}
+		reviewContentBuilder.WriteString(habitsSection(cfg.Habits, completion))
+	}
+
+	reviewContent, err := BuildReviewWithCustomSections(cfg, reviewContentBuilder.String(), ReviewTemplateData{Period: fmt.Sprintf("Q%d %d", quarter, year), StartDate: startDate, EndDate: endDate})
+	if err != nil {
+		return "", fmt.Errorf("failed to add custom sections to quarterly review: %w", err)
+	}
+
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContent), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write quarterly review file: %w", err)
+	}
+	if err := writeReviewSidecar(cfg, reviewFilePath, journalFiles, ReviewMeta{Type: "quarter", Quarter: quarter, Year: year}); err != nil {
+		return "", fmt.Errorf("failed to write quarterly review metadata: %w", err)
+	}
+
+	return theme.Success("Quarterly review generated at: %s", reviewFilePath), nil
+}
+
+// ReviewCustomRange generates a review file of the days from startDate to endDate included, e.g. a sprint or a vacation.
+// It has the same structure as the weekly review. Only the dates of startDate and endDate are used.
+func ReviewCustomRange(cfg *config.Config, startDate, endDate time.Time, summarizer ai.AISummarizer, reader io.Reader, opts ReviewOptions) (string, error) {
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+	if endDate.Before(startDate) {
+		return "", fmt.Errorf("end date %s is before start date %s", endDate.Format("2006-01-02"), startDate.Format("2006-01-02"))
+	}
+	summarizer, err := selectSummarizer(cfg, summarizer, opts)
+	if err != nil {
+		return "", err
+	}
+
+	journalFiles, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return "", fmt.Errorf("failed to list journal files for custom review: %w", err)
+	}
+
+	period := fmt.Sprintf("%s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	var reviewContentBuilder strings.Builder
+	reviewContentBuilder.WriteString(fmt.Sprintf("# Review - %s\n\n", period))
+
+	reviewFilePath := filepath.Join(reviewDir(cfg), customReviewFileName(startDate, endDate))
+	if err := os.MkdirAll(filepath.Dir(reviewFilePath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for custom review file: %w", err)
+	}
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write custom review file: %w", err)
+	}
+
+	reviewSummaryPrompt := "Write a summary of the review of the period using the same Language. Use 1st person and a simple language. Use 200 characters or less."
+	err = journal.GenerateSummaryIfMissing(context.Background(), reviewFilePath, cfg, summarizer, summaryPrompt(cfg, reviewSummaryPrompt, opts), reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate summary for custom review: %w", err)
+	}
+
+	reviewContentBytes, err := fileutil.ReadFile(reviewFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read custom review file after summary generation: %w", err)
+	}
+	reviewContentBuilder.Reset()
+	reviewContentBuilder.Write(reviewContentBytes)
+
+	if len(journalFiles) == 0 {
+		reviewContentBuilder.WriteString("No journal entries found for this period.\n\n")
+	} else {
+		reviewContentBuilder.WriteString("## Daily Summaries\n\n")
+		for _, filePath := range journalFiles {
+			summary, skip, err := dailySummary(filePath, opts)
+			if err != nil {
+				return "", err
+			}
+			if skip {
+				continue
+			}
+			dateStr := strings.TrimSuffix(filepath.Base(filePath), ".md")
+			reviewContentBuilder.WriteString(fmt.Sprintf("### %s\n%s\n\n", dateStr, summary.Full))
+		}
+	}
+
+	reviewContent, err := BuildReviewWithCustomSections(cfg, reviewContentBuilder.String(), ReviewTemplateData{Period: period, StartDate: startDate, EndDate: endDate})
+	if err != nil {
+		return "", fmt.Errorf("failed to add custom sections to custom review: %w", err)
+	}
+
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContent), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write custom review file: %w", err)
+	}
+	meta := ReviewMeta{Type: "custom", Start: startDate.Format("2006-01-02"), End: endDate.Format("2006-01-02"), Year: startDate.Year()}
+	if err := writeReviewSidecar(cfg, reviewFilePath, journalFiles, meta); err != nil {
+		return "", fmt.Errorf("failed to write custom review metadata: %w", err)
+	}
 
-	return color.GreenString("Monthly review generated at: %s", reviewFilePath), nil
+	return theme.Success("Custom review generated at: %s", reviewFilePath), nil
 }
 
 // ReviewYear generates a yearly review file with monthly summaries and daily entries organized by month.
-func ReviewYear(cfg *config.Config, year int, summarizer ai.AISummarizer, reader io.Reader) (string, error) {
+func ReviewYear(cfg *config.Config, year int, summarizer ai.AISummarizer, reader io.Reader, opts ReviewOptions) (string, error) {
+	summarizer, err := selectSummarizer(cfg, summarizer, opts)
+	if err != nil {
+		return "", err
+	}
+
 	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
 
@@ -186,28 +880,36 @@ func ReviewYear(cfg *config.Config, year int, summarizer ai.AISummarizer, reader
 	var reviewContentBuilder strings.Builder
 	reviewContentBuilder.WriteString(fmt.Sprintf("# Yearly Review - %d\n\n", year))
 
-	reviewFilePath := filepath.Join(cfg.JournalDir, fmt.Sprintf("review_year_%d.md", year))
+	reviewFilePath := filepath.Join(reviewDir(cfg), yearReviewFileName(year))
 	if err := os.MkdirAll(filepath.Dir(reviewFilePath), 0755); err != nil {
 		return "", fmt.Errorf("failed to create directory for yearly review file: %w", err)
 	}
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write yearly review file: %w", err)
 	}
 
-	reviewSummaryPrompt := "Write a summary of the yearly review. Use 1st person and a simple language. Use 200 characters or less."
-	err = journal.GenerateSummaryIfMissing(reviewFilePath, cfg, summarizer, reviewSummaryPrompt, reader)
+	reviewSummaryPrompt := cfg.ReviewYearPrompt
+	err = journal.GenerateSummaryIfMissing(context.Background(), reviewFilePath, cfg, summarizer, summaryPrompt(cfg, reviewSummaryPrompt, opts), reader)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate summary for yearly review: %w", err)
 	}
 
-	reviewContentBytes, err := os.ReadFile(reviewFilePath)
+	reviewContentBytes, err := fileutil.ReadFile(reviewFilePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read yearly review file after summary generation: %w", err)
 	}
 	reviewContentBuilder.Reset()
 	reviewContentBuilder.Write(reviewContentBytes)
 
+	if opts.IncludeEntryGraph {
+		graph, err := MonthlyEntryGraph(cfg, year)
+		if err != nil {
+			return "", fmt.Errorf("failed to build entry graph for yearly review: %w", err)
+		}
+		reviewContentBuilder.WriteString("## Entries per Month\n\n```\n" + graph + "```\n\n")
+	}
+
 	if len(journalFiles) == 0 {
 		reviewContentBuilder.WriteString("No journal entries found for this year.\n\n")
 	} else {
@@ -236,22 +938,245 @@ func ReviewYear(cfg *config.Config, year int, summarizer ai.AISummarizer, reader
 
 			// Add daily summaries for this month
 			for _, filePath := range files {
-				summary, err := journal.ExtractSummary(filePath)
+				summary, skip, err := dailySummary(filePath, opts)
 				if err != nil {
-					return "", fmt.Errorf("failed to extract summary from %s: %w", filePath, err)
+					return "", err
+				}
+				if skip {
+					continue
 				}
 				fileName := filepath.Base(filePath)
 				dateStr := strings.TrimSuffix(fileName, ".md")
-				reviewContentBuilder.WriteString(fmt.Sprintf("- **%s**: %s\n", dateStr, summary))
+				// Keep the list item on one line
+				fullSummary := strings.ReplaceAll(summary.Full, "\n\n", " ")
+				reviewContentBuilder.WriteString(fmt.Sprintf("- **%s**: %s\n", dateStr, fullSummary))
+				if opts.IncludeFullLog {
+					log, err := journal.ExtractLogSection(filePath)
+					if err != nil {
+						return "", err
+					}
+					// Indented to stay in the list item, on the line after the summary
+					if log != "" {
+						reviewContentBuilder.WriteString("\n")
+					}
+					reviewContentBuilder.WriteString(fullLogMarkdown(log, "  "))
+				}
 			}
 			reviewContentBuilder.WriteString("\n")
 		}
+
+		if opts.ExtractLearnings {
+			section, err := learningsSection(cfg, journalFiles, summarizer)
+			if err != nil {
+				return "", fmt.Errorf("failed to extract learnings for yearly review: %w", err)
+			}
+			reviewContentBuilder.WriteString(section)
+		}
+
+		if opts.BestOf {
+			bestOf, err := BestOfEntries(cfg, year)
+			if err != nil {
+				return "", fmt.Errorf("failed to find the best entries for yearly review: %w", err)
+			}
+			reviewContentBuilder.WriteString(bestOfSection(bestOf))
+		}
+
+		if opts.IncludeTopTags {
+			topN := opts.TopTagsCount
+			if topN <= 0 {
+				topN = defaultTopTags
+			}
+			tags, err := TopTags(cfg, year, topN)
+			if err != nil {
+				return "", fmt.Errorf("failed to count tags for yearly review: %w", err)
+			}
+			reviewContentBuilder.WriteString(topTagsSection(tags))
+		}
+	}
+
+	reviewContent, err := BuildReviewWithCustomSections(cfg, reviewContentBuilder.String(), ReviewTemplateData{Period: fmt.Sprintf("%d", year), StartDate: startDate, EndDate: endDate})
+	if err != nil {
+		return "", fmt.Errorf("failed to add custom sections to yearly review: %w", err)
 	}
 
-	err = os.WriteFile(reviewFilePath, []byte(reviewContentBuilder.String()), 0644)
+	err = fileutil.AtomicWrite(reviewFilePath, []byte(reviewContent), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write yearly review file: %w", err)
 	}
+	if err := writeReviewSidecar(cfg, reviewFilePath, journalFiles, ReviewMeta{Type: "year", Year: year}); err != nil {
+		return "", fmt.Errorf("failed to write yearly review metadata: %w", err)
+	}
+
+	return theme.Success("Yearly review generated at: %s", reviewFilePath), nil
+}
+
+// dailySummary returns the summary of a journal file for a review, decrypting it with opts.PassPhrase if encrypted.
+// skip is true, after a warning, for the encrypted summaries without a passphrase.
+func dailySummary(filePath string, opts ReviewOptions) (summary journal.ExtractSummaryResult, skip bool, err error) {
+	summary, err = journal.ExtractSummaryFull(filePath)
+	if err != nil {
+		return summary, false, fmt.Errorf("failed to extract summary from %s: %w", filePath, err)
+	}
+	if !summary.Encrypted {
+		return summary, false, nil
+	}
+	if opts.PassPhrase == "" {
+		fmt.Println(theme.Warning("Skipping %s: its summary is encrypted, use --decrypt to include it.", filePath))
+		return summary, true, nil
+	}
+	full, err := journal.DecryptSummary(filePath, opts.PassPhrase)
+	if err != nil {
+		return summary, false, err
+	}
+	paragraphs := strings.Split(full, "\n\n")
+	return journal.ExtractSummaryResult{Short: paragraphs[0], Full: full, ParagraphCount: len(paragraphs)}, false, nil
+}
+
+// learningsSection returns the "Skills & Tools Learned" section for the LOG entries of the given files.
+func learningsSection(cfg *config.Config, journalFiles []string, summarizer ai.AISummarizer) (string, error) {
+	if summarizer == nil {
+		fmt.Println(theme.Warning("No AI agent configured, skipping the skills and tools learned."))
+		return "", nil
+	}
+
+	var entries []string
+	for _, filePath := range journalFiles {
+		logEntries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range logEntries {
+			entries = append(entries, entry.Text)
+		}
+	}
+
+	learnings, err := ExtractLearnings(limitToTokens(entries, cfg.AIMaxContextTokens), summarizer, cfg.LearningExtractionPrompt)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Skills & Tools Learned\n\n")
+	if len(learnings) == 0 {
+		sb.WriteString("No skills or tools found.\n\n")
+		return sb.String(), nil
+	}
+	for _, learning := range learnings {
+		sb.WriteString("- " + learning + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
+
+// ExtractLearnings asks the AI for the technologies, methodologies and tools mentioned in the entries,
+// and returns one item per line of the answer, without list markers and duplicates.
+// Entries are sent one per line: limit them with the AI context size first.
+func ExtractLearnings(entries []string, summarizer ai.AISummarizer, prompt string) ([]string, error) {
+	if summarizer == nil {
+		return nil, fmt.Errorf("AI summarizer is not configured")
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	output, err := summarizer.GenerateSummary(context.Background(), strings.Join(entries, "\n"), prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract learnings with AI: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var learnings []string
+	for _, line := range strings.Split(output, "\n") {
+		item := strings.TrimSpace(listMarkerPattern.ReplaceAllString(strings.TrimSpace(line), ""))
+		if item == "" || seen[strings.ToLower(item)] {
+			continue
+		}
+		seen[strings.ToLower(item)] = true
+		learnings = append(learnings, item)
+	}
+	return learnings, nil
+}
 
-	return color.GreenString("Yearly review generated at: %s", reviewFilePath), nil
+// listMarkerPattern matches the bullet or number at the start of a list item.
+var listMarkerPattern = regexp.MustCompile(`^([-*•+]|\d+[.)])\s*`)
+
+// limitToTokens returns the first entries whose text fits in maxTokens, estimating a token per word.
+// A maxTokens of zero means no limit.
+func limitToTokens(entries []string, maxTokens int) []string {
+	if maxTokens <= 0 {
+		return entries
+	}
+	tokens := 0
+	for i, entry := range entries {
+		tokens += len(strings.Fields(entry))
+		if tokens > maxTokens {
+			return entries[:i]
+		}
+	}
+	return entries
+}
+
+// LinkifyIssueRefs replaces the issue references matching pattern with Markdown links "[<match>](<url>)".
+// The URL is urlTemplate rendered with the "id" named group of the match as {{.ID}} (the whole match if there is no such group).
+// A nil pattern leaves the text unchanged.
+func LinkifyIssueRefs(text string, pattern *regexp.Regexp, urlTemplate string) (string, error) {
+	if pattern == nil {
+		return text, nil
+	}
+
+	idIndex := pattern.SubexpIndex("id")
+	var sb strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start == end {
+			continue // Ignore empty matches
+		}
+		id := text[start:end]
+		if idIndex != -1 && match[2*idIndex] != -1 {
+			id = text[match[2*idIndex]:match[2*idIndex+1]]
+		}
+		url, err := template.RenderData(urlTemplate, struct{ ID string }{ID: id})
+		if err != nil {
+			return "", fmt.Errorf("failed to render issue link for %s: %w", id, err)
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(fmt.Sprintf("[%s](%s)", text[start:end], url))
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), nil
+}
+
+// defaultChartHeight is used when Config.ChartHeight is not set.
+const defaultChartHeight = 10
+
+// MonthlyEntryGraph returns an ASCII bar chart of the number of LOG entries written in each month of the year.
+func MonthlyEntryGraph(cfg *config.Config, year int) (string, error) {
+	labels := make([]string, 0, 12)
+	counts := make([]int, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		journalFiles, err := journal.ListJournalFilesByPeriod(cfg, startDate, startDate.AddDate(0, 1, -1))
+		if err != nil {
+			return "", fmt.Errorf("failed to list journal files for %s %d: %w", month, year, err)
+		}
+
+		count := 0
+		for _, filePath := range journalFiles {
+			entries, err := journal.ExtractLogEntries(cfg, filePath)
+			if err != nil {
+				return "", err
+			}
+			count += len(entries)
+		}
+		labels = append(labels, month.String()[:3])
+		counts = append(counts, count)
+	}
+
+	height := cfg.ChartHeight
+	if height == 0 {
+		height = defaultChartHeight
+	}
+	return chart.BarChart(labels, counts, height)
 }
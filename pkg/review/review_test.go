@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/clobrano/LogBook/pkg/ai"
 	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
 	"github.com/clobrano/LogBook/pkg/template"
 	"github.com/stretchr/testify/assert"
 )
@@ -63,7 +66,7 @@ func TestReviewWeek(t *testing.T) {
 	aiCfg.DailyTemplate = cfg.DailyTemplate
 	aiCfg.AISummarizer = aiSummarizer
 
-	result, err := ReviewWeek(aiCfg, week, year, aiSummarizer, strings.NewReader(""))
+	result, err := ReviewWeek(aiCfg, week, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage := fmt.Sprintf("Weekly review generated at: %s", filepath.Join(tmpDir, "review_week_2025_38.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -99,7 +102,7 @@ func TestReviewWeek(t *testing.T) {
 
 	// Re-create the review file to ensure it's clean for manual input
 	os.Remove(reviewFilePath)
-	result, err = ReviewWeek(manualCfg, week, year, nil, manualReader)
+	result, err = ReviewWeek(manualCfg, week, year, nil, manualReader, ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage = fmt.Sprintf("Weekly review generated at: %s", filepath.Join(tmpDir, "review_week_2025_38.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -129,7 +132,7 @@ func TestReviewWeek(t *testing.T) {
 	noEntriesCfg.DailyTemplate = cfg.DailyTemplate
 	noEntriesCfg.AISummarizer = nil
 
-	result, err = ReviewWeek(noEntriesCfg, week, year, nil, strings.NewReader("\n")) // Simulate skipping manual summary
+	result, err = ReviewWeek(noEntriesCfg, week, year, nil, strings.NewReader("\n"), ReviewOptions{}) // Simulate skipping manual summary
 	assert.NoError(t, err)
 	assert.Contains(t, result, fmt.Sprintf("Weekly review generated at: %s", filepath.Join(noEntriesTmpDir, "review_week_2025_38.md")))
 
@@ -147,9 +150,19 @@ func TestReviewWeek(t *testing.T) {
 	// Test case 4: Error during manual summary input
 	errorReader := &ErrorReader{Err: errors.New("read error during manual summary")}
 	os.Remove(reviewFilePath) // Clean up previous review file
-	_, err = ReviewWeek(noEntriesCfg, week, year, nil, errorReader)
+	_, err = ReviewWeek(noEntriesCfg, week, year, nil, errorReader, ReviewOptions{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to generate summary for weekly review: failed to read manual summary: read error during manual summary")
+
+	// Test case 5: Review files go to ReviewDir when it differs from JournalDir
+	reviewDir := filepath.Join(t.TempDir(), "reviews")
+	aiCfg.ReviewDir = reviewDir
+	os.Remove(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	result, err = ReviewWeek(aiCfg, week, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Weekly review generated at: %s", filepath.Join(reviewDir, "review_week_2025_38.md")), result)
+	assert.FileExists(t, filepath.Join(reviewDir, "review_week_2025_38.md"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "review_week_2025_38.md"))
 }
 
 func TestReviewMonth(t *testing.T) {
@@ -187,7 +200,7 @@ func TestReviewMonth(t *testing.T) {
 	aiCfg.DailyTemplate = cfg.DailyTemplate
 	aiCfg.AISummarizer = aiSummarizer
 
-	result, err := ReviewMonth(aiCfg, month, year, aiSummarizer, strings.NewReader(""))
+	result, err := ReviewMonth(aiCfg, month, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage := fmt.Sprintf("Monthly review generated at: %s", filepath.Join(tmpDir, "review_month_September_2025.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -220,7 +233,7 @@ func TestReviewMonth(t *testing.T) {
 
 	// Re-create the review file to ensure it's clean for manual input
 	os.Remove(reviewFilePath)
-	result, err = ReviewMonth(manualCfg, month, year, nil, manualReader)
+	result, err = ReviewMonth(manualCfg, month, year, nil, manualReader, ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage = fmt.Sprintf("Monthly review generated at: %s", filepath.Join(tmpDir, "review_month_September_2025.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -247,8 +260,8 @@ func TestReviewMonth(t *testing.T) {
 	noEntriesCfg.DailyTemplate = cfg.DailyTemplate
 	noEntriesCfg.AISummarizer = nil
 
-	os.Remove(reviewFilePath) // Clean up previous review file
-	result, err = ReviewMonth(noEntriesCfg, month, year, nil, strings.NewReader("\n")) // Simulate skipping manual summary
+	os.Remove(reviewFilePath)                                                                           // Clean up previous review file
+	result, err = ReviewMonth(noEntriesCfg, month, year, nil, strings.NewReader("\n"), ReviewOptions{}) // Simulate skipping manual summary
 	assert.NoError(t, err)
 	assert.Contains(t, result, fmt.Sprintf("Monthly review generated at: %s", filepath.Join(noEntriesTmpDir, "review_month_September_2025.md")))
 
@@ -266,11 +279,52 @@ func TestReviewMonth(t *testing.T) {
 	// Test case 4: Error during manual summary input
 	errorReader := &ErrorReader{Err: errors.New("read error during manual summary")}
 	os.Remove(reviewFilePath) // Clean up previous review file
-	_, err = ReviewMonth(noEntriesCfg, month, year, nil, errorReader)
+	_, err = ReviewMonth(noEntriesCfg, month, year, nil, errorReader, ReviewOptions{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to generate summary for monthly review: failed to read manual summary: read error during manual summary")
+
+	// Test case 5: Month given as a number, the review keeps the month name
+	os.Remove(filepath.Join(tmpDir, "review_month_September_2025.md"))
+	result, err = ReviewMonth(aiCfg, "9", year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Monthly review generated at: %s", filepath.Join(tmpDir, "review_month_September_2025.md")), result)
+	reviewContent, err = os.ReadFile(filepath.Join(tmpDir, "review_month_September_2025.md"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(reviewContent), "# Monthly Review - September 2025\n"))
+
+	// Test case 6: Invalid month numbers and names
+	_, err = ReviewMonth(aiCfg, "0", year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.ErrorContains(t, err, "invalid month number: 0")
+	_, err = ReviewMonth(aiCfg, "13", year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.ErrorContains(t, err, "invalid month number: 13")
+	_, err = ReviewMonth(aiCfg, "Septembre", year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.ErrorContains(t, err, "invalid month name: Septembre")
+
+	// Test case 7: Review files go to ReviewDir when it differs from JournalDir
+	reviewDir := filepath.Join(t.TempDir(), "reviews")
+	aiCfg.ReviewDir = reviewDir
+	os.Remove(filepath.Join(tmpDir, "review_month_September_2025.md"))
+	result, err = ReviewMonth(aiCfg, month, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Monthly review generated at: %s", filepath.Join(reviewDir, "review_month_September_2025.md")), result)
+	assert.FileExists(t, filepath.Join(reviewDir, "review_month_September_2025.md"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "review_month_September_2025.md"))
 }
 
+func TestParseMonth(t *testing.T) {
+	// Test case 1: Month names and numbers, including the boundaries
+	for input, expected := range map[string]time.Month{"January": time.January, "1": time.January, "09": time.September, "12": time.December, "December": time.December} {
+		month, err := ParseMonth(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, month, input)
+	}
+
+	// Test case 2: Out of range numbers and unknown names
+	for _, input := range []string{"0", "13", "-1", "sept", ""} {
+		_, err := ParseMonth(input)
+		assert.Error(t, err, input)
+	}
+}
 
 func TestReviewYear(t *testing.T) {
 	// Setup a temporary journal directory
@@ -306,7 +360,7 @@ func TestReviewYear(t *testing.T) {
 	aiCfg.DailyTemplate = cfg.DailyTemplate
 	aiCfg.AISummarizer = aiSummarizer
 
-	result, err := ReviewYear(aiCfg, year, aiSummarizer, strings.NewReader(""))
+	result, err := ReviewYear(aiCfg, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage := fmt.Sprintf("Yearly review generated at: %s", filepath.Join(tmpDir, "review_year_2025.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -342,7 +396,7 @@ func TestReviewYear(t *testing.T) {
 
 	// Re-create the review file to ensure it's clean for manual input
 	os.Remove(reviewFilePath)
-	result, err = ReviewYear(manualCfg, year, nil, manualReader)
+	result, err = ReviewYear(manualCfg, year, nil, manualReader, ReviewOptions{})
 	assert.NoError(t, err)
 	expectedSuccessMessage = fmt.Sprintf("Yearly review generated at: %s", filepath.Join(tmpDir, "review_year_2025.md"))
 	assert.Equal(t, expectedSuccessMessage, result)
@@ -372,8 +426,8 @@ func TestReviewYear(t *testing.T) {
 	noEntriesCfg.DailyTemplate = cfg.DailyTemplate
 	noEntriesCfg.AISummarizer = nil
 
-	os.Remove(reviewFilePath) // Clean up previous review file
-	result, err = ReviewYear(noEntriesCfg, year, nil, strings.NewReader("\n")) // Simulate skipping manual summary
+	os.Remove(reviewFilePath)                                                                   // Clean up previous review file
+	result, err = ReviewYear(noEntriesCfg, year, nil, strings.NewReader("\n"), ReviewOptions{}) // Simulate skipping manual summary
 	assert.NoError(t, err)
 	assert.Contains(t, result, fmt.Sprintf("Yearly review generated at: %s", filepath.Join(noEntriesTmpDir, "review_year_2025.md")))
 
@@ -391,8 +445,516 @@ func TestReviewYear(t *testing.T) {
 	// Test case 4: Error during manual summary input
 	errorReader := &ErrorReader{Err: errors.New("read error during manual summary")}
 	os.Remove(reviewFilePath) // Clean up previous review file
-	_, err = ReviewYear(noEntriesCfg, year, nil, errorReader)
+	_, err = ReviewYear(noEntriesCfg, year, nil, errorReader, ReviewOptions{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to generate summary for yearly review: failed to read manual summary: read error during manual summary")
+
+	// Test case 5: Review files go to ReviewDir when it differs from JournalDir
+	reviewDir := filepath.Join(t.TempDir(), "reviews")
+	aiCfg.ReviewDir = reviewDir
+	os.Remove(filepath.Join(tmpDir, "review_year_2025.md"))
+	result, err = ReviewYear(aiCfg, year, aiSummarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("Yearly review generated at: %s", filepath.Join(reviewDir, "review_year_2025.md")), result)
+	assert.FileExists(t, filepath.Join(reviewDir, "review_year_2025.md"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "review_year_2025.md"))
+}
+
+func TestNavigationLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	createReviewFile := func(name string) {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte("# Review\n"), 0644)
+		assert.NoError(t, err)
+	}
+
+	// Test case 1: Both adjacent weekly reviews exist
+	createReviewFile("review_week_2025_37.md")
+	createReviewFile("review_week_2025_39.md")
+	prev, next, err := NavigationLinks(cfg, "week", map[string]int{"week": 38, "year": 2025})
+	assert.NoError(t, err)
+	assert.Equal(t, "← [Week 37, 2025](review_week_2025_37.md)", prev)
+	assert.Equal(t, "→ [Week 39, 2025](review_week_2025_39.md)", next)
+
+	// Test case 2: The next review does not exist, so its link is omitted
+	prev, next, err = NavigationLinks(cfg, "week", map[string]int{"week": 39, "year": 2025})
+	assert.NoError(t, err)
+	assert.Empty(t, prev) // week 38 review does not exist
+	assert.Empty(t, next)
+
+	// Test case 3: ISO year boundary, week 1 of 2026 follows week 52 of 2025
+	createReviewFile("review_week_2025_52.md")
+	createReviewFile("review_week_2026_2.md")
+	prev, next, err = NavigationLinks(cfg, "week", map[string]int{"week": 1, "year": 2026})
+	assert.NoError(t, err)
+	assert.Equal(t, "← [Week 52, 2025](review_week_2025_52.md)", prev)
+	assert.Equal(t, "→ [Week 2, 2026](review_week_2026_2.md)", next)
+
+	// Test case 4: ISO year with 53 weeks
+	createReviewFile("review_week_2021_1.md")
+	_, next, err = NavigationLinks(cfg, "week", map[string]int{"week": 53, "year": 2020})
+	assert.NoError(t, err)
+	assert.Equal(t, "→ [Week 1, 2021](review_week_2021_1.md)", next)
+
+	// Test case 5: Monthly reviews across the year boundary
+	createReviewFile("review_month_December_2024.md")
+	prev, next, err = NavigationLinks(cfg, "month", map[string]int{"month": 1, "year": 2025})
+	assert.NoError(t, err)
+	assert.Equal(t, "← [December 2024](review_month_December_2024.md)", prev)
+	assert.Empty(t, next)
+
+	// Test case 6: Unsupported review type and missing parameters
+	_, _, err = NavigationLinks(cfg, "decade", map[string]int{"year": 2025})
+	assert.ErrorContains(t, err, "unsupported review type")
+	_, _, err = NavigationLinks(cfg, "week", map[string]int{"year": 2025})
+	assert.ErrorContains(t, err, "missing week")
+
+	// Test case 7: ReviewWeek adds the navigation bar below the title
+	aiSummarizer := &ai.MockAISummarizer{Summary: "AI generated weekly summary."}
+	_, err = ReviewWeek(cfg, 38, 2025, aiSummarizer, strings.NewReader(""), ReviewOptions{LinkedNavigation: true})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Weekly Review - Week 38, 2025\n← [Week 37, 2025](review_week_2025_37.md) | → [Week 39, 2025](review_week_2025_39.md)\n\nAI generated weekly summary.\n\n"))
+}
+
+func TestReviewSummaryLanguage(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	basePrompt := "Write a summary of the weekly review using the same Language. Use 1st person and a simple language. Use 200 characters or less."
+
+	// Test case 1: The language instruction is appended at the end of the prompt
+	summarizer := &ai.RecordingMockSummarizer{Summary: "Resumen semanal."}
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{AILanguage: "Spanish"})
+	assert.NoError(t, err)
+	assert.Len(t, summarizer.Calls, 1)
+	assert.Equal(t, basePrompt+" Respond in Spanish.", summarizer.Calls[0].Prompt)
+
+	// Test case 2: The configured default language is used when the option is not set
+	cfg.DefaultAILanguage = "French"
+	summarizer = &ai.RecordingMockSummarizer{Summary: "Résumé mensuel."}
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, summarizer.Calls, 1)
+	assert.True(t, strings.HasSuffix(summarizer.Calls[0].Prompt, " Respond in French."))
+
+	// Test case 3: The option takes precedence over the configured default
+	summarizer = &ai.RecordingMockSummarizer{Summary: "Jahresrückblick."}
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{AILanguage: "German"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(summarizer.Calls[0].Prompt, " Respond in German."))
+
+	// Test case 4: An empty language does not change the prompt
+	cfg.DefaultAILanguage = ""
+	summarizer = &ai.RecordingMockSummarizer{Summary: "Weekly summary."}
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, basePrompt, summarizer.Calls[0].Prompt)
+}
+
+func TestReviewAIProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	gemini := &ai.RecordingMockSummarizer{Summary: "Summary by Gemini."}
+	claude := &ai.RecordingMockSummarizer{Summary: "Summary by Claude."}
+	cfg.AIProfiles = map[string]config.AIProfile{
+		"gemini": {Command: "gemini '{TEXT}'", Summarizer: gemini},
+		"claude": {Command: "claude '{TEXT}'", Summarizer: claude},
+	}
+	fallback := &ai.RecordingMockSummarizer{Summary: "Default summary."}
+
+	// Test case 1: The selected profile is used instead of the given summarizer
+	_, err := ReviewWeek(cfg, 38, 2025, fallback, strings.NewReader(""), ReviewOptions{AIProfile: "gemini"})
+	assert.NoError(t, err)
+	assert.Len(t, gemini.Calls, 1)
+	assert.Empty(t, claude.Calls)
+	assert.Empty(t, fallback.Calls)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Summary by Gemini.")
+
+	// Test case 2: Another profile
+	_, err = ReviewWeek(cfg, 38, 2025, fallback, strings.NewReader(""), ReviewOptions{AIProfile: "claude"})
+	assert.NoError(t, err)
+	assert.Len(t, gemini.Calls, 1)
+	assert.Len(t, claude.Calls, 1)
+	assert.Empty(t, fallback.Calls)
+
+	// Test case 3: Without a profile the given summarizer is used
+	_, err = ReviewWeek(cfg, 38, 2025, fallback, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, fallback.Calls, 1)
+
+	// Test case 4: Unknown profile
+	_, err = ReviewWeek(cfg, 38, 2025, fallback, strings.NewReader(""), ReviewOptions{AIProfile: "copilot"})
+	assert.ErrorContains(t, err, "unknown AI profile: copilot")
+}
+
+func TestSortFilesByWordCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(day int, body string) string {
+		filePath := filepath.Join(tmpDir, fmt.Sprintf("2025-09-%02d.md", day))
+		content := fmt.Sprintf("# Sep %02d 2025\n<!-- summary below -->\n%s\n\n# LOG\n", day, body)
+		os.WriteFile(filePath, []byte(content), 0644)
+		return filePath
+	}
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	sep15 := writeFile(15, "two words")
+	sep16 := writeFile(16, "one")
+	sep17 := writeFile(17, "three words here")
+	sep18 := writeFile(18, "also two")
+	files := []string{sep15, sep16, sep17, sep18}
+
+	// Test case 1: Ascending order, ties keep the date order
+	sorted, err := SortFilesByWordCount(cfg, files, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{sep16, sep15, sep18, sep17}, sorted)
+
+	// Test case 2: Descending order, ties keep the date order
+	sorted, err = SortFilesByWordCount(cfg, files, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{sep17, sep15, sep18, sep16}, sorted)
+	assert.Equal(t, []string{sep15, sep16, sep17, sep18}, files, "input slice must not be modified")
+
+	// Test case 3: Missing file
+	_, err = SortFilesByWordCount(cfg, []string{filepath.Join(tmpDir, "missing.md")}, true)
+	assert.ErrorContains(t, err, "failed to read journal file")
+
+	// Test case 4: The weekly review emits the daily sections by word count, with their date
+	summarizer := &ai.MockAISummarizer{Summary: "Weekly summary."}
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{SortDailySummariesBy: SortByWordCountDesc})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-17\nthree words here\n\n### 2025-09-15\ntwo words\n\n### 2025-09-18\nalso two\n\n### 2025-09-16\none\n")
+
+	// Test case 5: The default date order is unchanged
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-15\ntwo words\n\n### 2025-09-16\none\n\n### 2025-09-17\nthree words here\n\n### 2025-09-18\nalso two\n")
+
+	// Test case 6: Unknown sort order
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{SortDailySummariesBy: "length"})
+	assert.ErrorContains(t, err, "unknown daily summaries sort order")
+}
+
+func TestMonthlyEntryGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(date time.Time, entries int) {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("# %s\nSummary.\n\n# LOG\n", date.Format("Jan 02 2006")))
+		for i := 0; i < entries; i++ {
+			sb.WriteString(fmt.Sprintf("%02d:00 Entry %d\n", 8+i, i))
+		}
+		os.WriteFile(filepath.Join(tmpDir, date.Format("2006-01-02")+".md"), []byte(sb.String()), 0644)
+	}
+	writeFile(time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC), 3)
+	writeFile(time.Date(2025, time.January, 11, 0, 0, 0, 0, time.UTC), 2)
+	writeFile(time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC), 10)
+	writeFile(time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC), 1)
+
+	// Test case 1: One column per month, scaled on the highest bar
+	graph, err := MonthlyEntryGraph(cfg, 2025)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(graph, "\n"), "\n")
+	assert.Len(t, lines, 10+2)
+	assert.Equal(t, "10 |         ###", lines[0], "only March reaches the top row")
+	assert.Equal(t, "   | ###     ###", lines[5], "January has half the entries of March")
+	assert.Equal(t, "   | ###     ###                                 ###", lines[9])
+	assert.Equal(t, "     Jan Feb Mar Apr May Jun Jul Aug Sep Oct Nov Dec", lines[11])
+	assert.Len(t, strings.Fields(lines[11]), 12)
+
+	// Test case 2: Months without entries have an empty bar
+	for _, line := range lines[:10] {
+		assert.Equal(t, "   ", line[9:12], "February must be empty")
+	}
+
+	// Test case 3: A chart height of 1 produces a single row
+	cfg.ChartHeight = 1
+	graph, err = MonthlyEntryGraph(cfg, 2025)
+	assert.NoError(t, err)
+	lines = strings.Split(strings.TrimSuffix(graph, "\n"), "\n")
+	assert.Len(t, lines, 1+2)
+	assert.Equal(t, "10 | ###     ###                                 ###", lines[0])
+
+	// Test case 4: The yearly review includes the graph after the summary
+	summarizer := &ai.MockAISummarizer{Summary: "Yearly summary."}
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeEntryGraph: true})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_year_2025.md"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# Yearly Review - 2025\nYearly summary.\n\n## Entries per Month\n\n```\n10 | ###"))
+}
+
+func TestLinkifyIssueRefs(t *testing.T) {
+	jira := regexp.MustCompile(`(?P<id>JIRA-\d+)`)
+	jiraURL := "https://jira.example.com/browse/{{.ID}}"
+
+	// Test case 1: Every match becomes a link
+	result, err := LinkifyIssueRefs("Fixed JIRA-1234 and reviewed JIRA-7.", jira, jiraURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed [JIRA-1234](https://jira.example.com/browse/JIRA-1234) and reviewed [JIRA-7](https://jira.example.com/browse/JIRA-7).", result)
+
+	// Test case 2: The id group can be a part of the match
+	github := regexp.MustCompile(`https://github\.com/org/repo/issues/(?P<id>\d+)`)
+	result, err = LinkifyIssueRefs("See https://github.com/org/repo/issues/123 for details", github, "https://github.com/org/repo/issues/{{.ID}}")
+	assert.NoError(t, err)
+	assert.Equal(t, "See [https://github.com/org/repo/issues/123](https://github.com/org/repo/issues/123) for details", result)
+
+	// Test case 3: Text without matches, or without a pattern, is unchanged
+	result, err = LinkifyIssueRefs("Nothing to link here.", jira, jiraURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "Nothing to link here.", result)
+	result, err = LinkifyIssueRefs("JIRA-1234", nil, jiraURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "JIRA-1234", result)
+
+	// Test case 4: Invalid URL template
+	_, err = LinkifyIssueRefs("JIRA-1234", jira, "{{.ID")
+	assert.ErrorContains(t, err, "failed to render issue link for JIRA-1234")
+
+	// Test case 5: The monthly review links the issues of the daily summaries
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.IssueLinkPattern = `(?P<id>JIRA-\d+)`
+	cfg.IssueLinkTemplate = jiraURL
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-18.md"), []byte("# Sep 18 2025\nClosed JIRA-42.\n\n# LOG\n"), 0644)
+
+	_, err = ReviewMonth(cfg, "September", 2025, &ai.MockAISummarizer{Summary: "Monthly summary."}, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_month_September_2025.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-18\nClosed [JIRA-42](https://jira.example.com/browse/JIRA-42).\n")
+}
+
+func TestExtractLearnings(t *testing.T) {
+	// Test case 1: One item per line, without list markers and duplicates
+	mockAI := &ai.RecordingMockSummarizer{Summary: "- Go\n* Docker\n\n1. Test-driven development\n- go\n• Kubernetes"}
+	learnings, err := ExtractLearnings([]string{"Wrote Go code", "Built a Docker image"}, mockAI, "Extract tools")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Docker", "Test-driven development", "Kubernetes"}, learnings)
+	assert.Equal(t, "Wrote Go code\nBuilt a Docker image", mockAI.Calls[0].Text)
+	assert.Equal(t, "Extract tools", mockAI.Calls[0].Prompt)
+
+	// Test case 2: No entries, no AI call
+	mockAI = &ai.RecordingMockSummarizer{Summary: "- Go"}
+	learnings, err = ExtractLearnings(nil, mockAI, "Extract tools")
+	assert.NoError(t, err)
+	assert.Empty(t, learnings)
+	assert.Empty(t, mockAI.Calls)
+
+	// Test case 3: AI errors
+	_, err = ExtractLearnings([]string{"entry"}, &ai.MockAISummarizer{Err: errors.New("AI error")}, "Extract tools")
+	assert.ErrorContains(t, err, "AI error")
+	_, err = ExtractLearnings([]string{"entry"}, nil, "Extract tools")
+	assert.ErrorContains(t, err, "AI summarizer is not configured")
+
+	// Test case 4: Entries are limited to the AI context size
+	assert.Equal(t, []string{"one two", "three"}, limitToTokens([]string{"one two", "three", "four five"}, 4))
+	assert.Equal(t, []string{"one two", "three"}, limitToTokens([]string{"one two", "three"}, 0))
+
+	// Test case 5: The yearly review has a section with the learnings
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-03-10.md"), []byte("# Mar 10 2025\nSummary.\n\n# LOG\n09:00 Tried Kubernetes\n"), 0644)
+	mockAI = &ai.RecordingMockSummarizer{Summary: "- Kubernetes\n- Helm"}
+
+	_, err = ReviewYear(cfg, 2025, mockAI, strings.NewReader(""), ReviewOptions{ExtractLearnings: true})
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_year_2025.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "## Skills & Tools Learned\n\n- Kubernetes\n- Helm\n")
+	assert.Equal(t, cfg.LearningExtractionPrompt, mockAI.Calls[len(mockAI.Calls)-1].Prompt)
+
+	// Test case 6: The section is omitted by default
+	_, err = ReviewYear(cfg, 2025, mockAI, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_year_2025.md"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "Skills & Tools Learned")
+}
+
+func TestReviewEncryptedSummaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	summarizer := &ai.MockAISummarizer{Summary: "Weekly summary."}
+
+	// Week 38, 2025: Monday, Sep 15 to Sunday, Sep 21
+	plainFile := filepath.Join(tmpDir, "2025-09-15.md")
+	encryptedFile := filepath.Join(tmpDir, "2025-09-16.md")
+	os.WriteFile(plainFile, []byte("# Sep 15 2025\nShipped the release.\n\n# LOG\n10:00 Release\n"), 0644)
+	os.WriteFile(encryptedFile, []byte("# Sep 16 2025\nTalked with HR about the new role.\n\n# LOG\n10:00 Meeting\n"), 0644)
+	assert.NoError(t, journal.EncryptSummary(encryptedFile, "s3cret"))
+	reviewFile := filepath.Join(tmpDir, "review_week_2025_38.md")
+
+	// Test case 1: Without a passphrase, the files with an encrypted summary are skipped
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "### 2025-09-15\nShipped the release.\n\n")
+	assert.NotContains(t, string(content), "2025-09-16")
+
+	// Test case 2: With the passphrase, the summaries are decrypted
+	os.Remove(reviewFile)
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{PassPhrase: "s3cret"})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "### 2025-09-16\nTalked with HR about the new role.\n\n")
+
+	// Test case 3: Wrong passphrase
+	os.Remove(reviewFile)
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{PassPhrase: "wrong"})
+	assert.ErrorContains(t, err, "failed to decrypt the summary")
+}
+
+func TestReviewFullLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# 2025-09-15\n\nFixed bugs.\n\n# LOG\n\n09:00 Fixed the parser\n10:30 Reviewed a PR\n\n# One-line note\n- Old note\n"), 0644))
+	summarizer := &ai.MockAISummarizer{Summary: "A productive period."}
+	opts := DefaultReviewOptions()
+	opts.IncludeFullLog = true
+
+	// Test case 1: The LOG of each day follows its summary in the weekly review
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), opts)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-15\nFixed bugs.\n\n09:00 Fixed the parser\n10:30 Reviewed a PR\n\n")
+	assert.NotContains(t, string(content), "Old note")
+
+	// Test case 2: The monthly review
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), opts)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_month_September_2025.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### 2025-09-15\nFixed bugs.\n\n09:00 Fixed the parser\n10:30 Reviewed a PR\n\n")
+
+	// Test case 3: The yearly review, indented in the list item of the day
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), opts)
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_year_2025.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "- **2025-09-15**: Fixed bugs.\n\n  09:00 Fixed the parser\n  10:30 Reviewed a PR\n\n")
+
+	// Test case 4: Without the option only the summaries are written
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), DefaultReviewOptions())
+	assert.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "09:00 Fixed the parser")
 }
 
+func TestFindLastReviewedWeek(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	writeReview := func(name string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte("# Review\n"), 0644))
+	}
+
+	// Test case 1: No weekly review
+	_, _, err := FindLastReviewedWeek(cfg)
+	assert.ErrorIs(t, err, ErrNoWeeklyReview)
+
+	// Test case 2: The most recent week, by year then week, not by file name
+	writeReview("review_week_2024_52.md")
+	writeReview("review_week_2025_9.md")
+	writeReview("review_week_2025_10.md")
+	writeReview("review_week_2025_3.md")
+	writeReview("review_month_December_2025.md")
+	writeReview("review_week_2025_99.md") // Not a valid week
+	writeReview("review_week_2025_11.md.bak")
+	week, year, err := FindLastReviewedWeek(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, week)
+	assert.Equal(t, 2025, year)
+
+	// Test case 3: The reviews of the review directory
+	cfg.ReviewDir = t.TempDir()
+	_, _, err = FindLastReviewedWeek(cfg)
+	assert.ErrorIs(t, err, ErrNoWeeklyReview)
+	assert.NoError(t, os.WriteFile(filepath.Join(cfg.ReviewDir, "review_week_2026_1.md"), []byte("# Review\n"), 0644))
+	week, year, err = FindLastReviewedWeek(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, week)
+	assert.Equal(t, 2026, year)
+}
+
+func TestWeeksAfter(t *testing.T) {
+	// Test case 1: The weeks up to the current one, across the year boundary (2026-01-07 is in week 2 of 2026)
+	now := time.Date(2026, time.January, 7, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []ISOWeek{{2025, 51}, {2025, 52}, {2026, 1}, {2026, 2}}, WeeksAfter(50, 2025, now))
+
+	// Test case 2: A year with 53 weeks, 2020
+	assert.Equal(t, []ISOWeek{{2020, 53}, {2021, 1}}, WeeksAfter(52, 2020, time.Date(2021, time.January, 5, 0, 0, 0, 0, time.UTC)))
+
+	// Test case 3: The current week, or a later one, is already reviewed
+	assert.Empty(t, WeeksAfter(2, 2026, now))
+	assert.Empty(t, WeeksAfter(5, 2026, now))
+}
+
+func TestEncryptedReviews(t *testing.T) {
+	defer func() { fileutil.EncryptionKey = nil }()
+	fileutil.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	for _, day := range []string{"2025-09-15", "2025-09-17"} {
+		content := fmt.Sprintf("# %s\n\nTalked with HR on %s.\n\n# LOG\n09:00 Talked with HR\n", day, day)
+		assert.NoError(t, fileutil.AtomicWrite(filepath.Join(tmpDir, day+".md"), []byte(content), 0644))
+	}
+	summarizer := &ai.MockAISummarizer{Summary: "A private week."}
+
+	// Test case 1: The weekly review is written encrypted, its summary and daily summaries included
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), DefaultReviewOptions())
+	assert.NoError(t, err)
+	reviewFile := filepath.Join(tmpDir, "review_week_2025_38.md")
+	raw, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(raw), "HR")
+	assert.NotContains(t, string(raw), "Daily Summaries")
+	content, err := fileutil.ReadFile(reviewFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "A private week.")
+	assert.Contains(t, string(content), "Talked with HR on 2025-09-17.")
+	meta, err := ReadSidecar(reviewFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "A private week.", meta.Summary)
+
+	// Test case 2: The monthly review, including the encrypted weekly review
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeWeeklyReviews: true})
+	assert.NoError(t, err)
+	reviewFile = filepath.Join(tmpDir, "review_month_September_2025.md")
+	raw, _ = os.ReadFile(reviewFile)
+	assert.NotContains(t, string(raw), "HR")
+	content, err = fileutil.ReadFile(reviewFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "### Week 38\nA private week.\n")
+	assert.Contains(t, string(content), "## Daily Summaries\n\n### 2025-09-15\nTalked with HR on 2025-09-15.\n")
+
+	// Test case 3: The yearly review
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	reviewFile = filepath.Join(tmpDir, "review_year_2025.md")
+	content, err = fileutil.ReadFile(reviewFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "A private week.")
+}
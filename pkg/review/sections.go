@@ -0,0 +1,130 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/template"
+)
+
+// ReviewTemplateData holds the data available to the templates of Config.ReviewCustomSections.
+type ReviewTemplateData struct {
+	Period    string // e.g. "Week 38, 2025", "September 2025" or "2025"
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// sectionPositionOrder sorts the sections inserted at the same offset by position.
+var sectionPositionOrder = map[string]int{
+	config.ReviewSectionBeforeSummary:       0,
+	config.ReviewSectionAfterSummary:        1,
+	config.ReviewSectionAfterDailySummaries: 2,
+}
+
+// BuildReviewWithCustomSections inserts the enabled Config.ReviewCustomSections into the content of a review,
+// in configuration order for the sections sharing a position:
+//   - "before-summary" goes right after the title and the navigation bar;
+//   - "after-summary" goes before the first chapter, e.g. "## Daily Summaries";
+//   - "after-daily-summaries" goes after the "## Daily Summaries" or "## Monthly Summaries" chapter.
+func BuildReviewWithCustomSections(cfg *config.Config, baseContent string, reviewData ReviewTemplateData) (string, error) {
+	texts := make(map[string]string)
+	for _, section := range cfg.ReviewCustomSections {
+		if !section.Enabled {
+			continue
+		}
+		body, err := template.RenderData(section.Template, reviewData)
+		if err != nil {
+			return "", fmt.Errorf("failed to render review section %q: %w", section.Title, err)
+		}
+		text := "## " + section.Title + "\n\n"
+		if body = strings.TrimSpace(body); body != "" {
+			text += body + "\n\n"
+		}
+		texts[section.Position] += text
+	}
+	if len(texts) == 0 {
+		return baseContent, nil
+	}
+
+	offsets := sectionOffsets(baseContent)
+	positions := make([]string, 0, len(texts))
+	for position := range texts {
+		positions = append(positions, position)
+	}
+	// Insert from the end, so that the offsets of the earlier positions stay valid
+	sort.Slice(positions, func(i, j int) bool {
+		if offsets[positions[i]] != offsets[positions[j]] {
+			return offsets[positions[i]] > offsets[positions[j]]
+		}
+		return sectionPositionOrder[positions[i]] > sectionPositionOrder[positions[j]]
+	})
+
+	content := baseContent
+	for _, position := range positions {
+		offset := offsets[position]
+		before := content[:offset]
+		if !strings.HasSuffix(before, "\n\n") {
+			before = strings.TrimRight(before, "\n") + "\n\n"
+		}
+		content = before + texts[position] + content[offset:]
+	}
+	return content, nil
+}
+
+// sectionOffsets returns the offsets of the content where the custom sections of each position are inserted.
+func sectionOffsets(content string) map[string]int {
+	var lineStarts []int
+	var lines []string
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineStarts = append(lineStarts, offset)
+		lines = append(lines, strings.TrimRight(line, "\n"))
+		offset += len(line)
+	}
+	lineStarts = append(lineStarts, len(content)) // The end of the content, past the last line
+	startOf := func(i int) int { return lineStarts[min(i, len(lines))] }
+
+	// The title is followed by the navigation bar, if any
+	headEnd := 1
+	if len(lines) > 1 && (strings.HasPrefix(lines[1], "← [") || strings.HasPrefix(lines[1], "→ [")) {
+		headEnd = 2
+	}
+
+	summaryStart := headEnd
+	for summaryStart < len(lines) && strings.TrimSpace(lines[summaryStart]) == "" {
+		summaryStart++
+	}
+
+	bodyStart := headEnd
+	for bodyStart < len(lines) && !strings.HasPrefix(lines[bodyStart], "## ") && !strings.HasPrefix(lines[bodyStart], "No journal entries found") {
+		bodyStart++
+	}
+
+	// The daily summaries end at the next chapter, the message about missing entries is kept before the sections
+	dailyEnd := len(lines)
+	for i := bodyStart; i < len(lines); i++ {
+		if lines[i] == "## Daily Summaries" || lines[i] == "## Monthly Summaries" {
+			dailyEnd = i + 1
+			for dailyEnd < len(lines) && !strings.HasPrefix(lines[dailyEnd], "## ") {
+				dailyEnd++
+			}
+			break
+		}
+		if strings.HasPrefix(lines[i], "No journal entries found") {
+			dailyEnd = i + 1
+			for dailyEnd < len(lines) && strings.TrimSpace(lines[dailyEnd]) == "" {
+				dailyEnd++
+			}
+			break
+		}
+	}
+
+	return map[string]int{
+		config.ReviewSectionBeforeSummary:       startOf(summaryStart),
+		config.ReviewSectionAfterSummary:        startOf(bodyStart),
+		config.ReviewSectionAfterDailySummaries: startOf(dailyEnd),
+	}
+}
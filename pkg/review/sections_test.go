@@ -0,0 +1,85 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReviewWithCustomSections(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\nWorked on the parser.\n\n# LOG\n09:00 Parser\n"), 0644)
+
+	cfg.ReviewCustomSections = []config.ReviewCustomSection{
+		{Title: "Action Items", Position: config.ReviewSectionAfterDailySummaries, Template: "", Enabled: true},
+		{Title: "Goals", Position: config.ReviewSectionBeforeSummary, Template: "Goals for {{.Period}}:\n- [ ] ", Enabled: true},
+		{Title: "Disabled", Position: config.ReviewSectionAfterSummary, Template: "Never shown", Enabled: false},
+	}
+	summarizer := &ai.MockAISummarizer{Summary: "A week of parsing."}
+
+	// Test case 1: Sections appear at their positions in the weekly review
+	_, err := ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{CrossReference: true})
+	assert.NoError(t, err)
+	contentBytes, err := os.ReadFile(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	assert.NoError(t, err)
+	content := string(contentBytes)
+
+	title := strings.Index(content, "# Weekly Review - Week 38, 2025")
+	goals := strings.Index(content, "## Goals\n\nGoals for Week 38, 2025:\n- [ ]\n\n")
+	summary := strings.Index(content, "A week of parsing.")
+	daily := strings.Index(content, "## Daily Summaries")
+	actionItems := strings.Index(content, "## Action Items\n\n## Cross References")
+	crossRefs := strings.Index(content, "## Cross References")
+	assert.True(t, title >= 0 && goals > title, "Goals should follow the title")
+	assert.True(t, summary > goals, "Goals should precede the summary")
+	assert.True(t, daily > summary)
+	assert.True(t, actionItems > daily, "Action Items should follow the daily summaries")
+	assert.True(t, crossRefs > actionItems)
+	assert.NotContains(t, content, "Never shown")
+
+	// Test case 2: Without enabled sections the content is unchanged
+	base := "# Monthly Review - September 2025\nSummary.\n\n## Daily Summaries\n\n### 2025-09-15\nParser.\n\n"
+	cfg.ReviewCustomSections = []config.ReviewCustomSection{{Title: "Disabled", Position: config.ReviewSectionAfterSummary}}
+	result, err := BuildReviewWithCustomSections(cfg, base, ReviewTemplateData{Period: "September 2025"})
+	assert.NoError(t, err)
+	assert.Equal(t, base, result)
+
+	// Test case 3: All the positions, with a navigation bar and several sections at the same position
+	cfg.ReviewCustomSections = []config.ReviewCustomSection{
+		{Title: "Notes", Position: config.ReviewSectionAfterSummary, Template: "{{.Period}}", Enabled: true},
+		{Title: "Top", Position: config.ReviewSectionBeforeSummary, Enabled: true},
+		{Title: "Bottom", Position: config.ReviewSectionAfterDailySummaries, Enabled: true},
+		{Title: "More Notes", Position: config.ReviewSectionAfterSummary, Enabled: true},
+	}
+	base = "# Monthly Review - September 2025\n← [August 2025](review_month_2025_08.md)\n\nSummary.\n\n## Daily Summaries\n\n### 2025-09-15\nParser.\n\n"
+	result, err = BuildReviewWithCustomSections(cfg, base, ReviewTemplateData{Period: "September 2025"})
+	assert.NoError(t, err)
+	expected := "# Monthly Review - September 2025\n← [August 2025](review_month_2025_08.md)\n\n" +
+		"## Top\n\n" +
+		"Summary.\n\n" +
+		"## Notes\n\nSeptember 2025\n\n" +
+		"## More Notes\n\n" +
+		"## Daily Summaries\n\n### 2025-09-15\nParser.\n\n" +
+		"## Bottom\n\n"
+	assert.Equal(t, expected, result)
+
+	// Test case 4: A review without entries nor summary
+	base = "# Yearly Review - 2025\n\nNo journal entries found for this year.\n\n"
+	result, err = BuildReviewWithCustomSections(cfg, base, ReviewTemplateData{Period: "2025"})
+	assert.NoError(t, err)
+	expected = "# Yearly Review - 2025\n\n## Top\n\n## Notes\n\n2025\n\n## More Notes\n\n" +
+		"No journal entries found for this year.\n\n## Bottom\n\n"
+	assert.Equal(t, expected, result)
+
+	// Test case 5: Invalid template
+	cfg.ReviewCustomSections = []config.ReviewCustomSection{{Title: "Broken", Position: config.ReviewSectionAfterSummary, Template: "{{.Unknown}}", Enabled: true}}
+	_, err = BuildReviewWithCustomSections(cfg, base, ReviewTemplateData{Period: "2025"})
+	assert.ErrorContains(t, err, "failed to render review section \"Broken\"")
+}
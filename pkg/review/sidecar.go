@@ -0,0 +1,123 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// ReviewMeta is the machine-readable metadata of a review, stored in a JSON sidecar file next to it.
+type ReviewMeta struct {
+	Type        string    `json:"type"` // One of "week", "month", "quarter", "year" or "custom"
+	Week        int       `json:"week,omitempty"`
+	Month       string    `json:"month,omitempty"`
+	Quarter     int       `json:"quarter,omitempty"`
+	Start       string    `json:"start,omitempty"` // First day of a custom review, as YYYY-MM-DD
+	End         string    `json:"end,omitempty"`   // Last day of a custom review, as YYYY-MM-DD
+	Year        int       `json:"year"`
+	GeneratedAt time.Time `json:"generated_at"`
+	EntryCount  int       `json:"entry_count"`
+	WordCount   int       `json:"word_count"`
+	Summary     string    `json:"summary"`
+	Files       []string  `json:"files"` // Names of the journal files of the period
+}
+
+// sidecarPath returns the path of the sidecar file of a review, e.g. review_month_September_2025.meta.json.
+// It differs from the path of the JSON review written with OutputFormatJSON, e.g. review_month_September_2025.json.
+func sidecarPath(reviewFilePath string) string {
+	return strings.TrimSuffix(reviewFilePath, filepath.Ext(reviewFilePath)) + ".meta.json"
+}
+
+// WriteSidecar writes the metadata of a review to its sidecar file.
+func WriteSidecar(reviewFilePath string, meta ReviewMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode review metadata: %w", err)
+	}
+	if err := fileutil.AtomicWrite(sidecarPath(reviewFilePath), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write review metadata file: %w", err)
+	}
+	return nil
+}
+
+// ReadSidecar reads the metadata of a review from its sidecar file.
+func ReadSidecar(reviewFilePath string) (*ReviewMeta, error) {
+	path := sidecarPath(reviewFilePath)
+	data, err := fileutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review metadata file %s: %w", path, err)
+	}
+	var meta ReviewMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse review metadata file %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+// writeReviewSidecar writes the sidecar file of a review just written, covering the given journal files.
+// meta carries the period of the review, the other fields are computed.
+func writeReviewSidecar(cfg *config.Config, reviewFilePath string, journalFiles []string, meta ReviewMeta) error {
+	meta.GeneratedAt = time.Now().UTC().Truncate(time.Second)
+	meta.Files = []string{}
+	for _, filePath := range journalFiles {
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return err
+		}
+		words, err := journal.CountWords(cfg, filePath)
+		if err != nil {
+			return err
+		}
+		meta.EntryCount += len(entries)
+		meta.WordCount += words
+		meta.Files = append(meta.Files, filepath.Base(filePath))
+	}
+	summary, err := journal.ExtractSummaryFull(reviewFilePath)
+	if err != nil {
+		return err
+	}
+	meta.Summary = summary.Full
+	return WriteSidecar(reviewFilePath, meta)
+}
+
+// reviewFileFromMeta returns the review described by the metadata of its sidecar file.
+func reviewFileFromMeta(path string, meta *ReviewMeta) (ReviewFile, bool) {
+	switch meta.Type {
+	case "week":
+		if meta.Week < 1 || meta.Week > 53 {
+			return ReviewFile{}, false
+		}
+		start := isoWeekStart(meta.Week, meta.Year)
+		return ReviewFile{Path: path, Type: "week", StartDate: start, EndDate: start.AddDate(0, 0, 6), Meta: meta}, true
+	case "month":
+		month, err := time.Parse("January", meta.Month)
+		if err != nil {
+			return ReviewFile{}, false
+		}
+		start := time.Date(meta.Year, month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "month", StartDate: start, EndDate: start.AddDate(0, 1, -1), Meta: meta}, true
+	case "quarter":
+		if meta.Quarter < 1 || meta.Quarter > 4 {
+			return ReviewFile{}, false
+		}
+		start := time.Date(meta.Year, time.Month(3*(meta.Quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "quarter", StartDate: start, EndDate: start.AddDate(0, 3, -1), Meta: meta}, true
+	case "year":
+		start := time.Date(meta.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return ReviewFile{Path: path, Type: "year", StartDate: start, EndDate: start.AddDate(1, 0, -1), Meta: meta}, true
+	case "custom":
+		start, startErr := time.Parse("2006-01-02", meta.Start)
+		end, endErr := time.Parse("2006-01-02", meta.End)
+		if startErr != nil || endErr != nil || end.Before(start) {
+			return ReviewFile{}, false
+		}
+		return ReviewFile{Path: path, Type: "custom", StartDate: start, EndDate: end, Meta: meta}, true
+	}
+	return ReviewFile{}, false
+}
@@ -0,0 +1,94 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewSidecar(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-15.md"), []byte("# Sep 15 2025\n\nReleased.\n\n# LOG\n09:00 Released v2\n10:00 Wrote the notes\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-16.md"), []byte("# Sep 16 2025\n\nFixed.\n\n# LOG\n10:00 Fixed the hotfix\n"), 0644)
+	summarizer := &ai.RecordingMockSummarizer{Summary: "A release month."}
+
+	// Test case 1: The monthly review writes its sidecar with the values of the period
+	before := time.Now().UTC().Add(-time.Second)
+	_, err := ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	reviewFile := filepath.Join(tmpDir, "review_month_September_2025.md")
+	assert.FileExists(t, filepath.Join(tmpDir, "review_month_September_2025.meta.json"))
+	meta, err := ReadSidecar(reviewFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "month", meta.Type)
+		assert.Equal(t, "September", meta.Month)
+		assert.Equal(t, 2025, meta.Year)
+		assert.Equal(t, 3, meta.EntryCount)
+		assert.Equal(t, 13, meta.WordCount)
+		assert.Equal(t, "A release month.", meta.Summary)
+		assert.Equal(t, []string{"2025-09-15.md", "2025-09-16.md"}, meta.Files)
+		assert.False(t, meta.GeneratedAt.Before(before))
+	}
+
+	// Test case 2: The weekly and yearly reviews write their sidecar too
+	_, err = ReviewWeek(cfg, 38, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	meta, err = ReadSidecar(filepath.Join(tmpDir, "review_week_2025_38.md"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "week", meta.Type)
+		assert.Equal(t, 38, meta.Week)
+		assert.Equal(t, 3, meta.EntryCount)
+	}
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	meta, err = ReadSidecar(filepath.Join(tmpDir, "review_year_2025.md"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "year", meta.Type)
+		assert.Equal(t, 2025, meta.Year)
+		assert.Len(t, meta.Files, 2)
+	}
+
+	// Test case 3: ReadSidecar round-trips WriteSidecar
+	written := ReviewMeta{Type: "week", Week: 12, Year: 2024, GeneratedAt: time.Date(2024, time.March, 24, 18, 0, 0, 0, time.UTC),
+		EntryCount: 4, WordCount: 40, Summary: "A quiet week.", Files: []string{"2024-03-18.md"}}
+	roundTrip := filepath.Join(tmpDir, "review_week_2024_12.md")
+	assert.NoError(t, WriteSidecar(roundTrip, written))
+	meta, err = ReadSidecar(roundTrip)
+	if assert.NoError(t, err) {
+		assert.Equal(t, written, *meta)
+	}
+
+	// Test case 4: ListReviews prefers the sidecar over the file name, and falls back to the file name without it
+	os.WriteFile(roundTrip, []byte("# Weekly Review - Week 12, 2024\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2024_13.md"), []byte("# Weekly Review - Week 13, 2024\n"), 0644)
+	reviews, err := ListReviews(cfg)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 5)
+	for _, review := range reviews {
+		switch filepath.Base(review.Path) {
+		case "review_week_2024_12.md":
+			if assert.NotNil(t, review.Meta) {
+				assert.Equal(t, "A quiet week.", review.Meta.Summary)
+			}
+			assert.Equal(t, time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC), review.StartDate)
+		case "review_week_2024_13.md":
+			assert.Nil(t, review.Meta)
+			assert.Equal(t, "week", review.Type)
+			assert.Equal(t, time.Date(2024, time.March, 25, 0, 0, 0, 0, time.UTC), review.StartDate)
+		}
+	}
+
+	// Test case 5: Deleting an orphaned review deletes its sidecar
+	deleted, err := DeleteOrphanedReviews(cfg, false)
+	assert.NoError(t, err)
+	assert.Contains(t, deleted, roundTrip)
+	assert.NoFileExists(t, filepath.Join(tmpDir, "review_week_2024_12.meta.json"))
+}
@@ -0,0 +1,100 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// defaultTopTags is the number of tags listed when ReviewOptions.TopTagsCount is not set.
+const defaultTopTags = 10
+
+// TagCount is the number of times a hashtag is used in a period.
+type TagCount struct {
+	Tag       string // The tag in lower case, without "#"
+	Count     int
+	FirstSeen time.Time // Date of the first journal file using the tag
+	LastSeen  time.Time // Date of the last journal file using the tag
+}
+
+// TopTags returns the topN most used hashtags of the year's journal files, sorted by count and then by tag.
+// Tags are case insensitive. Headers, HTML comments and code blocks are ignored.
+func TopTags(cfg *config.Config, year int, topN int) ([]TagCount, error) {
+	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	journalFiles, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files for %d: %w", year, err)
+	}
+
+	counts := make(map[string]*TagCount)
+	for _, filePath := range journalFiles {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			continue
+		}
+		content, err := fileutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+		}
+
+		inCodeBlock := false
+		for _, line := range strings.Split(string(content), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") {
+				inCodeBlock = !inCodeBlock
+				continue
+			}
+			if inCodeBlock || strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "<!--") {
+				continue
+			}
+			for _, match := range journal.HashtagPattern.FindAllStringSubmatch(line, -1) {
+				tag := strings.ToLower(match[1])
+				count, ok := counts[tag]
+				if !ok {
+					count = &TagCount{Tag: tag, FirstSeen: date}
+					counts[tag] = count
+				}
+				count.Count++
+				count.LastSeen = date
+			}
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, *count)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	if topN > 0 && len(tags) > topN {
+		tags = tags[:topN]
+	}
+	return tags, nil
+}
+
+// topTagsSection renders the most used tags as a Markdown table.
+func topTagsSection(tags []TagCount) string {
+	var sb strings.Builder
+	sb.WriteString("## Most Used Tags\n\n")
+	if len(tags) == 0 {
+		sb.WriteString("No tags used this year.\n\n")
+		return sb.String()
+	}
+	sb.WriteString("| Tag | Count | First Seen | Last Seen |\n")
+	sb.WriteString("|-----|-------|------------|-----------|\n")
+	for _, tag := range tags {
+		sb.WriteString(fmt.Sprintf("| #%s | %d | %s | %s |\n", tag.Tag, tag.Count, tag.FirstSeen.Format("2006-01-02"), tag.LastSeen.Format("2006-01-02")))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
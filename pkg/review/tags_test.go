@@ -0,0 +1,69 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	writeFile := func(name, content string) {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+	// #go: 4, #review: 2, #api: 2, #docs: 1; #ignored is in a header, a comment and a code block
+	writeFile("2025-02-03.md", "# Feb 03 2025 #ignored\n<!-- #ignored -->\n\n# LOG\n09:00 Started the #go rewrite of the #API\n")
+	writeFile("2025-05-12.md", "# May 12 2025\n\n# LOG\n09:00 #review of the #go code, fixed #123\n```sh\n#ignored\n```\n10:00 #Go #docs\n")
+	writeFile("2025-11-20.md", "# Nov 20 2025\n\n# LOG\n09:00 Another #review, the #api is done, #go\n")
+	writeFile("2024-12-31.md", "# Dec 31 2024\n\n# LOG\n09:00 #docs #docs #docs\n")
+
+	// Test case 1: Counts, sorted by count descending and then by tag
+	tags, err := TopTags(cfg, 2025, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []TagCount{
+		{Tag: "go", Count: 4, FirstSeen: time.Date(2025, time.February, 3, 0, 0, 0, 0, time.UTC), LastSeen: time.Date(2025, time.November, 20, 0, 0, 0, 0, time.UTC)},
+		{Tag: "api", Count: 2, FirstSeen: time.Date(2025, time.February, 3, 0, 0, 0, 0, time.UTC), LastSeen: time.Date(2025, time.November, 20, 0, 0, 0, 0, time.UTC)},
+		{Tag: "review", Count: 2, FirstSeen: time.Date(2025, time.May, 12, 0, 0, 0, 0, time.UTC), LastSeen: time.Date(2025, time.November, 20, 0, 0, 0, 0, time.UTC)},
+		{Tag: "docs", Count: 1, FirstSeen: time.Date(2025, time.May, 12, 0, 0, 0, 0, time.UTC), LastSeen: time.Date(2025, time.May, 12, 0, 0, 0, 0, time.UTC)},
+	}, tags)
+
+	// Test case 2: Only the top N tags
+	tags, err = TopTags(cfg, 2025, 2)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "api", tags[1].Tag)
+
+	// Test case 3: A year without tags
+	tags, err = TopTags(cfg, 2023, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+	assert.Equal(t, "## Most Used Tags\n\nNo tags used this year.\n\n", topTagsSection(tags))
+
+	// Test case 4: The yearly review has the table only when requested
+	summarizer := &ai.MockAISummarizer{Summary: "Yearly summary."}
+	reviewFile := filepath.Join(tmpDir, "review_year_2025.md")
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "## Most Used Tags")
+
+	os.Remove(reviewFile)
+	_, err = ReviewYear(cfg, 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeTopTags: true, TopTagsCount: 3})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "## Most Used Tags\n\n"+
+		"| Tag | Count | First Seen | Last Seen |\n"+
+		"|-----|-------|------------|-----------|\n"+
+		"| #go | 4 | 2025-02-03 | 2025-11-20 |\n"+
+		"| #api | 2 | 2025-02-03 | 2025-11-20 |\n"+
+		"| #review | 2 | 2025-05-12 | 2025-11-20 |\n\n")
+}
@@ -0,0 +1,71 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// monthWeeks returns the ISO weeks with at least one day in the month, in order.
+func monthWeeks(month time.Month, year int) []ISOWeek {
+	var weeks []ISOWeek
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	for d := start; d.Month() == month; d = d.AddDate(0, 0, 1) {
+		y, w := d.ISOWeek()
+		if len(weeks) == 0 || weeks[len(weeks)-1] != (ISOWeek{Year: y, Week: w}) {
+			weeks = append(weeks, ISOWeek{Year: y, Week: w})
+		}
+	}
+	return weeks
+}
+
+// weeklyReviewSummary returns the summary of a weekly review file, without the navigation links and the key insight
+// written above it. ok is false if the review does not exist.
+func weeklyReviewSummary(filePath string) (summary string, ok bool, err error) {
+	if _, err := os.Stat(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to check weekly review %s: %w", filePath, err)
+	}
+	result, err := journal.ExtractSummaryFull(filePath)
+	if err != nil {
+		return "", false, err
+	}
+	var paragraphs []string
+	for _, paragraph := range strings.Split(result.Full, "\n\n") {
+		if strings.HasPrefix(paragraph, "> 💡") || strings.Contains(paragraph, "](review_week_") {
+			continue
+		}
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return strings.Join(paragraphs, "\n\n"), true, nil
+}
+
+// weeklyReviewsMarkdown returns the "Weekly Reviews" chapter of the monthly review, with the summary of the
+// existing weekly reviews of the weeks of the month. It is empty if none of them exists.
+func weeklyReviewsMarkdown(cfg *config.Config, month time.Month, year int) (string, error) {
+	var b strings.Builder
+	for _, week := range monthWeeks(month, year) {
+		summary, ok, err := weeklyReviewSummary(filepath.Join(reviewDir(cfg), weekReviewFileName(week.Week, week.Year)))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		if summary == "" {
+			summary = "No summary."
+		}
+		b.WriteString(fmt.Sprintf("### Week %d\n%s\n\n", week.Week, summary))
+	}
+	if b.Len() == 0 {
+		return "", nil
+	}
+	return "## Weekly Reviews\n\n" + b.String(), nil
+}
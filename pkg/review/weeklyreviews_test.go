@@ -0,0 +1,56 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clobrano/LogBook/pkg/ai"
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewMonthWeeklyReviews(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-17.md"), []byte("# Sep 17 2025\nReleased v2.\n\n# LOG\n09:00 Released v2\n"), 0644)
+	// Weeks 36 to 40 have days in September 2025, week 35 ends on August 31st
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_35.md"), []byte("# Weekly Review - Week 35, 2025\n\nHolidays.\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_36.md"), []byte("# Weekly Review - Week 36, 2025\n"+
+		"[← Week 35, 2025](review_week_2025_35.md)\n\n> 💡 The parser is done.\n\nBack to work on the parser.\n\n## Daily Summaries\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_38.md"), []byte("# Weekly Review - Week 38, 2025\n\nReleased v2.\n\nWrote the notes.\n\n## Daily Summaries\n"), 0644)
+	summarizer := &ai.MockAISummarizer{Summary: "Monthly summary."}
+	reviewFile := filepath.Join(tmpDir, "review_month_September_2025.md")
+
+	// Test case 1: No weekly reviews by default
+	_, err := ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{})
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(reviewFile)
+	assert.NotContains(t, string(content), "## Weekly Reviews")
+
+	// Test case 2: The summaries of the existing weekly reviews of the month, before the daily summaries
+	os.Remove(reviewFile)
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeWeeklyReviews: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(reviewFile)
+	assert.Contains(t, string(content), "## Weekly Reviews\n\n"+
+		"### Week 36\nBack to work on the parser.\n\n"+
+		"### Week 38\nReleased v2.\n\nWrote the notes.\n\n"+
+		"## Daily Summaries\n\n### 2025-09-17\nReleased v2.\n")
+	assert.NotContains(t, string(content), "Holidays.")
+
+	// Test case 3: Without weekly reviews there is no chapter
+	os.Remove(reviewFile)
+	cfg.ReviewDir = filepath.Join(tmpDir, "reviews")
+	_, err = ReviewMonth(cfg, "September", 2025, summarizer, strings.NewReader(""), ReviewOptions{IncludeWeeklyReviews: true})
+	assert.NoError(t, err)
+	content, _ = os.ReadFile(filepath.Join(cfg.ReviewDir, "review_month_September_2025.md"))
+	assert.NotContains(t, string(content), "## Weekly Reviews")
+
+	// Test case 4: The ISO weeks of a month, across the new year
+	assert.Equal(t, []ISOWeek{{2025, 36}, {2025, 37}, {2025, 38}, {2025, 39}, {2025, 40}}, monthWeeks(9, 2025))
+	assert.Equal(t, []ISOWeek{{2026, 1}, {2026, 2}, {2026, 3}, {2026, 4}, {2026, 5}}, monthWeeks(1, 2026))
+	assert.Equal(t, []ISOWeek{{2024, 48}, {2024, 49}, {2024, 50}, {2024, 51}, {2024, 52}, {2025, 1}}, monthWeeks(12, 2024))
+}
@@ -0,0 +1,112 @@
+// Package search finds lines of the daily journal files, filtered by date and tags.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/fileutil"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// SearchOptions selects the lines returned by Search.
+type SearchOptions struct {
+	Query       string    // Text to find, case-insensitive unless Exact or Regex is set
+	Exact       bool      // Case-sensitive matching of Query
+	Regex       bool      // Query is a regular expression, see regexp.Compile
+	From, To    time.Time // Range of the daily files, a zero value leaves the range open on that side
+	Tags        []string  // Only search the files using all these tags, with or without "#"
+	SummaryOnly bool      // Only search the summary, not the LOG and the other chapters
+}
+
+// SearchResult is a line of a daily file matching the query of Search.
+type SearchResult struct {
+	FilePath   string
+	Date       time.Time // Date of the daily file
+	Line       string    // The matching line, trimmed
+	LineNumber int       // Starting from 1
+	Tags       []string  // Tags of the daily file, see journal.ExtractTags
+}
+
+// Search returns the lines of the daily files of cfg.JournalDir matching opts, oldest file first and in file order.
+// Chapter headers and empty lines are never returned.
+func Search(cfg *config.Config, opts SearchOptions) ([]SearchResult, error) {
+	if opts.Query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	match := func(line string) bool {
+		return strings.Contains(strings.ToLower(line), strings.ToLower(opts.Query))
+	}
+	if opts.Exact {
+		match = func(line string) bool { return strings.Contains(line, opts.Query) }
+	}
+	if opts.Regex {
+		pattern, err := regexp.Compile(opts.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+		match = pattern.MatchString
+	}
+
+	files, err := journal.ListJournalFiles(cfg, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files: %w", err)
+	}
+
+	var results []SearchResult
+	for _, filePath := range files {
+		tags, err := journal.ExtractTags(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if !hasAllTags(tags, opts.Tags) {
+			continue
+		}
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fileutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal file %s: %w", filePath, err)
+		}
+
+		// The first line is the title, the summary goes up to the first chapter
+		lines := strings.Split(journal.NormaliseCRLF(string(content)), "\n")
+		for i := 1; i < len(lines); i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			if journal.IsSectionHeader(trimmed) {
+				if opts.SummaryOnly {
+					break
+				}
+				continue
+			}
+			if trimmed == "" || !match(trimmed) {
+				continue
+			}
+			results = append(results, SearchResult{FilePath: filePath, Date: date, Line: trimmed, LineNumber: i + 1, Tags: tags})
+		}
+	}
+	return results, nil
+}
+
+// hasAllTags reports whether fileTags, as returned by journal.ExtractTags, contains all the wanted tags.
+func hasAllTags(fileTags []string, wanted []string) bool {
+	for _, tag := range wanted {
+		tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+		found := false
+		for _, fileTag := range fileTags {
+			if fileTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	sep15 := filepath.Join(tmpDir, "2025-09-15.md")
+	sep16 := filepath.Join(tmpDir, "2025-09-16.md")
+	sep20 := filepath.Join(tmpDir, "2025-09-20.md")
+	os.WriteFile(sep15, []byte("# Sep 15 2025\n\nDeployed the API.\n\n# LOG\n09:00 Deployed the API #work\n11:00 Lunch with Anna #personal\n"), 0644)
+	os.WriteFile(sep16, []byte("# Sep 16 2025\n\nQuiet day.\n\n# LOG\n10:00 Fixed the api timeout #work #bug\n"), 0644)
+	os.WriteFile(sep20, []byte("# Sep 20 2025\n\n# LOG\n10:00 Hiking, no api today #personal\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "review_week_2025_38.md"), []byte("# Weekly Review\n\nAPI work\n"), 0644)
+
+	sep15Date := time.Date(2025, time.September, 15, 0, 0, 0, 0, time.UTC)
+	sep16Date := time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC)
+	sep20Date := time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		expected []SearchResult
+		err      string
+	}{
+		{
+			name: "case-insensitive text in all daily files",
+			opts: SearchOptions{Query: "API"},
+			expected: []SearchResult{
+				{FilePath: sep15, Date: sep15Date, Line: "Deployed the API.", LineNumber: 3, Tags: []string{"personal", "work"}},
+				{FilePath: sep15, Date: sep15Date, Line: "09:00 Deployed the API #work", LineNumber: 6, Tags: []string{"personal", "work"}},
+				{FilePath: sep16, Date: sep16Date, Line: "10:00 Fixed the api timeout #work #bug", LineNumber: 6, Tags: []string{"bug", "work"}},
+				{FilePath: sep20, Date: sep20Date, Line: "10:00 Hiking, no api today #personal", LineNumber: 4, Tags: []string{"personal"}},
+			},
+		},
+		{
+			name: "case-sensitive text",
+			opts: SearchOptions{Query: "api", Exact: true},
+			expected: []SearchResult{
+				{FilePath: sep16, Date: sep16Date, Line: "10:00 Fixed the api timeout #work #bug", LineNumber: 6, Tags: []string{"bug", "work"}},
+				{FilePath: sep20, Date: sep20Date, Line: "10:00 Hiking, no api today #personal", LineNumber: 4, Tags: []string{"personal"}},
+			},
+		},
+		{
+			name: "regular expression",
+			opts: SearchOptions{Query: `^\d{2}:\d{2} (Fixed|Deployed)`, Regex: true},
+			expected: []SearchResult{
+				{FilePath: sep15, Date: sep15Date, Line: "09:00 Deployed the API #work", LineNumber: 6, Tags: []string{"personal", "work"}},
+				{FilePath: sep16, Date: sep16Date, Line: "10:00 Fixed the api timeout #work #bug", LineNumber: 6, Tags: []string{"bug", "work"}},
+			},
+		},
+		{
+			name: "date range",
+			opts: SearchOptions{Query: "api", From: sep16Date, To: sep16Date},
+			expected: []SearchResult{
+				{FilePath: sep16, Date: sep16Date, Line: "10:00 Fixed the api timeout #work #bug", LineNumber: 6, Tags: []string{"bug", "work"}},
+			},
+		},
+		{
+			name: "files with all the tags",
+			opts: SearchOptions{Query: "api", Tags: []string{"#work", "Personal"}},
+			expected: []SearchResult{
+				{FilePath: sep15, Date: sep15Date, Line: "Deployed the API.", LineNumber: 3, Tags: []string{"personal", "work"}},
+				{FilePath: sep15, Date: sep15Date, Line: "09:00 Deployed the API #work", LineNumber: 6, Tags: []string{"personal", "work"}},
+			},
+		},
+		{
+			name: "summary only",
+			opts: SearchOptions{Query: "api", SummaryOnly: true},
+			expected: []SearchResult{
+				{FilePath: sep15, Date: sep15Date, Line: "Deployed the API.", LineNumber: 3, Tags: []string{"personal", "work"}},
+			},
+		},
+		{
+			name: "headers are not searched",
+			opts: SearchOptions{Query: "LOG"},
+		},
+		{
+			name: "no results",
+			opts: SearchOptions{Query: "holiday"},
+		},
+		{
+			name: "no file has all the tags",
+			opts: SearchOptions{Query: "api", Tags: []string{"bug", "personal"}},
+		},
+		{
+			name: "invalid regular expression",
+			opts: SearchOptions{Query: "(api", Regex: true},
+			err:  "invalid search pattern: error parsing regexp: missing closing ): `(api`",
+		},
+		{
+			name: "empty query",
+			opts: SearchOptions{},
+			err:  "search query cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Search(cfg, tt.opts)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, results)
+		})
+	}
+}
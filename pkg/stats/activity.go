@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// Stats sums up the journaling activity of a year. Journal days are the days whose daily file has log entries.
+type Stats struct {
+	Year              int
+	JournalDays       int
+	TotalEntries      int
+	AverageEntries    float64 // Entries per journal day
+	LongestStreak     int     // Consecutive journal days, including those of the adjacent years
+	CurrentStreak     int     // Consecutive journal days up to today, or yesterday if there is no entry yet today
+	MostActiveWeekday time.Weekday
+	WordCount         int // Words of the log entries, without their timestamps
+}
+
+// ComputeStats returns the Stats of the given year.
+func ComputeStats(cfg *config.Config, year int) (*Stats, error) {
+	return computeStats(cfg, year, time.Now())
+}
+
+// computeStats is ComputeStats with the current time as a parameter.
+func computeStats(cfg *config.Config, year int, now time.Time) (*Stats, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	files, err := journal.ListJournalFiles(cfg, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{Year: year}
+	journalDays := make(map[string]bool)
+	var weekdayEntries [7]int
+	for _, filePath := range files {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		// Streaks are computed on all the journal days, the other stats on the year only
+		journalDays[date.Format("2006-01-02")] = true
+		if date.Year() != year {
+			continue
+		}
+		stats.JournalDays++
+		stats.TotalEntries += len(entries)
+		weekdayEntries[date.Weekday()] += len(entries)
+		for _, entry := range entries {
+			stats.WordCount += len(strings.Fields(entry.Text))
+		}
+	}
+
+	today := journal.EffectiveDate(now, cfg.DayBoundaryHour)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if !journalDays[today.Format("2006-01-02")] {
+		today = today.AddDate(0, 0, -1)
+	}
+	for d := today; journalDays[d.Format("2006-01-02")]; d = d.AddDate(0, 0, -1) {
+		stats.CurrentStreak++
+	}
+
+	if stats.JournalDays == 0 {
+		return stats, nil
+	}
+
+	stats.AverageEntries = float64(stats.TotalEntries) / float64(stats.JournalDays)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if weekdayEntries[weekday] > weekdayEntries[stats.MostActiveWeekday] {
+			stats.MostActiveWeekday = weekday
+		}
+	}
+
+	// A streak counts for the year if one of its days is in the year
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if !journalDays[d.Format("2006-01-02")] {
+			continue
+		}
+		if !d.Equal(first) && journalDays[d.AddDate(0, 0, -1).Format("2006-01-02")] {
+			continue // Already counted from the first day of its streak
+		}
+		start := d
+		for journalDays[start.AddDate(0, 0, -1).Format("2006-01-02")] {
+			start = start.AddDate(0, 0, -1)
+		}
+		if length := streakLength(journalDays, start); length > stats.LongestStreak {
+			stats.LongestStreak = length
+		}
+	}
+
+	return stats, nil
+}
+
+// streakLength returns the number of consecutive journal days from start.
+func streakLength(journalDays map[string]bool, start time.Time) int {
+	length := 0
+	for d := start; journalDays[d.Format("2006-01-02")]; d = d.AddDate(0, 0, 1) {
+		length++
+	}
+	return length
+}
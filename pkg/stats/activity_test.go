@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// A streak from Dec 29, 2024 to Jan 3, 2025, then a 3 days streak in March 2025 and one ending on Jan 2, 2026
+	for d := 29; d <= 31; d++ {
+		writeJournalFile(t, tmpDir, day(2024, time.December, d), "09:00 Old year")
+	}
+	writeJournalFile(t, tmpDir, day(2025, time.January, 1), "09:00 New year party", "23:00 Sleep")
+	writeJournalFile(t, tmpDir, day(2025, time.January, 2), "09:00 Back to work")
+	writeJournalFile(t, tmpDir, day(2025, time.January, 3), "09:00 Friday")
+	writeJournalFile(t, tmpDir, day(2025, time.March, 3), "09:00 Monday one", "10:00 Monday two", "11:00 Monday three", "12:00 Monday four", "13:00 Monday five")
+	writeJournalFile(t, tmpDir, day(2025, time.March, 4), "09:00 Tuesday")
+	writeJournalFile(t, tmpDir, day(2025, time.March, 5), "09:00 Wednesday")
+	writeJournalFile(t, tmpDir, day(2025, time.December, 31), "09:00 Last day")
+	writeJournalFile(t, tmpDir, day(2026, time.January, 1), "09:00 First day")
+	writeJournalFile(t, tmpDir, day(2026, time.January, 2), "09:00 Second day")
+	// A daily file without entries is not a journal day
+	os.WriteFile(filepath.Join(tmpDir, "2025-01-04.md"), []byte("# Jan 04 2025\n\n# LOG\n\n"), 0644)
+
+	// Test case 1: The stats of 2025, with the streak started in 2024
+	stats, err := computeStats(cfg, 2025, time.Date(2025, time.March, 5, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{
+		Year:              2025,
+		JournalDays:       7,
+		TotalEntries:      12,
+		AverageEntries:    12.0 / 7,
+		LongestStreak:     6,
+		CurrentStreak:     3,
+		MostActiveWeekday: time.Monday,
+		WordCount:         22,
+	}, stats)
+
+	// Test case 2: Without an entry today, the current streak ends yesterday
+	stats, err = computeStats(cfg, 2025, time.Date(2025, time.March, 6, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.CurrentStreak)
+	stats, err = computeStats(cfg, 2025, time.Date(2025, time.March, 7, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.CurrentStreak)
+
+	// Test case 3: The current streak goes across the new year
+	stats, err = computeStats(cfg, 2026, time.Date(2026, time.January, 2, 10, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.CurrentStreak)
+	assert.Equal(t, 3, stats.LongestStreak)
+	assert.Equal(t, 2, stats.JournalDays)
+
+	// Test case 4: The day boundary moves today to yesterday
+	cfg.DayBoundaryHour = 4
+	stats, err = computeStats(cfg, 2026, time.Date(2026, time.January, 3, 2, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.CurrentStreak)
+	cfg.DayBoundaryHour = 0
+
+	// Test case 5: A year without entries
+	stats, err = computeStats(cfg, 2023, time.Date(2025, time.March, 5, 20, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Year: 2023, CurrentStreak: 3}, stats)
+}
@@ -0,0 +1,240 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// ProjectStat holds the activity recorded for a single project label.
+type ProjectStat struct {
+	EntryCount int
+	WordCount  int
+	ActiveDays int
+	FirstEntry time.Time
+	LastEntry  time.Time
+}
+
+// projectLabelPattern matches the "[project:name]" label attached to log entries.
+var projectLabelPattern = regexp.MustCompile(`\[project:([^\]\s]+)\]`)
+
+// ProjectStats breaks down the log entries between startDate and endDate (inclusive) by project label.
+// Entries without a "[project:name]" label are ignored, and projects without entries are absent from the result.
+func ProjectStats(cfg *config.Config, startDate, endDate time.Time) (map[string]ProjectStat, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	projects := make(map[string]ProjectStat)
+
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		filePath, err := journal.DailyFilePath(cfg, d)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to check file %s: %w", filePath, err)
+		}
+
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		activeToday := make(map[string]bool)
+		for _, entry := range entries {
+			match := projectLabelPattern.FindStringSubmatch(entry.Text)
+			if match == nil {
+				continue
+			}
+			name := match[1]
+			timestamp := entry.On(d)
+			text := projectLabelPattern.ReplaceAllString(entry.Text, "")
+
+			stat := projects[name]
+			stat.EntryCount++
+			stat.WordCount += len(strings.Fields(text))
+			if !activeToday[name] {
+				activeToday[name] = true
+				stat.ActiveDays++
+			}
+			if stat.FirstEntry.IsZero() || timestamp.Before(stat.FirstEntry) {
+				stat.FirstEntry = timestamp
+			}
+			if timestamp.After(stat.LastEntry) {
+				stat.LastEntry = timestamp
+			}
+			projects[name] = stat
+		}
+	}
+
+	return projects, nil
+}
+
+// SortProjectsByEntryCount returns the project names sorted by entry count (descending), then by name.
+func SortProjectsByEntryCount(projects map[string]ProjectStat) []string {
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if projects[names[i]].EntryCount != projects[names[j]].EntryCount {
+			return projects[names[i]].EntryCount > projects[names[j]].EntryCount
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// AverageEntryInterval returns, for each day between startDate and endDate (inclusive) with log entries,
+// the mean interval between its consecutive timestamped entries, keyed by date in YYYY-MM-DD format.
+// Days with fewer than two timestamped entries map to 0, days without entries are absent.
+func AverageEntryInterval(cfg *config.Config, startDate, endDate time.Time) (map[string]time.Duration, error) {
+	intervals, err := entryIntervals(cfg, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	averages := make(map[string]time.Duration, len(intervals))
+	for date, dayIntervals := range intervals {
+		averages[date] = meanDuration(dayIntervals)
+	}
+	return averages, nil
+}
+
+// OverallAverageInterval returns the mean interval between consecutive timestamped entries of the same day,
+// over all the days between startDate and endDate (inclusive). Returns 0 if no day has two entries.
+func OverallAverageInterval(cfg *config.Config, startDate, endDate time.Time) (time.Duration, error) {
+	intervals, err := entryIntervals(cfg, startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+
+	var all []time.Duration
+	for _, dayIntervals := range intervals {
+		all = append(all, dayIntervals...)
+	}
+	return meanDuration(all), nil
+}
+
+// entryIntervals returns the intervals between the consecutive timestamped entries of each day with log entries.
+func entryIntervals(cfg *config.Config, startDate, endDate time.Time) (map[string][]time.Duration, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	intervals := make(map[string][]time.Duration)
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		filePath, err := journal.DailyFilePath(cfg, d)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to check file %s: %w", filePath, err)
+		}
+
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		var timestamps []time.Time
+		for _, entry := range entries {
+			if !entry.Time.IsZero() {
+				timestamps = append(timestamps, entry.On(d))
+			}
+		}
+		// Files written with LogEntryOrder "prepend" list the newest entries first
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+		dayIntervals := []time.Duration{}
+		for i := 1; i < len(timestamps); i++ {
+			dayIntervals = append(dayIntervals, timestamps[i].Sub(timestamps[i-1]))
+		}
+		intervals[d.Format("2006-01-02")] = dayIntervals
+	}
+	return intervals, nil
+}
+
+// meanDuration returns the mean of the given durations, or 0 if there are none.
+func meanDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// HabitStat holds how often a habit was done in a period.
+type HabitStat struct {
+	Habit         string
+	DaysCompleted int     // Days with at least one log entry mentioning the habit
+	TotalDays     int     // Days of the period
+	Percentage    float64 // DaysCompleted out of TotalDays, from 0 to 100
+}
+
+// HabitCompletion returns, for each habit of cfg.Habits, the days between startDate and endDate (inclusive)
+// with a log entry mentioning it, see journal.CheckHabitEntry.
+func HabitCompletion(cfg *config.Config, startDate, endDate time.Time) (map[string]HabitStat, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	habits := make(map[string]HabitStat, len(cfg.Habits))
+	for _, habit := range cfg.Habits {
+		habits[habit] = HabitStat{Habit: habit}
+	}
+
+	totalDays := 0
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		totalDays++
+		filePath, err := journal.DailyFilePath(cfg, d)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to check file %s: %w", filePath, err)
+		}
+
+		entries, err := journal.ExtractLogEntries(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		for habit, stat := range habits {
+			for _, entry := range entries {
+				if journal.CheckHabitEntry(entry.Text, habit) {
+					stat.DaysCompleted++
+					habits[habit] = stat
+					break
+				}
+			}
+		}
+	}
+
+	for habit, stat := range habits {
+		stat.TotalDays = totalDays
+		if totalDays > 0 {
+			stat.Percentage = float64(stat.DaysCompleted) / float64(totalDays) * 100
+		}
+		habits[habit] = stat
+	}
+	return habits, nil
+}
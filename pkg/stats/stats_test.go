@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeJournalFile creates a daily journal file with the given LOG lines.
+func writeJournalFile(t *testing.T, dir string, date time.Time, logLines ...string) {
+	t.Helper()
+	content := "# " + date.Format("Jan 02 2006 Monday") + "\n\n# LOG\n\n" + strings.Join(logLines, "\n") + "\n"
+	err := os.WriteFile(filepath.Join(dir, date.Format("2006-01-02")+".md"), []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func TestProjectStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 0, 0, 0, 0, time.UTC) }
+
+	// Two weeks of entries for two projects, with some unlabelled entries
+	writeJournalFile(t, tmpDir, day(1),
+		"09:00 [project:logbook] Started the stats package",
+		"10:30 [project:logbook] Wrote tests",
+		"12:00 Lunch with the team")
+	writeJournalFile(t, tmpDir, day(3),
+		"08:15 [project:website] Updated the landing page")
+	writeJournalFile(t, tmpDir, day(8),
+		"11:00 [project:logbook] Fixed review generation",
+		"16:45 [project:website] Deployed")
+	writeJournalFile(t, tmpDir, day(14),
+		"17:20 [project:logbook] Released version one")
+
+	projects, err := ProjectStats(cfg, day(1), day(14))
+	assert.NoError(t, err)
+	assert.Len(t, projects, 2)
+
+	// Test case 1: ActiveDays only counts days with at least one entry for the project
+	logbook := projects["logbook"]
+	assert.Equal(t, 4, logbook.EntryCount)
+	assert.Equal(t, 3, logbook.ActiveDays)
+	assert.Equal(t, 12, logbook.WordCount)
+	assert.Equal(t, time.Date(2025, time.September, 1, 9, 0, 0, 0, time.UTC), logbook.FirstEntry)
+	assert.Equal(t, time.Date(2025, time.September, 14, 17, 20, 0, 0, time.UTC), logbook.LastEntry)
+
+	// Test case 2: FirstEntry and LastEntry of the second project
+	website := projects["website"]
+	assert.Equal(t, 2, website.EntryCount)
+	assert.Equal(t, 2, website.ActiveDays)
+	assert.Equal(t, time.Date(2025, time.September, 3, 8, 15, 0, 0, time.UTC), website.FirstEntry)
+	assert.Equal(t, time.Date(2025, time.September, 8, 16, 45, 0, 0, time.UTC), website.LastEntry)
+
+	// Test case 3: A project without entries in the range is absent
+	projects, err = ProjectStats(cfg, day(2), day(7))
+	assert.NoError(t, err)
+	assert.Contains(t, projects, "website")
+	assert.NotContains(t, projects, "logbook")
+
+	// Test case 4: Projects are sorted by entry count
+	projects, err = ProjectStats(cfg, day(1), day(14))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"logbook", "website"}, SortProjectsByEntryCount(projects))
+
+	// Test case 5: Invalid configuration
+	invalidCfg := config.DefaultConfig()
+	invalidCfg.JournalDir = ""
+	_, err = ProjectStats(invalidCfg, day(1), day(14))
+	assert.ErrorContains(t, err, "invalid configuration: JournalDir cannot be empty")
+}
+
+func TestAverageEntryInterval(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 0, 0, 0, 0, time.UTC) }
+
+	writeJournalFile(t, tmpDir, day(1), "09:00 Standup", "11:00 Code review", "14:00 Pairing")
+	writeJournalFile(t, tmpDir, day(2), "10:00 Only entry")
+	writeJournalFile(t, tmpDir, day(3)) // No entries
+	writeJournalFile(t, tmpDir, day(4), "16:00 Newest first", "12:00 Oldest")
+
+	// Test case 1: Mean interval per day
+	averages, err := AverageEntryInterval(cfg, day(1), day(7))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"2025-09-01": 2*time.Hour + 30*time.Minute,
+		"2025-09-02": 0,
+		"2025-09-04": 4 * time.Hour,
+	}, averages)
+
+	// Test case 2: Overall mean of the intervals, 2h, 3h and 4h
+	overall, err := OverallAverageInterval(cfg, day(1), day(7))
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Hour, overall)
+
+	// Test case 3: No day with two entries
+	overall, err = OverallAverageInterval(cfg, day(2), day(3))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), overall)
+
+	// Test case 4: Invalid configuration
+	invalidCfg := config.DefaultConfig()
+	invalidCfg.JournalDir = ""
+	_, err = AverageEntryInterval(invalidCfg, day(1), day(7))
+	assert.ErrorContains(t, err, "invalid configuration: JournalDir cannot be empty")
+}
+
+func TestHabitCompletion(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	cfg.Habits = []string{"exercise", "meditation"}
+
+	// September 2025 has 30 days: exercise on the odd days, twice on the first day
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 0, 0, 0, 0, time.UTC) }
+	for d := 1; d <= 30; d++ {
+		if d%2 == 1 {
+			writeJournalFile(t, tmpDir, day(d), "07:00 Morning Exercise", "18:00 Worked late")
+		} else {
+			writeJournalFile(t, tmpDir, day(d), "09:00 Worked on the release")
+		}
+	}
+	writeJournalFile(t, tmpDir, day(1), "07:00 exercise", "19:00 More exercise")
+
+	// Test case 1: Half of the days
+	habits, err := HabitCompletion(cfg, day(1), day(30))
+	assert.NoError(t, err)
+	assert.Equal(t, HabitStat{Habit: "exercise", DaysCompleted: 15, TotalDays: 30, Percentage: 50}, habits["exercise"])
+
+	// Test case 2: A habit never mentioned
+	assert.Equal(t, HabitStat{Habit: "meditation", DaysCompleted: 0, TotalDays: 30, Percentage: 0}, habits["meditation"])
+
+	// Test case 3: No habits configured
+	cfg.Habits = nil
+	habits, err = HabitCompletion(cfg, day(1), day(30))
+	assert.NoError(t, err)
+	assert.Empty(t, habits)
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/clobrano/LogBook/pkg/journal"
+)
+
+// Periods of PeriodRange.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// DayWords is the number of words of the LOG chapter of a daily journal file, see journal.CountLogWords.
+type DayWords struct {
+	Date  time.Time
+	Words int
+}
+
+// WordStat sums up the words written in a period. Days without a daily journal file are ignored.
+type WordStat struct {
+	Days         []DayWords // In chronological order
+	Total        int
+	DailyAverage float64  // Words per day with a daily journal file
+	Peak         DayWords // The first day with the most words, zero if there are no days
+}
+
+// WordStats returns the words written in the daily journal files between startDate and endDate (inclusive).
+func WordStats(cfg *config.Config, startDate, endDate time.Time) (*WordStat, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	files, err := journal.ListJournalFilesByPeriod(cfg, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal files: %w", err)
+	}
+
+	stat := &WordStat{}
+	for _, filePath := range files {
+		date, err := journal.DateFromFilePath(cfg, filePath)
+		if err != nil {
+			return nil, err
+		}
+		words, err := journal.CountLogWords(filePath)
+		if err != nil {
+			return nil, err
+		}
+		day := DayWords{Date: date, Words: words}
+		stat.Days = append(stat.Days, day)
+		stat.Total += words
+		if len(stat.Days) == 1 || words > stat.Peak.Words {
+			stat.Peak = day
+		}
+	}
+	if len(stat.Days) > 0 {
+		stat.DailyAverage = float64(stat.Total) / float64(len(stat.Days))
+	}
+	return stat, nil
+}
+
+// TopDays returns the n days with the most words, by words (descending) then date.
+func (s *WordStat) TopDays(n int) []DayWords {
+	days := make([]DayWords, len(s.Days))
+	copy(days, s.Days)
+	sort.SliceStable(days, func(i, j int) bool {
+		return days[i].Words > days[j].Words
+	})
+	if n < len(days) {
+		days = days[:n]
+	}
+	return days
+}
+
+// PeriodRange returns the first and last day of the day, ISO week, month or year including now.
+func PeriodRange(period string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case PeriodDay:
+		return today, today, nil
+	case PeriodWeek:
+		// Weeks start on Monday
+		start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return start, start.AddDate(0, 0, 6), nil
+	case PeriodMonth:
+		start := today.AddDate(0, 0, 1-today.Day())
+		return start, start.AddDate(0, 1, -1), nil
+	case PeriodYear:
+		start := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+		return start, start.AddDate(1, 0, -1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown period: %s, expected %s, %s, %s or %s", period, PeriodDay, PeriodWeek, PeriodMonth, PeriodYear)
+}
@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clobrano/LogBook/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JournalDir = tmpDir
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 0, 0, 0, 0, time.UTC) }
+
+	writeJournalFile(t, tmpDir, day(15), "09:00 Fixed the parser")                       // 4 words
+	writeJournalFile(t, tmpDir, day(16), "09:00 Long day", "10:00 Reviewed two big PRs") // 8 words
+	writeJournalFile(t, tmpDir, day(18), "09:00 Short", "10:00 Also short")              // 5 words
+	// An empty LOG and a LOG with only whitespace have no words
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-19.md"), []byte("# Sep 19 2025\n\nA summary.\n\n# LOG\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "2025-09-20.md"), []byte("# Sep 20 2025\n\n# LOG\n \t\n\n"), 0644)
+	writeJournalFile(t, tmpDir, day(30), "09:00 Out of the period")
+
+	// Test case 1: Total, daily average and peak of the period, days without file are ignored
+	stat, err := WordStats(cfg, day(15), day(21))
+	assert.NoError(t, err)
+	assert.Equal(t, []DayWords{{day(15), 4}, {day(16), 8}, {day(18), 5}, {day(19), 0}, {day(20), 0}}, stat.Days)
+	assert.Equal(t, 17, stat.Total)
+	assert.InDelta(t, 3.4, stat.DailyAverage, 0.001)
+	assert.Equal(t, DayWords{day(16), 8}, stat.Peak)
+
+	// Test case 2: The top days, by words then date
+	assert.Equal(t, []DayWords{{day(16), 8}, {day(18), 5}}, stat.TopDays(2))
+	assert.Equal(t, []DayWords{{day(16), 8}, {day(18), 5}, {day(15), 4}, {day(19), 0}, {day(20), 0}}, stat.TopDays(10))
+	assert.Empty(t, stat.TopDays(0))
+
+	// Test case 3: A period without files
+	stat, err = WordStats(cfg, day(1), day(7))
+	assert.NoError(t, err)
+	assert.Empty(t, stat.Days)
+	assert.Equal(t, 0, stat.Total)
+	assert.Equal(t, 0.0, stat.DailyAverage)
+	assert.Equal(t, DayWords{}, stat.Peak)
+}
+
+func TestPeriodRange(t *testing.T) {
+	now := time.Date(2025, time.September, 18, 15, 30, 0, 0, time.UTC) // A Thursday
+	date := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+
+	// Test case 1: The supported periods
+	for period, expected := range map[string][2]time.Time{
+		PeriodDay:   {date(time.September, 18), date(time.September, 18)},
+		PeriodWeek:  {date(time.September, 15), date(time.September, 21)},
+		PeriodMonth: {date(time.September, 1), date(time.September, 30)},
+		PeriodYear:  {date(time.January, 1), date(time.December, 31)},
+	} {
+		start, end, err := PeriodRange(period, now)
+		assert.NoError(t, err, period)
+		assert.Equal(t, expected[0], start, period)
+		assert.Equal(t, expected[1], end, period)
+	}
+
+	// Test case 2: A week starting on Monday, seen from Sunday
+	start, end, err := PeriodRange(PeriodWeek, time.Date(2025, time.September, 21, 23, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, date(time.September, 15), start)
+	assert.Equal(t, date(time.September, 21), end)
+
+	// Test case 3: Unknown period
+	_, _, err = PeriodRange("decade", now)
+	assert.EqualError(t, err, "unknown period: decade, expected day, week, month or year")
+}
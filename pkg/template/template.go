@@ -3,36 +3,72 @@ package template
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"text/template"
+	"text/template/parse"
 	"time"
 )
 
 // TemplateData holds the data available for templating.
 type TemplateData struct {
 	Date    time.Time
-	Time    time.Time
+	Time    time.Time // Time of a log entry, see Config.LogEntryTemplate
 	Summary string
-	Entry   string
+	Entry   string // Text of a log entry, see Config.LogEntryTemplate
+	// DatePrefix is the layout used to render the date before a log entry, see Config.EntryDatePrefix.
+	DatePrefix string
 	// Add other fields as needed for templating
 }
 
+// funcs holds the custom functions available to templates.
+var funcs = template.FuncMap{
+	"formatDate": func(format string, date time.Time) string {
+		return date.Format(format)
+	},
+	"formatTime": func(format string, t time.Time) string {
+		return t.Format(format)
+	},
+}
+
+// safeBuiltins are the text/template builtin functions templates may use.
+// Notably "call" is missing, so templates cannot invoke arbitrary functions.
+var safeBuiltins = map[string]bool{
+	"and": true, "or": true, "not": true, "len": true, "index": true, "slice": true,
+	"print": true, "printf": true, "println": true,
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+}
+
+// FieldNames returns the names of the TemplateData fields, which templates can reference as {{.Name}}.
+func FieldNames() []string {
+	dataType := reflect.TypeOf(TemplateData{})
+	names := make([]string, 0, dataType.NumField())
+	for i := 0; i < dataType.NumField(); i++ {
+		if dataType.Field(i).IsExported() {
+			names = append(names, dataType.Field(i).Name)
+		}
+	}
+	return names
+}
+
 // Render renders a given template string with the provided data.
+// Only the custom functions and the safe builtins can be used.
 func Render(templateString string, data TemplateData) (string, error) {
+	return RenderData(templateString, data)
+}
+
+// RenderData is like Render, for templates using other data than TemplateData, e.g. {{.ID}} in issue links.
+func RenderData(templateString string, data interface{}) (string, error) {
 	// Create a new template and add custom functions
-	tmpl := template.New("logbook_template").Funcs(template.FuncMap{
-		"formatDate": func(format string, date time.Time) string {
-			return date.Format(format)
-		},
-		"formatTime": func(format string, t time.Time) string {
-			return t.Format(format)
-		},
-	})
+	tmpl := template.New("logbook_template").Funcs(funcs)
 
 	// Parse the template string
 	parsedTmpl, err := tmpl.Parse(templateString)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
+	if err := walk(parsedTmpl.Tree.Root, nil); err != nil {
+		return "", err
+	}
 
 	// Execute the template with the provided data
 	var buf bytes.Buffer
@@ -42,3 +78,87 @@ func Render(templateString string, data TemplateData) (string, error) {
 
 	return buf.String(), nil
 }
+
+// ValidateNoCycle parses a template and checks that it only references the variables in allowedVarNames
+// and only uses safe functions. This catches templates referencing themselves, like {{.DailyFileName}}, before rendering.
+func ValidateNoCycle(templateStr string, allowedVarNames []string) error {
+	tmpl, err := template.New("logbook_template").Funcs(funcs).Parse(templateStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	allowed := make(map[string]bool, len(allowedVarNames))
+	for _, name := range allowedVarNames {
+		allowed[name] = true
+	}
+	return walk(tmpl.Tree.Root, allowed)
+}
+
+// walk checks the functions used by the nodes of a template tree and, if allowed is not nil, the variables they reference.
+func walk(node parse.Node, allowed map[string]bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := walk(child, allowed); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return walk(n.Pipe, allowed)
+	case *parse.IfNode:
+		return walkBranch(&n.BranchNode, allowed)
+	case *parse.RangeNode:
+		return walkBranch(&n.BranchNode, allowed)
+	case *parse.WithNode:
+		return walkBranch(&n.BranchNode, allowed)
+	case *parse.TemplateNode:
+		return walk(n.Pipe, allowed)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := walk(cmd, allowed); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := walk(arg, allowed); err != nil {
+				return err
+			}
+		}
+	case *parse.ChainNode:
+		return walk(n.Node, allowed)
+	case *parse.IdentifierNode:
+		if _, ok := funcs[n.Ident]; !ok && !safeBuiltins[n.Ident] {
+			return fmt.Errorf("function %q is not allowed in templates", n.Ident)
+		}
+	case *parse.FieldNode:
+		if allowed != nil && !allowed[n.Ident[0]] {
+			return fmt.Errorf("unknown template variable %q", "."+n.Ident[0])
+		}
+	case *parse.VariableNode:
+		// $.Name refers to the root data
+		if allowed != nil && n.Ident[0] == "$" && len(n.Ident) > 1 && !allowed[n.Ident[1]] {
+			return fmt.Errorf("unknown template variable %q", "."+n.Ident[1])
+		}
+	}
+	return nil
+}
+
+// walkBranch checks the pipeline and both lists of an if, range or with node.
+func walkBranch(n *parse.BranchNode, allowed map[string]bool) error {
+	if err := walk(n.Pipe, allowed); err != nil {
+		return err
+	}
+	if err := walk(n.List, allowed); err != nil {
+		return err
+	}
+	return walk(n.ElseList, allowed)
+}
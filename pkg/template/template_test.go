@@ -38,3 +38,57 @@ func TestRender(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "function \"invalidFunc\" not defined")
 }
+
+func TestValidateNoCycle(t *testing.T) {
+	allowed := FieldNames()
+	assert.Equal(t, []string{"Date", "Time", "Summary", "Entry", "DatePrefix"}, allowed)
+
+	// Test case 1: Valid templates
+	assert.NoError(t, ValidateNoCycle("{{.Date | formatDate \"2006-01-02\"}}: {{.Summary}}", allowed))
+	assert.NoError(t, ValidateNoCycle("{{if .Entry}}{{printf \"%s!\" .Entry}}{{else}}{{$.Summary}}{{end}}", allowed))
+	assert.NoError(t, ValidateNoCycle("Plain text", allowed))
+
+	// Test case 2: Unknown and self-referencing variables
+	err := ValidateNoCycle("{{.Unknown}}", allowed)
+	assert.ErrorContains(t, err, "unknown template variable \".Unknown\"")
+	err = ValidateNoCycle("{{.DailyFileName}}.md", allowed)
+	assert.ErrorContains(t, err, "unknown template variable \".DailyFileName\"")
+	err = ValidateNoCycle("{{with .Date}}{{$.Missing}}{{end}}", allowed)
+	assert.ErrorContains(t, err, "unknown template variable \".Missing\"")
+
+	// Test case 3: Unsafe functions are rejected
+	err = ValidateNoCycle("{{call .SomeFunc}}", []string{"SomeFunc"})
+	assert.ErrorContains(t, err, "function \"call\" is not allowed in templates")
+
+	// Test case 4: Invalid template
+	err = ValidateNoCycle("{{.Date", allowed)
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	// Test case 5: Render refuses unsafe functions too
+	_, err = Render("{{call .Entry}}", TemplateData{Entry: "text"})
+	assert.ErrorContains(t, err, "function \"call\" is not allowed in templates")
+}
+
+func TestRenderLogEntryTemplate(t *testing.T) {
+	timestamp := time.Date(2025, time.September, 18, 9, 5, 42, 0, time.UTC)
+
+	// Test case 1: The default log entry template
+	result, err := Render("{{.Time | formatTime \"15:04\"}} {{.Entry}}", TemplateData{Date: timestamp, Time: timestamp, Entry: "Deployed the API"})
+	assert.NoError(t, err)
+	assert.Equal(t, "09:05 Deployed the API", result)
+
+	// Test case 2: Time and Date are independent
+	result, err = Render("{{.Date | formatDate \"Mon\"}} {{.Time | formatTime \"15:04:05\"}} {{.Entry}}", TemplateData{Date: timestamp.AddDate(0, 0, 1), Time: timestamp, Entry: "Late"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Fri 09:05:42 Late", result)
+
+	// Test case 3: The summary
+	result, err = Render("> {{.Summary}}", TemplateData{Summary: "A good day."})
+	assert.NoError(t, err)
+	assert.Equal(t, "> A good day.", result)
+
+	// Test case 4: Missing fields render their zero value instead of failing
+	result, err = Render("{{.Time | formatTime \"15:04\"}} {{.Entry}}", TemplateData{})
+	assert.NoError(t, err)
+	assert.Equal(t, "00:00 ", result)
+}
@@ -0,0 +1,94 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// ErrUnknownTheme is returned by Apply for theme names it does not know.
+var ErrUnknownTheme = errors.New("unknown color theme")
+
+// Theme-aware colour functions, configured by Apply.
+// They have the same signature as color.GreenString and friends.
+var (
+	Success = color.New(color.FgGreen).SprintfFunc()   // Confirmation messages, e.g. a file was written
+	Warning = color.New(color.FgYellow).SprintfFunc()  // Skipped steps and non fatal problems
+	Muted   = color.New(color.FgHiBlack).SprintfFunc() // Secondary information, e.g. unchanged settings
+)
+
+// noColorEnvVars disable colours when set to any non-empty value, see https://no-color.org/.
+var noColorEnvVars = []string{"NO_COLOR", "LOGBOOK_NO_COLOR"}
+
+// disabled is set by Disable, so that Apply keeps the colours disabled whatever the theme.
+var disabled bool
+
+// stdoutIsTerminal reports whether stdout is a terminal, replaced in tests.
+var stdoutIsTerminal = func() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// noColorRequested reports whether colours are disabled by one of noColorEnvVars.
+func noColorRequested() bool {
+	for _, name := range noColorEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsColourSupported reports whether the output can be coloured: colours are not disabled by $NO_COLOR
+// or $LOGBOOK_NO_COLOR, $TERM is not "dumb" and stdout is a terminal.
+func IsColourSupported() bool {
+	if noColorRequested() || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// Apply configures the colour functions for the given theme: "default", "solarized", "dracula" or "none".
+// An empty name selects the default theme. The "none" theme disables colours globally via color.NoColor,
+// as does calling Disable, or an output that IsColourSupported rejects, whatever the theme.
+func Apply(theme string) error {
+	switch theme {
+	case "", "default":
+		Success = color.New(color.FgGreen).SprintfFunc()
+		Warning = color.New(color.FgYellow).SprintfFunc()
+		Muted = color.New(color.FgHiBlack).SprintfFunc()
+	case "solarized":
+		Success = color.New(color.FgCyan).SprintfFunc()
+		Warning = color.New(color.FgYellow).SprintfFunc()
+		Muted = color.New(color.FgHiBlack).SprintfFunc()
+	case "dracula":
+		Success = color.New(color.FgHiGreen).SprintfFunc()
+		Warning = color.New(color.FgHiYellow).SprintfFunc()
+		Muted = color.New(color.FgHiBlue).SprintfFunc()
+	case "none":
+		disableColours()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownTheme, theme)
+	}
+
+	if disabled || !IsColourSupported() {
+		disableColours()
+	}
+	return nil
+}
+
+// Disable disables colours globally, as the "none" theme, including for the themes applied later, e.g. for --no-color.
+func Disable() {
+	disabled = true
+	disableColours()
+}
+
+// disableColours disables colours globally and makes the colour functions plain fmt.Sprintf.
+func disableColours() {
+	color.NoColor = true
+	Success = fmt.Sprintf
+	Warning = fmt.Sprintf
+	Muted = fmt.Sprintf
+}
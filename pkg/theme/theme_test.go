@@ -0,0 +1,113 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	noColor, isTerminal := color.NoColor, stdoutIsTerminal
+	t.Cleanup(func() {
+		color.NoColor, stdoutIsTerminal = noColor, isTerminal
+		Apply("default")
+	})
+	stdoutIsTerminal = func() bool { return true }
+
+	// Test case 1: Known themes are accepted
+	for _, name := range []string{"", "default", "solarized", "dracula", "none"} {
+		assert.NoError(t, Apply(name), name)
+	}
+
+	// Test case 2: The "none" theme disables colours
+	color.NoColor = false
+	assert.NoError(t, Apply("none"))
+	assert.True(t, color.NoColor)
+	assert.Equal(t, "Log entry appended to file.md", Success("Log entry appended to %s", "file.md"))
+
+	// Test case 3: Unknown theme
+	err := Apply("monokai")
+	assert.ErrorIs(t, err, ErrUnknownTheme)
+	assert.ErrorContains(t, err, "monokai")
+}
+
+func TestNoColorEnvVars(t *testing.T) {
+	noColor, isTerminal := color.NoColor, stdoutIsTerminal
+	t.Cleanup(func() {
+		color.NoColor, stdoutIsTerminal = noColor, isTerminal
+		Apply("default")
+	})
+	stdoutIsTerminal = func() bool { return true }
+
+	for _, name := range []string{"NO_COLOR", "LOGBOOK_NO_COLOR"} {
+		t.Run(name, func(t *testing.T) {
+			// Test case 1: Any non-empty value disables colours, whatever the theme
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("LOGBOOK_NO_COLOR", "")
+			t.Setenv(name, "1")
+			assert.False(t, IsColourSupported())
+
+			color.NoColor = false
+			assert.NoError(t, Apply("dracula"))
+			assert.True(t, color.NoColor)
+			assert.Equal(t, "Review written", Success("Review %s", "written"))
+
+			// Test case 2: Unknown themes are still rejected
+			assert.ErrorIs(t, Apply("monokai"), ErrUnknownTheme)
+		})
+	}
+
+	// Test case 3: A dumb terminal does not support colours, the themes are applied without them
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("LOGBOOK_NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	assert.False(t, IsColourSupported())
+	color.NoColor = false
+	assert.NoError(t, Apply("solarized"))
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, Success("written"), "\x1b[")
+
+	// Test case 4: Empty variables do not disable colours
+	t.Setenv("TERM", "xterm-256color")
+	color.NoColor = false
+	assert.NoError(t, Apply("default"))
+	assert.False(t, color.NoColor)
+	assert.Contains(t, Success("written"), "\x1b[")
+
+	// Test case 5: An output that is not a terminal, e.g. a pipe, is not coloured
+	stdoutIsTerminal = func() bool { return false }
+	assert.False(t, IsColourSupported())
+	assert.NoError(t, Apply("dracula"))
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, Muted("unchanged"), "\x1b[")
+}
+
+func TestDisable(t *testing.T) {
+	noColor, isTerminal := color.NoColor, stdoutIsTerminal
+	t.Cleanup(func() {
+		disabled = false
+		color.NoColor, stdoutIsTerminal = noColor, isTerminal
+		Apply("default")
+	})
+	stdoutIsTerminal = func() bool { return true }
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("LOGBOOK_NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	// Test case 1: The colour functions write no ANSI escape sequences
+	color.NoColor = false
+	assert.NoError(t, Apply("default"))
+	assert.Contains(t, Success("colored"), "\x1b[")
+	Disable()
+	assert.True(t, color.NoColor)
+	for _, colorize := range []func(string, ...interface{}) string{Success, Warning, Muted} {
+		assert.Equal(t, "Entry added to log.", colorize("Entry added to %s.", "log"))
+	}
+
+	// Test case 2: The themes applied later keep the colours disabled
+	color.NoColor = false
+	assert.NoError(t, Apply("dracula"))
+	assert.True(t, color.NoColor)
+	assert.NotContains(t, Warning("skipped"), "\x1b[")
+}
@@ -0,0 +1,92 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BaseURL is the address of the wttr.in service, replaced in tests.
+var BaseURL = "https://wttr.in/"
+
+// Timeout is the maximum duration of a request, set from Config.WeatherTimeout.
+var Timeout = 3 * time.Second
+
+// WeatherInfo is the current weather of a location.
+type WeatherInfo struct {
+	Condition string // e.g. "Partly cloudy"
+	TempC     int
+	Icon      string // Emoji of the condition
+}
+
+// String returns the weather as prepended to the log entries, e.g. "🌤️ 22°C".
+func (w WeatherInfo) String() string {
+	return fmt.Sprintf("%s %d°C", w.Icon, w.TempC)
+}
+
+// conditionIcons maps the wttr.in weather codes to emojis.
+var conditionIcons = map[string]string{
+	"113": "☀️",
+	"116": "🌤️",
+	"119": "☁️", "122": "☁️",
+	"143": "🌫️", "248": "🌫️", "260": "🌫️",
+	"176": "🌦️", "263": "🌦️", "266": "🌦️", "293": "🌦️", "296": "🌦️", "353": "🌦️",
+	"299": "🌧️", "302": "🌧️", "305": "🌧️", "308": "🌧️", "356": "🌧️", "359": "🌧️",
+	"179": "🌨️", "182": "🌨️", "185": "🌨️", "227": "🌨️", "230": "❄️", "317": "🌨️", "320": "🌨️",
+	"323": "🌨️", "326": "🌨️", "329": "❄️", "332": "❄️", "335": "❄️", "338": "❄️", "368": "🌨️", "371": "❄️",
+	"200": "⛈️", "386": "⛈️", "389": "⛈️", "392": "⛈️", "395": "⛈️",
+}
+
+// defaultIcon is used for the weather codes missing from conditionIcons.
+const defaultIcon = "🌡️"
+
+// j1Response is the part of the wttr.in "j1" JSON format used by Fetch.
+type j1Response struct {
+	CurrentCondition []struct {
+		TempC       string `json:"temp_C"`
+		WeatherCode string `json:"weatherCode"`
+		WeatherDesc []struct {
+			Value string `json:"value"`
+		} `json:"weatherDesc"`
+	} `json:"current_condition"`
+}
+
+// Fetch returns the current weather of a location from wttr.in.
+// An empty location lets the service find it from the IP address.
+func Fetch(location string) (WeatherInfo, error) {
+	client := &http.Client{Timeout: Timeout}
+	resp, err := client.Get(BaseURL + url.PathEscape(location) + "?format=j1")
+	if err != nil {
+		return WeatherInfo{}, fmt.Errorf("failed to fetch weather: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return WeatherInfo{}, fmt.Errorf("failed to fetch weather: %s", resp.Status)
+	}
+
+	var data j1Response
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return WeatherInfo{}, fmt.Errorf("failed to decode weather: %w", err)
+	}
+	if len(data.CurrentCondition) == 0 {
+		return WeatherInfo{}, fmt.Errorf("failed to decode weather: no current condition")
+	}
+
+	current := data.CurrentCondition[0]
+	tempC, err := strconv.Atoi(current.TempC)
+	if err != nil {
+		return WeatherInfo{}, fmt.Errorf("failed to decode weather temperature %q: %w", current.TempC, err)
+	}
+	info := WeatherInfo{TempC: tempC, Icon: defaultIcon}
+	if len(current.WeatherDesc) > 0 {
+		info.Condition = current.WeatherDesc[0].Value
+	}
+	if icon, ok := conditionIcons[current.WeatherCode]; ok {
+		info.Icon = icon
+	}
+	return info, nil
+}
@@ -0,0 +1,63 @@
+package weather
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetch(t *testing.T) {
+	origBaseURL, origTimeout := BaseURL, Timeout
+	defer func() { BaseURL, Timeout = origBaseURL, origTimeout }()
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		switch r.URL.Path {
+		case "/", "/Rome":
+			w.Write([]byte(`{"current_condition": [{"temp_C": "22", "weatherCode": "116", "weatherDesc": [{"value": "Partly cloudy"}]}]}`))
+		case "/Atlantis":
+			w.Write([]byte(`{"current_condition": [{"temp_C": "-3", "weatherCode": "999", "weatherDesc": [{"value": "Unknown"}]}]}`))
+		case "/Slow":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"current_condition": []}`))
+		case "/Empty":
+			w.Write([]byte(`{"current_condition": []}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	BaseURL = server.URL + "/"
+
+	// Test case 1: The current condition of the location
+	info, err := Fetch("Rome")
+	assert.NoError(t, err)
+	assert.Equal(t, WeatherInfo{Condition: "Partly cloudy", TempC: 22, Icon: "🌤️"}, info)
+	assert.Equal(t, "🌤️ 22°C", info.String())
+	assert.Equal(t, "/Rome?format=j1", requested)
+
+	// Test case 2: An empty location is detected by the service
+	_, err = Fetch("")
+	assert.NoError(t, err)
+	assert.Equal(t, "/?format=j1", requested)
+
+	// Test case 3: Unknown weather codes use the default icon
+	info, err = Fetch("Atlantis")
+	assert.NoError(t, err)
+	assert.Equal(t, "🌡️ -3°C", info.String())
+
+	// Test case 4: Errors
+	_, err = Fetch("Nowhere")
+	assert.ErrorContains(t, err, "404 Not Found")
+	_, err = Fetch("Empty")
+	assert.ErrorContains(t, err, "no current condition")
+
+	// Test case 5: Timeout
+	Timeout = 50 * time.Millisecond
+	_, err = Fetch("Slow")
+	assert.ErrorContains(t, err, "failed to fetch weather")
+}